@@ -21,63 +21,154 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/net"
+	"golang.org/x/time/rate"
 )
 
+// DefaultRequestTimeout is the default timeout for a single request to a
+// single admin host.
+const DefaultRequestTimeout = 10 * time.Second
+
+// DefaultMaxResponseBytes is the default limit on how much of a single
+// response body the client reads.
+const DefaultMaxResponseBytes = 64 << 20
+
 // AdminAPI is a client to interact with Redpanda's admin server.
 type AdminAPI struct {
-	urls   []string
-	client *http.Client
+	urls      []string
+	client    *http.Client
+	tlsConfig *tls.Config
+
+	headers        http.Header
+	tokenProvider  func() (string, error)
+	basicAuth      *basicAuth
+	fastest        bool
+	controllerOnly bool
+	hook           func(RequestInfo)
+	metrics        Metricer
+	tracer         Tracer
+	limiter        *rate.Limiter
+	sockets        map[string]string // placeholder host:port => socket path
+	insecure       bool
+	proxy          string
+	srv            *srvDiscovery
+	autoRefresh    time.Duration
+	refreshedAt    time.Time
+	maxBody        int64
+	warnOnce       sync.Once
+
+	strategy HostStrategy
+	next     int
+	cooldown time.Duration
+	failedAt map[string]time.Time
+
+	nodeHosts map[int]string // node ID => host, see hostForNode
+
+	mu      sync.Mutex
+	timeout time.Duration
+	retry   retryPolicy
 }
 
 // NewAdminAPI returns client that talks to each of the input URLs.
 //
-// If tlsConfig is non-nil, the client talks to the URLs over https with the
-// given tls configuration.
-func NewAdminAPI(urls []string, tlsConfig *tls.Config) (*AdminAPI, error) {
-	if len(urls) == 0 {
+// If tlsConfig is non-nil, or if the WithTLS option is used, the client talks
+// to the URLs over https with the given tls configuration.
+//
+// A URL of the form unix:///path/to/admin.sock talks plain http over the
+// given unix domain socket.
+//
+// Requests go through the proxy in the HTTP_PROXY or HTTPS_PROXY environment
+// variables, unless the host is exempted by NO_PROXY; see WithProxy.
+func NewAdminAPI(
+	urls []string, tlsConfig *tls.Config, opts ...Opt,
+) (*AdminAPI, error) {
+	a := &AdminAPI{
+		urls:      make([]string, len(urls)),
+		client:    new(http.Client),
+		tlsConfig: tlsConfig,
+		headers:   make(http.Header),
+		metrics:   nopMetricer{},
+		tracer:    nopTracer{},
+		timeout:   DefaultRequestTimeout,
+		cooldown:  DefaultHostCooldown,
+		failedAt:  make(map[string]time.Time),
+		sockets:   make(map[string]string),
+		maxBody:   DefaultMaxResponseBytes,
+		nodeHosts: make(map[int]string),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if len(urls) == 0 && a.srv == nil {
 		return nil, errors.New("at least one url is required for the admin api")
 	}
-
-	a := &AdminAPI{
-		urls:   make([]string, len(urls)),
-		client: &http.Client{Timeout: 10 * time.Second},
+	if a.tokenProvider != nil && a.basicAuth != nil {
+		return nil, errors.New("unable to use both bearer token and basic authentication for the admin api")
 	}
-	if tlsConfig != nil {
-		a.client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	if a.insecure {
+		// We clone rather than modify the config, since it may be shared
+		// with other clients.
+		if a.tlsConfig == nil {
+			a.tlsConfig = new(tls.Config)
+		} else {
+			a.tlsConfig = a.tlsConfig.Clone()
+		}
+		a.tlsConfig.InsecureSkipVerify = true
 	}
-
 	for i, u := range urls {
-		scheme, host, err := net.ParseHostMaybeScheme(u)
+		if strings.HasPrefix(u, unixScheme) {
+			socketURL, err := a.addSocket(i, u)
+			if err != nil {
+				return nil, err
+			}
+			a.urls[i] = socketURL
+			continue
+		}
+		hostURL, err := a.hostURL(u)
 		if err != nil {
 			return nil, err
 		}
-		switch scheme {
-		case "", "http":
-			scheme = "http"
-			if tlsConfig != nil {
-				scheme = "https"
-			}
-		case "https":
-		default:
-			return nil, fmt.Errorf("unrecognized scheme %q in host %q", scheme, u)
-		}
-		a.urls[i] = fmt.Sprintf("%s://%s", scheme, host)
+		a.urls[i] = hostURL
 	}
+	proxy, err := a.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	// We start from the default transport for its connection pooling and
+	// timeouts.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = a.tlsConfig
+	transport.DialContext = a.dialContext()
+	transport.Proxy = proxy
+	a.client.Transport = transport
 
 	return a, nil
 }
 
-func (a *AdminAPI) urlsWithPath(path string) []string {
-	urls := make([]string, len(a.urls))
-	for i := 0; i < len(a.urls); i++ {
-		urls[i] = fmt.Sprintf("%s%s", a.urls[i], path)
+// hostURL returns the scheme and host of the given host, which may or may not
+// have an http or https scheme. Hosts without a scheme use https if the client
+// has a tls configuration.
+func (a *AdminAPI) hostURL(u string) (string, error) {
+	scheme, host, err := net.ParseHostMaybeScheme(u)
+	if err != nil {
+		return "", err
 	}
-	return urls
+	switch scheme {
+	case "", "http":
+		scheme = "http"
+		if a.tlsConfig != nil {
+			scheme = "https"
+		}
+	case "https":
+	default:
+		return "", fmt.Errorf("unrecognized scheme %q in host %q", scheme, u)
+	}
+	return fmt.Sprintf("%s://%s", scheme, host), nil
 }
 
 // rng is a package-scoped, mutex guarded, seeded *rand.Rand.
@@ -93,33 +184,83 @@ var rng = func() func(int) int {
 
 // sendAny sends a single request to one of the client's urls and unmarshals
 // the body into into, which is expected to be a pointer to a struct.
-func (a *AdminAPI) sendAny(method, path string, body, into interface{}) error {
-	pick := rng(len(a.urls))
-	fmt.Println(pick)
-	url := a.urls[pick] + path
-	res, err := a.sendAndReceive(context.Background(), method, url, body)
+//
+// If a host fails to respond, or responds with a server error, the request is
+// retried against the next host. Hosts that recently failed are tried last.
+//
+// If the client was built WithFastestHost, GET requests are instead sent to
+// every url concurrently and the first successful response is used.
+func (a *AdminAPI) sendAny(
+	ctx context.Context, method, path string, body, into interface{},
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx = ensureCorrelationID(ctx)
+	if err := a.discover(ctx); err != nil {
+		return err
+	}
+	if a.controllerOnly && method != http.MethodGet {
+		return a.sendToController(ctx, method, path, body, into)
+	}
+	if a.fastest && method == http.MethodGet {
+		return a.sendRace(ctx, method, path, body, into)
+	}
+	host, res, err := a.sendFailover(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
-	return maybeUnmarshalRespInto(method, url, res, into)
+	return maybeUnmarshalRespInto(method, host+path, res, into)
+}
+
+// sendFailover sends a request to the client's hosts in hostOrder until one
+// of them responds without a host failure, and returns that host and its
+// response. The caller must close the response body.
+func (a *AdminAPI) sendFailover(
+	ctx context.Context, method, path string, body interface{},
+) (string, *http.Response, error) {
+	var errs *multierror.Error
+	for _, host := range a.hostOrder() {
+		res, err := a.sendAndReceive(ctx, method, host, path, body)
+		if err == nil {
+			a.markHealthy(host)
+			return host, res, nil
+		}
+		if ctx.Err() != nil || !isHostFailure(err) {
+			return "", nil, err
+		}
+		a.markFailed(host)
+		errs = multierror.Append(errs, err)
+	}
+	return "", nil, errs.ErrorOrNil()
 }
 
 // sendOne sends a request with sendAndReceive and unmarshals the body into
 // into, which is expected to be a pointer to a struct.
-func (a *AdminAPI) sendOne(method, path string, body, into interface{}) error {
-	if len(a.urls) != 1 {
-		return fmt.Errorf("unable to issue a single-admin-endpoint request to %d admin endpoints", len(a.urls))
+func (a *AdminAPI) sendOne(
+	ctx context.Context, method, path string, body, into interface{},
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx = ensureCorrelationID(ctx)
+	if err := a.discover(ctx); err != nil {
+		return err
+	}
+	urls := a.currentURLs()
+	if len(urls) != 1 {
+		return fmt.Errorf("unable to issue a single-admin-endpoint request to %d admin endpoints", len(urls))
 	}
-	url := a.urls[0] + path
-	res, err := a.sendAndReceive(context.Background(), method, url, body)
+	host := urls[0]
+	res, err := a.sendAndReceive(ctx, method, host, path, body)
 	if err != nil {
 		return err
 	}
-	return maybeUnmarshalRespInto(method, url, res, into)
+	return maybeUnmarshalRespInto(method, host+path, res, into)
 }
 
-// sendAll sends a request to all URLs in the admin client. The first successful
-// response will be unmarshaled into into if it is non-nil.
+// sendToLeader sends a request to all URLs in the admin client. The first
+// successful response will be unmarshaled into into if it is non-nil.
 //
 // As of v21.4.15, the Redpanda admin API doesn't do request forwarding, which
 // means that some requests (such as the ones made to /users) will fail unless
@@ -127,30 +268,134 @@ func (a *AdminAPI) sendOne(method, path string, body, into interface{}) error {
 // each node, and of those requests at least one should succeed.
 // FIXME (@david): when https://github.com/vectorizedio/redpanda/issues/1265
 // is fixed.
-func (a *AdminAPI) sendAll(method, path string, body, into interface{}) error {
+func (a *AdminAPI) sendToLeader(
+	ctx context.Context, method, path string, body, into interface{},
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx = ensureCorrelationID(ctx)
+	if err := a.discover(ctx); err != nil {
+		return err
+	}
+	if a.controllerOnly && method != http.MethodGet {
+		return a.sendToController(ctx, method, path, body, into)
+	}
+	return a.sendRace(ctx, method, path, body, into)
+}
+
+// sendAll sends a request to all URLs in the admin client and waits for each
+// of them. This is for requests that only the cluster leader handles, which
+// the admin API doesn't forward: the followers reject them, so the request
+// succeeds if any host accepts it. If every host fails, the returned error is
+// a *SendAllError that reports the result of each host. The first successful
+// response, in host order, is unmarshaled into into if it is non-nil.
+func (a *AdminAPI) sendAll(
+	ctx context.Context, method, path string, body, into interface{},
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx = ensureCorrelationID(ctx)
+	if err := a.discover(ctx); err != nil {
+		return err
+	}
+	if a.controllerOnly && method != http.MethodGet {
+		return a.sendToController(ctx, method, path, body, into)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		urls = a.currentURLs()
+		ress = make([]*http.Response, len(urls))
+		errs = make([]error, len(urls))
+	)
+	for i, host := range urls {
+		i, host := i, host
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ress[i], errs[i] = a.sendAndReceive(ctx, method, host, path, body)
+		}()
+	}
+	wg.Wait()
+
+	var (
+		perHost  = make(map[string]error, len(urls))
+		accepted bool
+	)
+	for i, host := range urls {
+		res, err := ress[i], errs[i]
+		if err == nil {
+			if into != nil {
+				err = maybeUnmarshalRespInto(method, host+path, res, into)
+				into = nil
+			} else {
+				res.Body.Close()
+			}
+		}
+		perHost[host] = err
+		accepted = accepted || err == nil
+	}
+	if !accepted {
+		return &SendAllError{Method: method, Path: path, perHost: perHost}
+	}
+	return nil
+}
+
+// sendRace sends a request to all URLs concurrently. Once a request succeeds,
+// all other requests are canceled and the successful response is unmarshaled
+// into into if it is non-nil. If every request fails, the errors from each
+// host are combined.
+func (a *AdminAPI) sendRace(
+	ctx context.Context, method, path string, body, into interface{},
+) error {
 	var (
 		once   sync.Once
 		resURL string
 		res    *http.Response
 		grp    multierror.Group
 
-		ctx, cancel = context.WithCancel(context.Background())
+		hosts   = a.currentURLs()
+		cancels = make([]context.CancelFunc, len(hosts))
 	)
 
-	defer cancel()
-	for _, url := range a.urlsWithPath(path) {
-		myURL := url
+	// Each request has its own context so that the winning request can
+	// cancel every other request without canceling itself, which would
+	// break reading its response body.
+	ctxs := make([]context.Context, len(hosts))
+	for i := range hosts {
+		ctxs[i], cancels[i] = context.WithCancel(ctx)
+	}
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	for i, host := range hosts {
+		i, myURL := i, host+path
 		grp.Go(func() error {
-			myRes, err := a.sendAndReceive(ctx, method, myURL, body)
+			myRes, err := a.sendAndReceive(ctxs[i], method, hosts[i], path, body)
 			if err != nil {
 				return err
 			}
-			cancel() // kill all other requests
 
 			// Only one request should be successful, but for
 			// paranoia, we guard keeping the first successful
 			// response.
-			once.Do(func() { resURL, res = myURL, myRes })
+			var kept bool
+			once.Do(func() {
+				resURL, res, kept = myURL, myRes, true
+				for j, cancel := range cancels {
+					if j != i {
+						cancel() // kill all other requests
+					}
+				}
+			})
+			if !kept {
+				myRes.Body.Close()
+			}
 			return nil
 		})
 	}
@@ -170,6 +415,7 @@ func (a *AdminAPI) sendAll(method, path string, body, into interface{}) error {
 func maybeUnmarshalRespInto(
 	method, url string, resp *http.Response, into interface{},
 ) error {
+	defer resp.Body.Close()
 	if into == nil {
 		return nil
 	}
@@ -190,31 +436,117 @@ func maybeUnmarshalRespInto(
 	return nil
 }
 
-// sendAndReceive sends a request and returns the response. If body is
-// non-nil, this json encodes the body and sends it with the request.
+// sendAndReceive sends a request to path on the given host and returns the
+// response. If body is non-nil, this json encodes the body and sends it with
+// the request; a rawBody is sent as is.
+//
+// Each attempt, including reading the response body, is bounded by the
+// client's request timeout. If the client has a retry policy, attempts that
+// failed transiently are retried. The caller must close the response body.
 func (a *AdminAPI) sendAndReceive(
-	ctx context.Context, method, url string, body interface{},
+	ctx context.Context, method, host, path string, body interface{},
 ) (*http.Response, error) {
-	var r io.Reader
-	if body != nil {
-		bs, err := json.Marshal(body)
+	url := host + path
+	var bs []byte
+	switch body := body.(type) {
+	case nil:
+	case rawBody:
+		bs = body
+	default:
+		var err error
+		bs, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("unable to encode request body for %s %s: %w", method, url, err) // should not happen
 		}
-		r = bytes.NewBuffer(bs)
 	}
 
+	id := CorrelationID(ctx)
+	endpoint := endpointTemplate(path)
+	retry := a.retryPolicy()
+	for attempt := 0; ; attempt++ {
+		if a.limiter != nil {
+			if err := a.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		spanCtx, span := a.tracer.StartSpan(ctx, endpoint)
+		start := time.Now()
+		res, err := a.sendOnce(spanCtx, method, url, bs)
+		var status int
+		if err == nil {
+			status = res.StatusCode
+			if status/100 != 2 {
+				err = statusError(method, url, id, res)
+			}
+		}
+		elapsed := time.Since(start)
+		a.metrics.ObserveRequest(endpoint, status, elapsed)
+		info := RequestInfo{
+			Host:          host,
+			Method:        method,
+			Path:          path,
+			StatusCode:    status,
+			Duration:      elapsed,
+			Attempt:       attempt + 1,
+			CorrelationID: id,
+			Err:           err,
+		}
+		span.End(info)
+		a.observe(info)
+		if err == nil {
+			return res, nil
+		}
+		if attempt >= retry.retries || !isRetryable(res, err) {
+			return nil, err
+		}
+		if err := sleepCtx(ctx, retry.delay(attempt, res)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendOnce issues a single request bounded by the client's request timeout.
+// The response is returned regardless of its status code.
+func (a *AdminAPI) sendOnce(
+	ctx context.Context, method, url string, body []byte,
+) (*http.Response, error) {
+	cancel := func() {}
+	if timeout := a.requestTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
 	req, err := http.NewRequestWithContext(ctx, method, url, r)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
+	// Custom headers are added first so that they cannot override the
+	// headers we set ourselves.
+	for k, vs := range a.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
 	const applicationJson = "application/json"
 	req.Header.Set("Content-Type", applicationJson)
 	req.Header.Set("Accept", applicationJson)
+	req.Header.Set("Accept-Encoding", "gzip")
+	if id := CorrelationID(ctx); id != "" {
+		req.Header.Set(CorrelationIDHeader, id)
+	}
+	if err := a.authorize(req); err != nil {
+		cancel()
+		return nil, err
+	}
 
 	res, err := a.client.Do(req)
 	if err != nil {
+		cancel()
 		// When the server expects a TLS connection, but the TLS config isn't
 		// set/ passed, The client returns an error like
 		// Get "http://localhost:9644/v1/security/users": EOF
@@ -224,15 +556,83 @@ func (a *AdminAPI) sendAndReceive(
 		}
 		return nil, err
 	}
+	res.Body = &cancelOnClose{res.Body, cancel}
+	maybeDecompress(res)
+	if a.maxBody > 0 {
+		res.Body = &limitedBody{ReadCloser: res.Body, max: a.maxBody}
+	}
+	return res, nil
+}
 
-	if res.StatusCode/100 != 2 {
-		resBody, err := ioutil.ReadAll(res.Body)
-		status := http.StatusText(res.StatusCode)
+// authorize sets the request's credentials, if the client has any.
+func (a *AdminAPI) authorize(req *http.Request) error {
+	if a.tokenProvider != nil {
+		token, err := a.tokenProvider()
 		if err != nil {
-			return nil, fmt.Errorf("request %s %s failed: %s, unable to read body: %w", method, url, status, err)
+			return fmt.Errorf("unable to get a bearer token for %s %s: %w", req.Method, req.URL, err)
 		}
-		return nil, fmt.Errorf("request %s %s failed: %s, body: %q", method, url, status, resBody)
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if a.basicAuth != nil {
+		req.SetBasicAuth(a.basicAuth.user, a.basicAuth.password)
+	}
+	return nil
+}
+
+type basicAuth struct {
+	user     string
+	password string
+}
+
+// statusError reads and closes the body of a non-2xx response and returns an
+// *HTTPResponseError describing the failed request.
+func statusError(method, url, correlationID string, res *http.Response) error {
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	return &HTTPResponseError{
+		Method:        method,
+		URL:           url,
+		StatusCode:    res.StatusCode,
+		Body:          resBody,
+		CorrelationID: correlationID,
+		readErr:       err,
 	}
+}
 
-	return res, nil
+// cancelOnClose cancels the context of a request once its response body is
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+// limitedBody fails reads with ErrResponseTooLarge once more than max bytes
+// have been read. This is applied after decompression, so it bounds what we
+// hold in memory rather than what was sent over the wire.
+type limitedBody struct {
+	io.ReadCloser
+	max  int64
+	read int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.read > l.max {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, l.max)
+	}
+	// We allow reading one byte past max so that a body of exactly max
+	// bytes is not an error.
+	if left := l.max + 1 - l.read; int64(len(p)) > left {
+		p = p[:left]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, l.max)
+	}
+	return n, err
 }
@@ -10,15 +10,30 @@
 package admin
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"math/big"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/Shopify/sarama"
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -62,7 +77,7 @@ func TestCreateUser(t *testing.T) {
 
 	adminClient, err := NewAdminAPI(urls, nil)
 	require.NoError(t, err)
-	err = adminClient.CreateUser(username, password)
+	err = adminClient.CreateUser(context.Background(), username, password, ScramSha256)
 	require.NoError(t, err)
 }
 
@@ -95,7 +110,7 @@ func TestDeleteUser(t *testing.T) {
 
 	adminClient, err := NewAdminAPI(urls, nil)
 	require.NoError(t, err)
-	err = adminClient.DeleteUser(username)
+	err = adminClient.DeleteUser(context.Background(), username)
 	require.NoError(t, err)
 }
 
@@ -124,7 +139,587 @@ func TestListUsers(t *testing.T) {
 
 	adminClient, err := NewAdminAPI(urls, nil)
 	require.NoError(t, err)
-	users, err := adminClient.ListUsers()
+	users, err := adminClient.ListUsers(context.Background())
 	require.NoError(t, err)
-	require.Exactly(t, []string{"Joss", "lola", "jeff", "tobias"}, users)
+	require.Exactly(t, []string{"Joss", "jeff", "lola", "tobias"}, users)
+}
+
+func TestCancelledContext(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			w.Write([]byte(`[]`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL, ts.URL}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = adminClient.Brokers(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = adminClient.Broker(ctx, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	err = adminClient.DecommissionBroker(ctx, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = adminClient.ListUsers(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, atomic.LoadInt32(&hits))
+}
+
+func TestRequestTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+	slow := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-hang:
+			case <-r.Context().Done():
+			}
+		}),
+	)
+	defer slow.Close()
+
+	var failedHits int32
+	failed := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&failedHits, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer failed.Close()
+
+	ok := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`["Joss"]`))
+		}),
+	)
+	defer ok.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{slow.URL, failed.URL},
+		nil,
+		WithTimeout(50*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	err = adminClient.DecommissionBroker(context.Background(), 1)
+	require.Error(t, err)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Contains(t, err.Error(), http.StatusText(http.StatusInternalServerError))
+	require.EqualValues(t, 1, atomic.LoadInt32(&failedHits))
+
+	adminClient, err = NewAdminAPI([]string{slow.URL, ok.URL}, nil)
+	require.NoError(t, err)
+	adminClient.SetRequestTimeout(50 * time.Millisecond)
+	users, err := adminClient.ListUsers(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"Joss"}, users)
+}
+
+func TestHTTPResponseError(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v1/brokers/1":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message": "broker not found"}`))
+			default:
+				w.WriteHeader(http.StatusConflict)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	_, err = adminClient.Broker(context.Background(), 1)
+	require.True(t, IsNotFound(err))
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he))
+	require.Equal(t, http.MethodGet, he.Method)
+	require.Equal(t, ts.URL+"/v1/brokers/1", he.URL)
+	require.Equal(t, http.StatusNotFound, he.StatusCode)
+	require.Equal(t, []byte(`{"message": "broker not found"}`), he.Body)
+
+	err = adminClient.DecommissionBroker(context.Background(), 2)
+	require.False(t, IsNotFound(err))
+	require.True(t, errors.As(err, &he))
+	require.Equal(t, http.StatusConflict, he.StatusCode)
+}
+
+// testCerts returns a CA pool, a server certificate for localhost and a
+// client certificate, both issued by the CA.
+func testCerts(t *testing.T) (*x509.CertPool, tls.Certificate, tls.Certificate) {
+	newKey := func() *ecdsa.PrivateKey {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+		require.NoError(t, err)
+		return k
+	}
+	caKey := newKey()
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(crand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, tmpl *x509.Certificate) tls.Certificate {
+		key := newKey()
+		tmpl.SerialNumber = big.NewInt(serial)
+		tmpl.NotBefore = caTmpl.NotBefore
+		tmpl.NotAfter = caTmpl.NotAfter
+		der, err := x509.CreateCertificate(crand.Reader, tmpl, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+	server := issue(2, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	client := issue(3, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "rpk"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return pool, server, client
+}
+
+func TestWithTLS(t *testing.T) {
+	pool, serverCert, clientCert := testCerts(t)
+
+	sni := make(chan string, 1)
+	ts := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Len(t, r.TLS.PeerCertificates, 1)
+			require.Equal(t, "rpk", r.TLS.PeerCertificates[0].Subject.CommonName)
+			w.Write([]byte(`{"node_id": 1, "num_cores": 2}`))
+		}),
+	)
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			sni <- hello.ServerName
+			return nil, nil
+		},
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	_, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	require.NoError(t, err)
+	host := net.JoinHostPort("localhost", port)
+
+	adminClient, err := NewAdminAPI([]string{host}, nil, WithTLS(clientCert, pool))
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://" + host}, adminClient.urls)
+
+	b, err := adminClient.Broker(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, Broker{NodeID: 1, NumCores: 2}, b)
+	require.Equal(t, "localhost", <-sni)
+
+	// Without the client certificate, the server rejects the handshake.
+	adminClient, err = NewAdminAPI([]string{host}, &tls.Config{RootCAs: pool})
+	require.NoError(t, err)
+	_, err = adminClient.Broker(context.Background(), 1)
+	require.Error(t, err)
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"node_id": 1}`))
+		}),
+	)
+	defer ts.Close()
+
+	// The test server's certificate is self-signed, so verification fails
+	// by default.
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	_, err = adminClient.Broker(context.Background(), 1)
+	require.Error(t, err)
+
+	var warnings []string
+	adminClient, err = NewAdminAPI(
+		[]string{ts.Listener.Addr().String()},
+		nil,
+		WithInsecureSkipVerify(true),
+		WithRequestHook(func(info RequestInfo) {
+			warnings = append(warnings, info.Warning)
+		}),
+	)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = adminClient.Broker(context.Background(), 1)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{insecureWarning, ""}, warnings)
+}
+
+func TestBearerToken(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		tokens []string
+	)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			tokens = append(tokens, r.Header.Get("Authorization"))
+			n := len(tokens)
+			mu.Unlock()
+			if n == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil, WithBearerToken("secret"))
+	require.NoError(t, err)
+	_, err = adminClient.Broker(context.Background(), 1)
+	require.Error(t, err)
+	require.Equal(t, []string{"Bearer secret"}, tokens)
+
+	var calls int
+	adminClient, err = NewAdminAPI(
+		[]string{ts.URL},
+		nil,
+		WithRetries(1, time.Millisecond, time.Millisecond),
+		WithTokenProvider(func() (string, error) {
+			calls++
+			return fmt.Sprintf("token-%d", calls), nil
+		}),
+	)
+	require.NoError(t, err)
+	mu.Lock()
+	tokens = nil
+	mu.Unlock()
+	_, err = adminClient.Broker(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, tokens)
+
+	errProvider := errors.New("expired credentials")
+	adminClient, err = NewAdminAPI(
+		[]string{ts.URL},
+		nil,
+		WithTokenProvider(func() (string, error) { return "", errProvider }),
+	)
+	require.NoError(t, err)
+	mu.Lock()
+	tokens = nil
+	mu.Unlock()
+	err = adminClient.DecommissionBroker(context.Background(), 1)
+	require.ErrorIs(t, err, errProvider)
+	require.Empty(t, tokens)
+}
+
+func TestBasicAuth(t *testing.T) {
+	pool, serverCert, _ := testCerts(t)
+
+	var hits int32
+	newServer := func() *httptest.Server {
+		ts := httptest.NewUnstartedServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				user, pass, ok := r.BasicAuth()
+				require.True(t, ok)
+				require.Equal(t, "admin", user)
+				require.Equal(t, "hunter2", pass)
+				w.WriteHeader(http.StatusOK)
+			}),
+		)
+		ts.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert}}
+		ts.StartTLS()
+		return ts
+	}
+	var hosts []string
+	for i := 0; i < 3; i++ {
+		ts := newServer()
+		defer ts.Close()
+		_, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+		require.NoError(t, err)
+		hosts = append(hosts, net.JoinHostPort("localhost", port))
+	}
+
+	adminClient, err := NewAdminAPI(
+		hosts,
+		&tls.Config{RootCAs: pool},
+		WithBasicAuth("admin", "hunter2"),
+	)
+	require.NoError(t, err)
+	err = adminClient.RecommissionBroker(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotZero(t, atomic.LoadInt32(&hits))
+
+	_, err = NewAdminAPI(
+		hosts,
+		nil,
+		WithBasicAuth("admin", "hunter2"),
+		WithBearerToken("secret"),
+	)
+	require.Error(t, err)
+}
+
+func TestWithHeader(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, []string{"rpk-automation"}, r.Header.Values("X-Request-Source"))
+			require.Equal(t, []string{"abc", "def"}, r.Header.Values("X-Correlation"))
+			require.Equal(t, []string{"application/json"}, r.Header.Values("Content-Type"))
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{ts.URL},
+		nil,
+		WithHeader("X-Request-Source", "rpk-automation"),
+		WithHeader("X-Correlation", "abc"),
+		WithHeader("x-correlation", "def"),
+		WithHeader("Content-Type", "text/plain"),
+	)
+	require.NoError(t, err)
+	err = adminClient.CreateUser(context.Background(), "Joss", "momorocks", ScramSha256)
+	require.NoError(t, err)
+}
+
+func TestWithFastestHost(t *testing.T) {
+	canceled := make(chan struct{})
+	slow := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			close(canceled)
+		}),
+	)
+	defer slow.Close()
+	failed := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer failed.Close()
+	ok := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"node_id": 2, "num_cores": 4}`))
+		}),
+	)
+	defer ok.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{slow.URL, failed.URL, ok.URL},
+		nil,
+		WithFastestHost(true),
+	)
+	require.NoError(t, err)
+	b, err := adminClient.Broker(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, Broker{NodeID: 2, NumCores: 4}, b)
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request to the slow host was not canceled")
+	}
+
+	adminClient, err = NewAdminAPI(
+		[]string{failed.URL, failed.URL, failed.URL},
+		nil,
+		WithFastestHost(true),
+	)
+	require.NoError(t, err)
+	_, err = adminClient.Broker(context.Background(), 2)
+	var merr *multierror.Error
+	require.True(t, errors.As(err, &merr))
+	require.Len(t, merr.Errors, 3)
+}
+
+func TestWithMaxResponseBytes(t *testing.T) {
+	body := []byte(`{"node_id": 1}`)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				w.Write(body)
+				return
+			}
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write(bytes.Repeat([]byte("<html>"), 100))
+		}),
+	)
+	defer ts.Close()
+
+	for _, test := range []struct {
+		max    int64
+		expErr bool
+	}{
+		{max: int64(len(body))},
+		{max: int64(len(body)) - 1, expErr: true},
+		{max: 0},
+	} {
+		adminClient, err := NewAdminAPI([]string{ts.URL}, nil, WithMaxResponseBytes(test.max))
+		require.NoError(t, err)
+		_, err = adminClient.Broker(context.Background(), 1)
+		if test.expErr {
+			require.True(t, errors.Is(err, ErrResponseTooLarge), "got %v", err)
+		} else {
+			require.NoError(t, err)
+		}
+	}
+
+	// Error bodies are bounded too.
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil, WithMaxResponseBytes(10))
+	require.NoError(t, err)
+	err = adminClient.DeleteUser(context.Background(), "Joss")
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he))
+	require.True(t, errors.Is(err, ErrResponseTooLarge), "got %v", err)
+}
+
+func TestSendAllPerHost(t *testing.T) {
+	var hits int32
+	leader := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer leader.Close()
+	newFollower := func(status int) *httptest.Server {
+		return httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				w.WriteHeader(status)
+			}),
+		)
+	}
+	follower1 := newFollower(http.StatusBadRequest)
+	defer follower1.Close()
+	follower2 := newFollower(http.StatusNotFound)
+	defer follower2.Close()
+
+	// The followers reject the request, which only the leader handles.
+	adminClient, err := NewAdminAPI([]string{follower1.URL, leader.URL, follower2.URL}, nil)
+	require.NoError(t, err)
+	require.NoError(t, adminClient.DecommissionBroker(context.Background(), 1))
+	require.EqualValues(t, 3, atomic.LoadInt32(&hits), "every host should be reached")
+	require.NoError(t, adminClient.RecommissionBroker(context.Background(), 1))
+
+	adminClient, err = NewAdminAPI([]string{follower1.URL, follower2.URL}, nil)
+	require.NoError(t, err)
+	err = adminClient.DecommissionBroker(context.Background(), 1)
+	var se *SendAllError
+	require.True(t, errors.As(err, &se))
+	perHost := se.PerHost()
+	require.Len(t, perHost, 2)
+	require.True(t, hasStatus(perHost[follower1.URL], http.StatusBadRequest))
+	require.True(t, IsNotFound(perHost[follower2.URL]))
+	require.True(t, IsNotFound(err))
+	require.Contains(t, err.Error(), "failed on 2 of 2 hosts")
+}
+
+func TestCreateUserMechanism(t *testing.T) {
+	var algorithm string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var u newUser
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&u))
+			algorithm = u.Algorithm
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, adminClient.CreateUser(ctx, "Joss", "momorocks", ScramSha512))
+	require.Equal(t, sarama.SASLTypeSCRAMSHA512, algorithm)
+
+	err = adminClient.CreateUser(ctx, "Joss", "momorocks", "PLAIN")
+	require.Error(t, err)
+}
+
+func TestCreateUserExists(t *testing.T) {
+	const password = "momorocks"
+	leader := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message": "user exists", "code": 409}`))
+		}),
+	)
+	defer leader.Close()
+	follower := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer follower.Close()
+
+	var infos []string
+	var mu sync.Mutex
+	adminClient, err := NewAdminAPI(
+		[]string{follower.URL, leader.URL},
+		nil,
+		WithRequestHook(func(info RequestInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			infos = append(infos, fmt.Sprintf("%+v", info))
+		}),
+	)
+	require.NoError(t, err)
+	err = adminClient.CreateUser(context.Background(), "Joss", password, ScramSha256)
+	var ue *UserExistsError
+	require.True(t, errors.As(err, &ue), "got %v", err)
+	require.Equal(t, "Joss", ue.Username)
+
+	require.Len(t, infos, 2)
+	for _, info := range infos {
+		require.NotContains(t, info, password)
+	}
+	require.NotContains(t, err.Error(), password)
+}
+
+func TestDeleteUserNotFound(t *testing.T) {
+	const username = "a/b c?%"
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v1/security/users/a%2Fb%20c%3F%25", r.URL.EscapedPath())
+			require.Equal(t, "/v1/security/users/"+username, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer ts.Close()
+	follower := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer follower.Close()
+
+	adminClient, err := NewAdminAPI([]string{follower.URL, ts.URL}, nil)
+	require.NoError(t, err)
+	err = adminClient.DeleteUser(context.Background(), username)
+	var ue *UserNotFoundError
+	require.True(t, errors.As(err, &ue), "got %v", err)
+	require.Equal(t, username, ue.Username)
+	require.True(t, IsNotFound(err))
 }
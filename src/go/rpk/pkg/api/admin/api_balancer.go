@@ -0,0 +1,86 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	partitionBalancerStatusEndpoint = "/v1/cluster/partition_balancer/status"
+	partitionRebalanceEndpoint      = "/v1/partitions/rebalance"
+)
+
+// The statuses of the partition balancer.
+const (
+	// BalancerStatusOff is the status of a balancer that is disabled.
+	BalancerStatusOff = "off"
+	// BalancerStatusReady is the status of an idle balancer, which has
+	// nothing left to move.
+	BalancerStatusReady = "ready"
+	// BalancerStatusStarting is the status of a balancer that has not yet
+	// planned its first actions, such as right after a controller election.
+	BalancerStatusStarting = "starting"
+	// BalancerStatusInProgress is the status of a balancer that is moving
+	// partitions.
+	BalancerStatusInProgress = "in_progress"
+	// BalancerStatusStalled is the status of a balancer that found
+	// violations it can't fix, such as too few nodes to move replicas to.
+	BalancerStatusStalled = "stalled"
+)
+
+// BalancerStatus is the status returned from the Redpanda admin partition
+// balancer status endpoint.
+type BalancerStatus struct {
+	// Status is one of the BalancerStatus constants.
+	Status     string             `json:"status"`
+	Violations BalancerViolations `json:"violations"`
+	// SecondsSinceLastTick is how long ago the balancer last planned its
+	// actions.
+	SecondsSinceLastTick int `json:"seconds_since_last_tick"`
+	// CurrentReassignments is the number of partition movements that the
+	// balancer planned and that are not yet done.
+	CurrentReassignments int `json:"current_reassignments_count"`
+}
+
+// BalancerViolations are the nodes that violate the balancer's constraints,
+// whose partitions the balancer moves elsewhere.
+type BalancerViolations struct {
+	// UnavailableNodes are the nodes that have been down for longer than
+	// the balancer tolerates.
+	UnavailableNodes []int `json:"unavailable_nodes"`
+	// OverDiskLimitNodes are the nodes whose disk usage is over the
+	// balancer's limit.
+	OverDiskLimitNodes []int `json:"over_disk_limit_nodes"`
+}
+
+// InProgress returns whether the balancer is planning or moving partitions.
+func (s BalancerStatus) InProgress() bool {
+	switch s.Status {
+	case BalancerStatusStarting, BalancerStatusInProgress:
+		return true
+	}
+	return s.CurrentReassignments > 0
+}
+
+// PartitionBalancerStatus returns the status of the partition balancer.
+func (a *AdminAPI) PartitionBalancerStatus(ctx context.Context) (BalancerStatus, error) {
+	var s BalancerStatus
+	return s, a.sendAny(ctx, http.MethodGet, partitionBalancerStatusEndpoint, nil, &s)
+}
+
+// TriggerPartitionBalancer triggers a single, on-demand run of the partition
+// balancer, which moves partitions to even out the replicas and leaders
+// across the brokers. This works even if the continuous balancer is
+// disabled. Use PartitionBalancerStatus to follow the run.
+func (a *AdminAPI) TriggerPartitionBalancer(ctx context.Context) error {
+	return a.sendAny(ctx, http.MethodPost, partitionRebalanceEndpoint, nil, nil)
+}
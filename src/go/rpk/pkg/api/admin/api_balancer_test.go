@@ -0,0 +1,96 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionBalancer(t *testing.T) {
+	var triggers int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case partitionRebalanceEndpoint:
+				require.Equal(t, http.MethodPost, r.Method)
+				atomic.AddInt32(&triggers, 1)
+			case partitionBalancerStatusEndpoint:
+				require.Equal(t, http.MethodGet, r.Method)
+				w.Write([]byte(`{
+  "status": "in_progress",
+  "violations": {"unavailable_nodes": [2], "over_disk_limit_nodes": []},
+  "seconds_since_last_tick": 3,
+  "current_reassignments_count": 4
+}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, adminClient.TriggerPartitionBalancer(ctx))
+	require.EqualValues(t, 1, atomic.LoadInt32(&triggers))
+
+	s, err := adminClient.PartitionBalancerStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, BalancerStatus{
+		Status: BalancerStatusInProgress,
+		Violations: BalancerViolations{
+			UnavailableNodes:   []int{2},
+			OverDiskLimitNodes: []int{},
+		},
+		SecondsSinceLastTick: 3,
+		CurrentReassignments: 4,
+	}, s)
+	require.True(t, s.InProgress())
+}
+
+func TestBalancerStatusInProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   BalancerStatus
+		expected bool
+	}{
+		{
+			name:   "it should be idle when ready",
+			status: BalancerStatus{Status: BalancerStatusReady},
+		},
+		{
+			name:   "it should be idle when stalled",
+			status: BalancerStatus{Status: BalancerStatusStalled},
+		},
+		{
+			name:     "it should be in progress when starting",
+			status:   BalancerStatus{Status: BalancerStatusStarting},
+			expected: true,
+		},
+		{
+			name:     "it should be in progress while its reassignments finish",
+			status:   BalancerStatus{Status: BalancerStatusReady, CurrentReassignments: 1},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.status.InProgress())
+		})
+	}
+}
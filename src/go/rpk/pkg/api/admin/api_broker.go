@@ -10,53 +10,801 @@
 package admin
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
 )
 
 const brokersEndpoint = "/v1/brokers"
 
+// DefaultDecommissionPoll is the default interval at which
+// WaitForBrokerRemoved and WaitForBrokerActive poll the cluster.
+const DefaultDecommissionPoll = 3 * time.Second
+
+// MembershipStatus is the membership status of a broker in the cluster.
+type MembershipStatus string
+
+const (
+	MembershipActive   MembershipStatus = "active"
+	MembershipDraining MembershipStatus = "draining"
+	MembershipRemoved  MembershipStatus = "removed"
+)
+
+// UnmarshalJSON keeps any status string the server sends, including ones
+// newer than this client, and decodes anything that is not a string as an
+// empty status rather than failing to decode the whole broker.
+func (m *MembershipStatus) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		*m = ""
+		return nil
+	}
+	*m = MembershipStatus(s)
+	return nil
+}
+
 // Broker is the information returned from the Redpanda admin broker endpoints.
 type Broker struct {
-	NodeID           int    `json:"node_id"`
-	NumCores         int    `json:"num_cores"`
-	MembershipStatus string `json:"membership_status"`
+	NodeID           int              `json:"node_id" yaml:"node_id"`
+	NumCores         int              `json:"num_cores" yaml:"num_cores"`
+	MembershipStatus MembershipStatus `json:"membership_status" yaml:"membership_status"`
+
+	// IsAlive is whether the controller considers the broker alive. Older
+	// versions do not report liveness, in which case this is nil.
+	IsAlive *bool `json:"is_alive,omitempty" yaml:"is_alive,omitempty"`
+
+	// Maintenance is the maintenance mode status of the broker, if the
+	// cluster supports maintenance mode.
+	Maintenance *MaintenanceStatus `json:"maintenance_status,omitempty" yaml:"maintenance_status,omitempty"`
+
+	// DiskSpace is the usage of each of the broker's data disks. Older
+	// versions do not report disk usage, in which case this is empty.
+	DiskSpace []DiskSpace `json:"disk_space,omitempty" yaml:"disk_space,omitempty"`
+
+	// AdminAddress is the address of the broker's admin API, for
+	// versions that report it; otherwise, this is empty.
+	AdminAddress string `json:"admin_address,omitempty" yaml:"admin_address,omitempty"`
+
+	// Version is the Redpanda version the broker runs, for versions that
+	// report it; otherwise, this is empty.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Rack is the rack the broker is configured in, if any, for versions
+	// that report it; otherwise, this is empty.
+	Rack string `json:"rack,omitempty" yaml:"rack,omitempty"`
+}
+
+// BrokerHeader is the header of the table rows returned from Broker.Row.
+var BrokerHeader = []string{"Node ID", "Num Cores", "Membership Status", "Rack", "Version", "Disk"}
+
+// String returns a short description of the broker, e.g. "broker 3 (active,
+// 8 cores)".
+func (b Broker) String() string {
+	cores := fmt.Sprintf("%d cores", b.NumCores)
+	if b.NumCores == 1 {
+		cores = "1 core"
+	}
+	if b.MembershipStatus == "" {
+		return fmt.Sprintf("broker %d (%s)", b.NodeID, cores)
+	}
+	return fmt.Sprintf("broker %d (%s, %s)", b.NodeID, b.MembershipStatus, cores)
+}
+
+// Row returns the broker as a table row with the columns of BrokerHeader.
+// The disk column is the free space of each disk, or "-" if the broker does
+// not report disk usage. The rack and version are "-" if they're unknown.
+func (b Broker) Row() []string {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	disk := "-"
+	if len(b.DiskSpace) > 0 {
+		free := make([]string, 0, len(b.DiskSpace))
+		for _, d := range b.DiskSpace {
+			free = append(free, fmt.Sprintf("%s %.1f%% free", d.Path, d.FreePercent()))
+		}
+		disk = strings.Join(free, ", ")
+	}
+	return []string{
+		strconv.Itoa(b.NodeID),
+		strconv.Itoa(b.NumCores),
+		string(b.MembershipStatus),
+		orDash(b.Rack),
+		orDash(b.Version),
+		disk,
+	}
+}
+
+// BrokerList is a list of brokers, such as the one returned from Brokers:
+//
+//	bs, err := cl.Brokers(ctx)
+//	...
+//	err = admin.BrokerList(bs).ValidateUniformCores()
+type BrokerList []Broker
+
+// ValidateUniformCores returns a *NonUniformCoresError if some brokers have a
+// different number of cores than most brokers, which unevenly spreads the
+// load across the cluster. Removed brokers are ignored.
+func (bs BrokerList) ValidateUniformCores() error {
+	byCores := map[int][]int{}
+	for _, b := range bs {
+		if b.MembershipStatus == MembershipRemoved {
+			continue
+		}
+		byCores[b.NumCores] = append(byCores[b.NumCores], b.NodeID)
+	}
+	if len(byCores) <= 1 {
+		return nil
+	}
+	var majority, most int
+	var tied bool
+	for cores, nodes := range byCores {
+		switch {
+		case len(nodes) > most:
+			majority, most, tied = cores, len(nodes), false
+		case len(nodes) == most:
+			tied = true
+		}
+	}
+	e := &NonUniformCoresError{Tie: tied, NodeCores: map[int]int{}}
+	if !tied {
+		e.Cores = majority
+	}
+	for cores, nodes := range byCores {
+		if !tied && cores == majority {
+			continue
+		}
+		for _, node := range nodes {
+			e.Nodes = append(e.Nodes, node)
+			e.NodeCores[node] = cores
+		}
+	}
+	sort.Ints(e.Nodes)
+	return e
+}
+
+// ValidateRackDistribution returns a *RackDistributionError if partitions
+// with the given replication factor can't survive the loss of a rack: if
+// some brokers have a rack and others don't, if there are fewer racks than
+// replicas, or if some racks have more than one broker more than others.
+// Removed brokers are ignored, and so is a cluster where no broker has a
+// rack, since it isn't rack aware. The cluster still works regardless, so
+// the error is meant to be reported as a warning.
+func (bs BrokerList) ValidateRackDistribution(replicationFactor int) error {
+	byRack := brokersByRack(bs)
+	if len(byRack) == 0 || len(byRack) == 1 && byRack[""] != nil {
+		return nil
+	}
+	e := &RackDistributionError{ReplicationFactor: replicationFactor}
+	for _, b := range byRack[""] {
+		e.NoRack = append(e.NoRack, b.NodeID)
+	}
+	delete(byRack, "")
+	e.Racks = make(map[string][]int, len(byRack))
+	fewest, most := -1, 0
+	for rack, rbs := range byRack {
+		for _, b := range rbs {
+			e.Racks[rack] = append(e.Racks[rack], b.NodeID)
+		}
+		if fewest < 0 || len(rbs) < fewest {
+			fewest = len(rbs)
+		}
+		if len(rbs) > most {
+			most = len(rbs)
+		}
+	}
+	e.Uneven = most-fewest > 1
+	if len(e.NoRack) == 0 && len(byRack) >= replicationFactor && !e.Uneven {
+		return nil
+	}
+	return e
+}
+
+// DiskSpace is the usage of a single disk of a broker, in bytes.
+type DiskSpace struct {
+	Path  string `json:"path" yaml:"path"`
+	Free  int64  `json:"free" yaml:"free"`
+	Total int64  `json:"total" yaml:"total"`
+}
+
+// FreePercent returns the percentage, from 0 to 100, of the disk that is
+// free. A disk of unknown size is reported as 0% free.
+func (d DiskSpace) FreePercent() float64 {
+	if d.Total <= 0 {
+		return 0
+	}
+	return 100 * float64(d.Free) / float64(d.Total)
+}
+
+// MaintenanceStatus is the progress of draining a broker in maintenance mode.
+type MaintenanceStatus struct {
+	// Draining is whether the broker is in maintenance mode.
+	Draining bool `json:"draining" yaml:"draining"`
+	// Finished is whether all leadership has been transferred away.
+	Finished bool `json:"finished" yaml:"finished"`
+	// Errors is whether any leadership transfers failed.
+	Errors bool `json:"errors" yaml:"errors"`
+	// Partitions is the number of partitions the broker has leadership of.
+	Partitions int `json:"partitions" yaml:"partitions"`
+	// Eligible is the number of partitions whose leadership can be moved.
+	Eligible int `json:"eligible" yaml:"eligible"`
+	// Transferring is the number of leadership transfers in progress.
+	Transferring int `json:"transferring" yaml:"transferring"`
+	// Failed is the number of leadership transfers that failed.
+	Failed int `json:"failed" yaml:"failed"`
 }
 
 // Brokers queries one of the client's hosts and returns the list of brokers.
-func (a *AdminAPI) Brokers() ([]Broker, error) {
+func (a *AdminAPI) Brokers(ctx context.Context) ([]Broker, error) {
 	var bs []Broker
 	defer func() {
 		sort.Slice(bs, func(i, j int) bool { return bs[i].NodeID < bs[j].NodeID })
 	}()
-	a.Broker(0)
-	return bs, a.sendAny(http.MethodGet, brokersEndpoint, nil, &bs)
+	return bs, a.sendAny(ctx, http.MethodGet, brokersEndpoint, nil, &bs)
+}
+
+// AliveBrokers returns the brokers that the controller considers alive.
+// Brokers that do not report liveness are not included.
+func (a *AdminAPI) AliveBrokers(ctx context.Context) ([]Broker, error) {
+	bs, err := a.Brokers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var alive []Broker
+	for _, b := range bs {
+		if b.IsAlive != nil && *b.IsAlive {
+			alive = append(alive, b)
+		}
+	}
+	return alive, nil
+}
+
+// BrokersWithStatus returns the brokers that have the given membership
+// status.
+func (a *AdminAPI) BrokersWithStatus(
+	ctx context.Context, status MembershipStatus,
+) ([]Broker, error) {
+	bs, err := a.Brokers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var keep []Broker
+	for _, b := range bs {
+		if b.MembershipStatus == status {
+			keep = append(keep, b)
+		}
+	}
+	return keep, nil
+}
+
+// ClusterVersions returns the Redpanda version of each broker that is not
+// removed, by node ID. The version of brokers that don't report it is empty.
+func (a *AdminAPI) ClusterVersions(ctx context.Context) (map[int]string, error) {
+	bs, err := a.Brokers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[int]string, len(bs))
+	for _, b := range bs {
+		if b.MembershipStatus != MembershipRemoved {
+			versions[b.NodeID] = b.Version
+		}
+	}
+	return versions, nil
+}
+
+// IsMixedVersion returns whether the brokers that are not removed run
+// different Redpanda versions, such as during a rolling upgrade. Brokers that
+// don't report their version run an older version than those that do, so a
+// cluster where only some brokers report it is mixed. If no broker reports
+// its version, this returns false.
+func (a *AdminAPI) IsMixedVersion(ctx context.Context) (bool, error) {
+	versions, err := a.ClusterVersions(ctx)
+	if err != nil {
+		return false, err
+	}
+	seen := map[string]bool{}
+	for _, v := range versions {
+		seen[v] = true
+	}
+	return len(seen) > 1, nil
+}
+
+// BrokersByRack returns the brokers that are not removed by rack, sorted by
+// node ID. The brokers without a rack, or of versions that don't report it,
+// are under the empty rack.
+func (a *AdminAPI) BrokersByRack(ctx context.Context) (map[string][]Broker, error) {
+	bs, err := a.Brokers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return brokersByRack(bs), nil
+}
+
+func brokersByRack(bs []Broker) map[string][]Broker {
+	byRack := map[string][]Broker{}
+	for _, b := range bs {
+		if b.MembershipStatus != MembershipRemoved {
+			byRack[b.Rack] = append(byRack[b.Rack], b)
+		}
+	}
+	for _, rbs := range byRack {
+		sort.Slice(rbs, func(i, j int) bool { return rbs[i].NodeID < rbs[j].NodeID })
+	}
+	return byRack
 }
 
 // Broker returns the status of a single broker, which includes membership
 // status.
-func (a *AdminAPI) Broker(node int) (Broker, error) {
+func (a *AdminAPI) Broker(ctx context.Context, node int) (Broker, error) {
 	var b Broker
-	return b, a.sendAny(http.MethodGet, fmt.Sprintf("%s/%d", brokersEndpoint, node), nil, &b)
+	err := a.sendAny(ctx, http.MethodGet, fmt.Sprintf("%s/%d", brokersEndpoint, node), nil, &b)
+	if err != nil {
+		return b, fmt.Errorf("get broker %d: %w", node, err)
+	}
+	return b, nil
 }
 
 // DecommissionBroker issues a decommission request for the given broker.
-func (a *AdminAPI) DecommissionBroker(node int) error {
-	return a.sendAll(
+func (a *AdminAPI) DecommissionBroker(ctx context.Context, node int) error {
+	err := a.sendAll(
+		ctx,
 		http.MethodPut,
 		fmt.Sprintf("%s/%d/decommission", brokersEndpoint, node),
 		nil,
 		nil,
 	)
+	if err != nil {
+		return fmt.Errorf("decommission broker %d: %w", node, err)
+	}
+	return nil
+}
+
+// DecommissionBrokers issues a decommission request for each of the given
+// brokers, one at a time in ascending node order. A failure to decommission
+// one broker does not stop the others; the returned error combines the
+// error of each broker that failed.
+//
+// Before issuing any request, this validates that every node is a known
+// broker that is not already removed, and that at least one active broker
+// remains afterwards. The admin API does not expose topic replication
+// factors, so whether the remaining brokers can hold every replica is left
+// to the server to reject. If dryRun is true, only the validation is done.
+func (a *AdminAPI) DecommissionBrokers(
+	ctx context.Context, nodes []int, dryRun bool,
+) error {
+	if len(nodes) == 0 {
+		return errors.New("no brokers to decommission")
+	}
+	sorted := append([]int(nil), nodes...)
+	sort.Ints(sorted)
+
+	bs, err := a.Brokers(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list brokers: %w", err)
+	}
+	statuses := make(map[int]MembershipStatus, len(bs))
+	for _, b := range bs {
+		statuses[b.NodeID] = b.MembershipStatus
+	}
+	for i, node := range sorted {
+		if i > 0 && sorted[i-1] == node {
+			return fmt.Errorf("broker %d is listed more than once", node)
+		}
+		status, ok := statuses[node]
+		if !ok {
+			return fmt.Errorf("broker %d does not exist", node)
+		}
+		if status == MembershipRemoved {
+			return fmt.Errorf("broker %d is already removed", node)
+		}
+		delete(statuses, node)
+	}
+	var remaining int
+	for _, status := range statuses {
+		if status == MembershipActive {
+			remaining++
+		}
+	}
+	if remaining == 0 {
+		return fmt.Errorf("decommissioning brokers %v would leave no active brokers", sorted)
+	}
+	if dryRun {
+		return nil
+	}
+
+	var errs *multierror.Error
+	for _, node := range sorted {
+		if err := a.DecommissionBroker(ctx, node); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
 }
 
 // RecommissionBroker issues a recommission request for the given broker.
-func (a *AdminAPI) RecommissionBroker(node int) error {
-	return a.sendAll(
+func (a *AdminAPI) RecommissionBroker(ctx context.Context, node int) error {
+	err := a.sendAll(
+		ctx,
 		http.MethodPut,
 		fmt.Sprintf("%s/%d/recommission", brokersEndpoint, node),
 		nil,
 		nil,
 	)
+	if err != nil {
+		return fmt.Errorf("recommission broker %d: %w", node, err)
+	}
+	return nil
+}
+
+// EnableMaintenanceMode puts the given broker into maintenance mode, which
+// drains leadership from the broker. The request is sent to every host and
+// succeeds once the cluster leader accepts it.
+func (a *AdminAPI) EnableMaintenanceMode(ctx context.Context, node int) error {
+	return a.sendAll(
+		ctx,
+		http.MethodPut,
+		fmt.Sprintf("%s/%d/maintenance", brokersEndpoint, node),
+		nil,
+		nil,
+	)
+}
+
+// DisableMaintenanceMode takes the given broker out of maintenance mode. As
+// with EnableMaintenanceMode, it succeeds once the cluster leader accepts it.
+func (a *AdminAPI) DisableMaintenanceMode(ctx context.Context, node int) error {
+	return a.sendAll(
+		ctx,
+		http.MethodDelete,
+		fmt.Sprintf("%s/%d/maintenance", brokersEndpoint, node),
+		nil,
+		nil,
+	)
+}
+
+// MaintenanceStatus returns the maintenance mode status of the given broker.
+// A broker that does not report a status is not in maintenance mode.
+func (a *AdminAPI) MaintenanceStatus(
+	ctx context.Context, node int,
+) (MaintenanceStatus, error) {
+	b, err := a.Broker(ctx, node)
+	if err != nil || b.Maintenance == nil {
+		return MaintenanceStatus{}, err
+	}
+	return *b.Maintenance, nil
+}
+
+// restartEndpoint is the node-local endpoint that asks a node to shut down
+// gracefully and start again. No released Redpanda version serves it yet:
+// it's the endpoint this client expects, and versions without it are
+// reported through a *RestartUnsupportedError.
+const restartEndpoint = "/v1/node/restart"
+
+// RestartBroker asks the given broker to restart itself. The request is sent
+// to the broker's own host, which must be one of the client's hosts, and
+// returns once the broker accepted it, which may be before it goes down.
+// Use WaitForBrokerActive to wait for the broker to be back, or
+// DrainAndRestartBroker to restart it without disrupting clients.
+//
+// The restart endpoint is speculative, since no released Redpanda version
+// serves it yet; if the broker's version has no restart endpoint, this
+// returns a *RestartUnsupportedError.
+func (a *AdminAPI) RestartBroker(ctx context.Context, node int) error {
+	err := a.sendToNode(ctx, node, http.MethodPost, restartEndpoint, nil, nil)
+	if hasStatus(err, http.StatusNotFound) || hasStatus(err, http.StatusMethodNotAllowed) {
+		return &RestartUnsupportedError{NodeID: node, err: err}
+	}
+	if err != nil {
+		return fmt.Errorf("restart broker %d: %w", node, err)
+	}
+	return nil
+}
+
+// undrainTimeout bounds taking a broker out of maintenance mode after
+// DrainAndRestartBroker failed, which may be because its ctx is done.
+const undrainTimeout = 10 * time.Second
+
+// restartDownGrace is how long DrainAndRestartBroker waits to see the broker
+// go down before assuming that it restarted too quickly to be seen.
+const restartDownGrace = 30 * time.Second
+
+// restartPoll is the longest interval at which DrainAndRestartBroker polls
+// the broker's own admin API while waiting for it to go down, so that a quick
+// restart isn't missed between polls.
+const restartPoll = 250 * time.Millisecond
+
+// DrainAndRestartBroker restarts the given broker without disrupting
+// clients, one step of a rolling restart. It puts the broker into
+// maintenance mode, waits for its leadership to drain, restarts it, waits for
+// it to go down and to be active again, and takes it out of maintenance mode.
+// Each wait polls the cluster every poll; if poll is zero or less,
+// DefaultDecommissionPoll is used.
+//
+// The broker is seen going down when its own admin API becomes unreachable
+// or not ready, or the controller considers it down. If it isn't seen going
+// down within restartDownGrace, it's assumed to have restarted between polls.
+// While it restarts, failing to reach the cluster is retried rather than
+// returned.
+//
+// If draining or restarting the broker fails, the broker is taken out of
+// maintenance mode before the error is returned, even if ctx is done. If ctx
+// is done once the broker is restarting, it's left in maintenance mode.
+func (a *AdminAPI) DrainAndRestartBroker(
+	ctx context.Context, node int, poll time.Duration,
+) error {
+	if poll <= 0 {
+		poll = DefaultDecommissionPoll
+	}
+	if err := a.EnableMaintenanceMode(ctx, node); err != nil {
+		return fmt.Errorf("unable to drain broker %d: %w", node, err)
+	}
+	undrain := func(err error) error {
+		uctx, cancel := context.WithTimeout(context.Background(), undrainTimeout)
+		defer cancel()
+		if dErr := a.DisableMaintenanceMode(uctx, node); dErr != nil {
+			return multierror.Append(err, fmt.Errorf(
+				"unable to take broker %d out of maintenance mode: %w", node, dErr,
+			))
+		}
+		return err
+	}
+	if err := a.waitDrained(ctx, node, poll); err != nil {
+		return undrain(err)
+	}
+	if err := a.RestartBroker(ctx, node); err != nil {
+		return undrain(err)
+	}
+	if err := a.waitBrokerDown(ctx, node, poll, restartDownGrace); err != nil {
+		return err
+	}
+	if err := a.waitBrokerActive(ctx, node, poll, true); err != nil {
+		return err
+	}
+	if err := a.DisableMaintenanceMode(ctx, node); err != nil {
+		return fmt.Errorf("unable to take broker %d out of maintenance mode: %w", node, err)
+	}
+	return nil
+}
+
+// waitDrained polls the maintenance mode status of the broker until all of
+// its leadership has been transferred away.
+func (a *AdminAPI) waitDrained(ctx context.Context, node int, poll time.Duration) error {
+	for {
+		s, err := a.MaintenanceStatus(ctx, node)
+		if err != nil {
+			return err
+		}
+		if s.Finished {
+			if s.Errors {
+				return fmt.Errorf(
+					"broker %d failed to transfer the leadership of %d partitions", node, s.Failed,
+				)
+			}
+			return nil
+		}
+		if err := sleepCtx(ctx, poll); err != nil {
+			return err
+		}
+	}
+}
+
+// waitBrokerDown polls the broker's own admin API until it can't be reached
+// or isn't ready, or the controller considers the broker down, or grace
+// elapses. The broker is polled at least every restartPoll.
+func (a *AdminAPI) waitBrokerDown(
+	ctx context.Context, node int, poll, grace time.Duration,
+) error {
+	if poll > restartPoll {
+		poll = restartPoll
+	}
+	deadline := time.Now().Add(grace)
+	for {
+		var status struct {
+			Status string `json:"status"`
+		}
+		err := a.sendToNode(ctx, node, http.MethodGet, readyEndpoint, nil, &status)
+		if err == nil && status.Status != "ready" {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("broker %d did not go down: %w", node, ctx.Err())
+			}
+			if isHostFailure(err) {
+				return nil
+			}
+			return err
+		}
+		b, err := a.Broker(ctx, node)
+		if err == nil && b.IsAlive != nil && !*b.IsAlive {
+			return nil
+		}
+		if err != nil && !isHostFailure(err) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+		if err := sleepCtx(ctx, poll); err != nil {
+			return fmt.Errorf("broker %d did not go down: %w", node, err)
+		}
+	}
+}
+
+// DecommissionStatus is the progress of a broker decommission, as returned
+// from the Redpanda admin decommission endpoint.
+type DecommissionStatus struct {
+	// Finished is whether all replicas have been moved off the broker.
+	Finished bool `json:"finished"`
+	// ReplicasLeft is the number of replicas that still need to be moved.
+	ReplicasLeft int `json:"replicas_left"`
+	// Partitions are the partitions that are still being moved.
+	Partitions []DecommissionPartition `json:"partitions"`
+}
+
+// DecommissionPartition is a partition that is being moved off of a
+// decommissioning broker.
+type DecommissionPartition struct {
+	Namespace       string `json:"ns"`
+	Topic           string `json:"topic"`
+	Partition       int    `json:"partition"`
+	MovingTo        int    `json:"moving_to"`
+	BytesLeftToMove int64  `json:"bytes_left_to_move"`
+	BytesMoved      int64  `json:"bytes_moved"`
+	PartitionSize   int64  `json:"partition_size"`
+}
+
+// Completion returns the percentage, from 0 to 100, of the partition that
+// has been moved. A partition of unknown size is reported as 0% complete.
+func (p DecommissionPartition) Completion() float64 {
+	if p.PartitionSize <= 0 {
+		return 0
+	}
+	return 100 * float64(p.BytesMoved) / float64(p.PartitionSize)
+}
+
+// DecommissionStatus returns the progress of decommissioning the given
+// broker.
+func (a *AdminAPI) DecommissionStatus(
+	ctx context.Context, node int,
+) (DecommissionStatus, error) {
+	var s DecommissionStatus
+	return s, a.sendAny(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/decommission", brokersEndpoint, node),
+		nil,
+		&s,
+	)
+}
+
+// WaitForBrokerRemoved polls the cluster every poll interval until the given
+// broker is no longer listed in Brokers, or its decommission status reports
+// finished, or ctx is done. If poll is zero or less, DefaultDecommissionPoll
+// is used.
+//
+// If the broker is seen decommissioning and is later active again, this
+// returns a *BrokerRecommissionedError.
+func (a *AdminAPI) WaitForBrokerRemoved(
+	ctx context.Context, node int, poll time.Duration,
+) error {
+	if poll <= 0 {
+		poll = DefaultDecommissionPoll
+	}
+	var draining bool
+	for {
+		done, stillDraining, err := a.brokerRemoved(ctx, node)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if draining && !stillDraining {
+			return &BrokerRecommissionedError{NodeID: node}
+		}
+		draining = draining || stillDraining
+
+		if err := sleepCtx(ctx, poll); err != nil {
+			return err
+		}
+	}
+}
+
+// WaitForBrokerActive polls the cluster every poll interval until the given
+// broker, e.g. one that was just recommissioned, has an active membership
+// status and is alive, or ctx is done. If poll is zero or less,
+// DefaultDecommissionPoll is used. Brokers that do not report liveness only
+// need to be active.
+//
+// If the broker is seen active and is later draining again, this returns a
+// *BrokerDecommissionedError. If the broker is removed, which cannot be
+// undone, this returns an error immediately.
+func (a *AdminAPI) WaitForBrokerActive(
+	ctx context.Context, node int, poll time.Duration,
+) error {
+	return a.waitBrokerActive(ctx, node, poll, false)
+}
+
+// waitBrokerActive is WaitForBrokerActive, which also retries failing to
+// reach the cluster if the broker is restarting.
+func (a *AdminAPI) waitBrokerActive(
+	ctx context.Context, node int, poll time.Duration, restarting bool,
+) error {
+	if poll <= 0 {
+		poll = DefaultDecommissionPoll
+	}
+	var active bool
+	for {
+		b, err := a.Broker(ctx, node)
+		if err != nil {
+			if !restarting || ctx.Err() != nil || !isHostFailure(err) {
+				return err
+			}
+			// The broker's status is unknown until the cluster can
+			// be reached again.
+			b = Broker{}
+		}
+		switch b.MembershipStatus {
+		case MembershipActive:
+			if b.IsAlive == nil || *b.IsAlive {
+				return nil
+			}
+			active = true
+		case MembershipDraining:
+			if active {
+				return &BrokerDecommissionedError{NodeID: node}
+			}
+		case MembershipRemoved:
+			return fmt.Errorf("broker %d was removed while waiting for it to be active", node)
+		}
+
+		if err := sleepCtx(ctx, poll); err != nil {
+			return err
+		}
+	}
+}
+
+// brokerRemoved returns whether the broker is fully removed and, if not,
+// whether it is still decommissioning.
+func (a *AdminAPI) brokerRemoved(
+	ctx context.Context, node int,
+) (removed, draining bool, err error) {
+	bs, err := a.Brokers(ctx)
+	if err != nil {
+		return false, false, err
+	}
+	var b *Broker
+	for i := range bs {
+		if bs[i].NodeID == node {
+			b = &bs[i]
+			break
+		}
+	}
+	if b == nil {
+		return true, false, nil
+	}
+
+	// Older versions do not have a decommission status, in which case we
+	// wait for the broker to leave the list.
+	s, err := a.DecommissionStatus(ctx, node)
+	switch {
+	case IsNotFound(err):
+	case err != nil:
+		return false, false, err
+	case s.Finished:
+		return true, false, nil
+	}
+	return false, b.MembershipStatus != MembershipActive, nil
 }
@@ -0,0 +1,1020 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecommissionStatus(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Equal(t, "/v1/brokers/2/decommission", r.URL.Path)
+			w.Write([]byte(`{
+  "finished": false,
+  "replicas_left": 1,
+  "partitions": [{
+    "ns": "kafka",
+    "topic": "foo",
+    "partition": 3,
+    "moving_to": 1,
+    "bytes_left_to_move": 300,
+    "bytes_moved": 100,
+    "partition_size": 400
+  }]
+}`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	s, err := adminClient.DecommissionStatus(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, DecommissionStatus{
+		ReplicasLeft: 1,
+		Partitions: []DecommissionPartition{{
+			Namespace:       "kafka",
+			Topic:           "foo",
+			Partition:       3,
+			MovingTo:        1,
+			BytesLeftToMove: 300,
+			BytesMoved:      100,
+			PartitionSize:   400,
+		}},
+	}, s)
+	require.Equal(t, 25.0, s.Partitions[0].Completion())
+	require.Equal(t, 0.0, DecommissionPartition{}.Completion())
+}
+
+func TestWaitForBrokerRemoved(t *testing.T) {
+	tests := []struct {
+		name string
+		// statuses is the membership status of broker 2 at each poll,
+		// where an empty status means the broker is gone.
+		statuses []string
+		finished bool
+		noStatus bool
+		expErr   bool
+	}{
+		{name: "removed", statuses: []string{"draining", "draining", ""}},
+		{name: "finished", statuses: []string{"draining", "draining"}, finished: true},
+		{name: "no status endpoint", statuses: []string{"draining", ""}, noStatus: true},
+		{name: "recommissioned", statuses: []string{"draining", "active"}, expErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int32
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case brokersEndpoint:
+						i := int(atomic.AddInt32(&polls, 1)) - 1
+						if i >= len(tt.statuses) {
+							i = len(tt.statuses) - 1
+						}
+						bs := `{"node_id": 1, "membership_status": "active"}`
+						if status := tt.statuses[i]; status != "" {
+							bs += fmt.Sprintf(`, {"node_id": 2, "membership_status": %q}`, status)
+						}
+						w.Write([]byte("[" + bs + "]"))
+					case brokersEndpoint + "/2/decommission":
+						if tt.noStatus {
+							w.WriteHeader(http.StatusNotFound)
+							return
+						}
+						finished := tt.finished && atomic.LoadInt32(&polls) == int32(len(tt.statuses))
+						fmt.Fprintf(w, `{"finished": %t}`, finished)
+					default:
+						w.Write([]byte(`{}`))
+					}
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err = adminClient.WaitForBrokerRemoved(ctx, 2, time.Millisecond)
+			if tt.expErr {
+				var re *BrokerRecommissionedError
+				require.True(t, errors.As(err, &re), "got %v", err)
+				require.Equal(t, 2, re.NodeID)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, len(tt.statuses), atomic.LoadInt32(&polls))
+		})
+	}
+
+	// The context bounds the wait.
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case brokersEndpoint:
+				w.Write([]byte(`[{"node_id": 2, "membership_status": "draining"}]`))
+			default:
+				w.Write([]byte(`{}`))
+			}
+		}),
+	)
+	defer ts.Close()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = adminClient.WaitForBrokerRemoved(ctx, 2, 10*time.Millisecond)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+}
+
+func TestWaitForBrokerActive(t *testing.T) {
+	tests := []struct {
+		name string
+		// brokers is broker 2 at each poll.
+		brokers []string
+		expErr  bool
+		expDec  bool
+	}{
+		{
+			name: "recommissioned",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "draining"}`,
+				`{"node_id": 2, "membership_status": "active"}`,
+			},
+		},
+		{
+			name: "waits until alive",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "active", "is_alive": false}`,
+				`{"node_id": 2, "membership_status": "active", "is_alive": true}`,
+			},
+		},
+		{
+			name: "decommissioned again",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "active", "is_alive": false}`,
+				`{"node_id": 2, "membership_status": "draining", "is_alive": false}`,
+			},
+			expErr: true,
+			expDec: true,
+		},
+		{
+			name:    "removed",
+			brokers: []string{`{"node_id": 2, "membership_status": "removed"}`},
+			expErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int32
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, brokersEndpoint+"/2", r.URL.Path)
+					i := int(atomic.AddInt32(&polls, 1)) - 1
+					if i >= len(tt.brokers) {
+						i = len(tt.brokers) - 1
+					}
+					w.Write([]byte(tt.brokers[i]))
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err = adminClient.WaitForBrokerActive(ctx, 2, time.Millisecond)
+			require.EqualValues(t, len(tt.brokers), atomic.LoadInt32(&polls))
+			if !tt.expErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var de *BrokerDecommissionedError
+			require.Equal(t, tt.expDec, errors.As(err, &de), "got %v", err)
+		})
+	}
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	var draining int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v1/brokers/1/maintenance":
+				switch r.Method {
+				case http.MethodPut:
+					atomic.StoreInt32(&draining, 1)
+				case http.MethodDelete:
+					atomic.StoreInt32(&draining, 0)
+				default:
+					w.WriteHeader(http.StatusMethodNotAllowed)
+				}
+			case "/v1/brokers/1":
+				if atomic.LoadInt32(&draining) == 0 {
+					w.Write([]byte(`{"node_id": 1}`))
+					return
+				}
+				w.Write([]byte(`{"node_id": 1, "maintenance_status": {
+  "draining": true,
+  "finished": false,
+  "errors": true,
+  "partitions": 10,
+  "eligible": 8,
+  "transferring": 4,
+  "failed": 1
+}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	s, err := adminClient.MaintenanceStatus(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, MaintenanceStatus{}, s)
+
+	require.NoError(t, adminClient.EnableMaintenanceMode(ctx, 1))
+	s, err = adminClient.MaintenanceStatus(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, MaintenanceStatus{
+		Draining:     true,
+		Errors:       true,
+		Partitions:   10,
+		Eligible:     8,
+		Transferring: 4,
+		Failed:       1,
+	}, s)
+
+	require.NoError(t, adminClient.DisableMaintenanceMode(ctx, 1))
+	s, err = adminClient.MaintenanceStatus(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, s.Draining)
+}
+
+func TestMaintenanceModeLeaderOnly(t *testing.T) {
+	var calls int32
+	leader := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}),
+	)
+	defer leader.Close()
+	follower := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "not leader", "code": 400}`))
+		}),
+	)
+	defer follower.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{follower.URL, down.URL, leader.URL}, nil)
+	require.NoError(t, err)
+	require.NoError(t, adminClient.EnableMaintenanceMode(ctx, 1))
+	require.NoError(t, adminClient.DisableMaintenanceMode(ctx, 1))
+	require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	adminClient, err = NewAdminAPI([]string{follower.URL, down.URL}, nil)
+	require.NoError(t, err)
+	err = adminClient.EnableMaintenanceMode(ctx, 1)
+	var se *SendAllError
+	require.True(t, errors.As(err, &se), "got %v", err)
+	require.Len(t, se.PerHost(), 2)
+}
+
+func TestBrokerDiskSpace(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case brokersEndpoint:
+				w.Write([]byte(`[
+  {"node_id": 2, "disk_space": [{"path": "/var/lib/redpanda/data", "free": 25, "total": 100}]},
+  {"node_id": 1}
+]`))
+			default:
+				w.Write([]byte(`{}`))
+			}
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	bs, err := adminClient.Brokers(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []Broker{
+		{NodeID: 1},
+		{NodeID: 2, DiskSpace: []DiskSpace{{Path: "/var/lib/redpanda/data", Free: 25, Total: 100}}},
+	}, bs)
+	require.Equal(t, 25.0, bs[1].DiskSpace[0].FreePercent())
+	require.Equal(t, 0.0, DiskSpace{}.FreePercent())
+}
+
+func TestAliveBrokers(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case brokersEndpoint:
+				w.Write([]byte(`[
+  {"node_id": 3, "is_alive": true},
+  {"node_id": 2, "is_alive": false},
+  {"node_id": 1, "is_alive": true},
+  {"node_id": 0}
+]`))
+			default:
+				w.Write([]byte(`{}`))
+			}
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	bs, err := adminClient.AliveBrokers(context.Background())
+	require.NoError(t, err)
+	alive := true
+	require.Equal(t, []Broker{
+		{NodeID: 1, IsAlive: &alive},
+		{NodeID: 3, IsAlive: &alive},
+	}, bs)
+}
+
+func TestBrokersWithStatus(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case brokersEndpoint:
+				w.Write([]byte(`[
+  {"node_id": 4, "membership_status": "active"},
+  {"node_id": 3, "membership_status": "draining"},
+  {"node_id": 2, "membership_status": "some_future_status"},
+  {"node_id": 1, "membership_status": 7},
+  {"node_id": 0, "membership_status": "active"}
+]`))
+			default:
+				w.Write([]byte(`{}`))
+			}
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	bs, err := adminClient.BrokersWithStatus(ctx, MembershipActive)
+	require.NoError(t, err)
+	require.Equal(t, []Broker{
+		{NodeID: 0, MembershipStatus: MembershipActive},
+		{NodeID: 4, MembershipStatus: MembershipActive},
+	}, bs)
+
+	bs, err = adminClient.BrokersWithStatus(ctx, MembershipDraining)
+	require.NoError(t, err)
+	require.Equal(t, []Broker{{NodeID: 3, MembershipStatus: MembershipDraining}}, bs)
+
+	bs, err = adminClient.Brokers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, MembershipStatus("some_future_status"), bs[2].MembershipStatus)
+	require.Equal(t, MembershipStatus(""), bs[1].MembershipStatus)
+}
+
+func TestDecommissionBrokers(t *testing.T) {
+	tests := []struct {
+		name   string
+		nodes  []int
+		dryRun bool
+		expErr string
+		exp    []string // decommissioned brokers, in order
+	}{
+		{name: "ordered", nodes: []int{3, 1}, exp: []string{"1", "3"}},
+		{name: "dry run", nodes: []int{3, 1}, dryRun: true},
+		{name: "partial failure", nodes: []int{2, 1}, exp: []string{"1", "2"}, expErr: "broker 2"},
+		{name: "unknown", nodes: []int{1, 9}, expErr: "broker 9 does not exist"},
+		{name: "duplicate", nodes: []int{1, 1}, expErr: "more than once"},
+		{name: "removed", nodes: []int{4}, expErr: "already removed"},
+		{name: "none left", nodes: []int{0, 1, 2, 3}, expErr: "no active brokers"},
+		{name: "empty", expErr: "no brokers"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decommissioned []string
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodPut {
+						node := strings.TrimPrefix(r.URL.Path, brokersEndpoint+"/")
+						node = strings.TrimSuffix(node, "/decommission")
+						decommissioned = append(decommissioned, node)
+						if node == "2" {
+							w.WriteHeader(http.StatusBadRequest)
+						}
+						return
+					}
+					switch r.URL.Path {
+					case brokersEndpoint:
+						w.Write([]byte(`[
+  {"node_id": 0, "membership_status": "active"},
+  {"node_id": 1, "membership_status": "active"},
+  {"node_id": 2, "membership_status": "active"},
+  {"node_id": 3, "membership_status": "draining"},
+  {"node_id": 4, "membership_status": "removed"}
+]`))
+					default:
+						w.Write([]byte(`{}`))
+					}
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			err = adminClient.DecommissionBrokers(context.Background(), tt.nodes, tt.dryRun)
+			require.Equal(t, tt.exp, decommissioned)
+			if tt.expErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBrokersSingleRequest(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.Write([]byte(`[{"node_id": 2}, {"node_id": 1}]`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	bs, err := adminClient.Brokers(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []Broker{{NodeID: 1}, {NodeID: 2}}, bs)
+	require.Equal(t, []string{brokersEndpoint}, paths)
+}
+
+func TestBrokerErrorContext(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	for prefix, fn := range map[string]func() error{
+		"get broker 7: ": func() error {
+			_, err := adminClient.Broker(ctx, 7)
+			return err
+		},
+		"decommission broker 7: ": func() error { return adminClient.DecommissionBroker(ctx, 7) },
+		"recommission broker 7: ": func() error { return adminClient.RecommissionBroker(ctx, 7) },
+	} {
+		err := fn()
+		require.Error(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), prefix), "got %v", err)
+		require.True(t, IsNotFound(err), "cause should be unwrappable: %v", err)
+	}
+}
+
+func TestBrokerString(t *testing.T) {
+	for _, test := range []struct {
+		b      Broker
+		exp    string
+		expRow []string
+	}{
+		{
+			b:      Broker{NodeID: 3, NumCores: 8, MembershipStatus: MembershipActive},
+			exp:    "broker 3 (active, 8 cores)",
+			expRow: []string{"3", "8", "active", "-", "-", "-"},
+		},
+		{
+			b:      Broker{NodeID: 0, NumCores: 1},
+			exp:    "broker 0 (1 core)",
+			expRow: []string{"0", "1", "", "-", "-", "-"},
+		},
+		{
+			b: Broker{
+				NodeID:           1,
+				NumCores:         2,
+				MembershipStatus: MembershipDraining,
+				Rack:             "us-east-1a",
+				Version:          "v21.11.1",
+				DiskSpace: []DiskSpace{
+					{Path: "/a", Free: 1, Total: 4},
+					{Path: "/b", Free: 1, Total: 2},
+				},
+			},
+			exp:    "broker 1 (draining, 2 cores)",
+			expRow: []string{"1", "2", "draining", "us-east-1a", "v21.11.1", "/a 25.0% free, /b 50.0% free"},
+		},
+	} {
+		require.Equal(t, test.exp, test.b.String())
+		require.Equal(t, test.expRow, test.b.Row())
+		require.Len(t, test.b.Row(), len(BrokerHeader))
+	}
+}
+
+func TestClusterVersions(t *testing.T) {
+	var brokers atomic.Value
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != brokersEndpoint {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(brokers.Load().(string)))
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name     string
+		brokers  string
+		versions map[int]string
+		mixed    bool
+	}{
+		{
+			name: "same version",
+			brokers: `[
+  {"node_id": 1, "version": "v21.11.3"},
+  {"node_id": 2, "version": "v21.11.3"},
+  {"node_id": 3, "version": "v21.10.1", "membership_status": "removed"}
+]`,
+			versions: map[int]string{1: "v21.11.3", 2: "v21.11.3"},
+		},
+		{
+			name: "rolling upgrade",
+			brokers: `[
+  {"node_id": 1, "version": "v21.11.3"},
+  {"node_id": 2, "version": "v21.10.1"}
+]`,
+			versions: map[int]string{1: "v21.11.3", 2: "v21.10.1"},
+			mixed:    true,
+		},
+		{
+			name:     "upgrade from a version that doesn't report it",
+			brokers:  `[{"node_id": 1, "version": "v21.11.3"}, {"node_id": 2}]`,
+			versions: map[int]string{1: "v21.11.3", 2: ""},
+			mixed:    true,
+		},
+		{
+			name:     "no broker reports its version",
+			brokers:  `[{"node_id": 1}, {"node_id": 2}]`,
+			versions: map[int]string{1: "", 2: ""},
+		},
+	} {
+		brokers.Store(test.brokers)
+		versions, err := adminClient.ClusterVersions(ctx)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.versions, versions, test.name)
+		mixed, err := adminClient.IsMixedVersion(ctx)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.mixed, mixed, test.name)
+	}
+}
+
+func TestValidateUniformCores(t *testing.T) {
+	tests := []struct {
+		name     string
+		brokers  BrokerList
+		expected *NonUniformCoresError
+		expErr   string
+	}{
+		{
+			name:    "it should pass if every broker has the same cores",
+			brokers: BrokerList{{NodeID: 0, NumCores: 8}, {NodeID: 1, NumCores: 8}},
+		},
+		{
+			name:    "it should pass without brokers",
+			brokers: nil,
+		},
+		{
+			name: "it should ignore removed brokers",
+			brokers: BrokerList{
+				{NodeID: 0, NumCores: 8},
+				{NodeID: 1, NumCores: 4, MembershipStatus: MembershipRemoved},
+			},
+		},
+		{
+			name: "it should return the brokers that differ from the majority",
+			brokers: BrokerList{
+				{NodeID: 0, NumCores: 8},
+				{NodeID: 4, NumCores: 16},
+				{NodeID: 1, NumCores: 8},
+				{NodeID: 2, NumCores: 4},
+				{NodeID: 3, NumCores: 8},
+			},
+			expected: &NonUniformCoresError{
+				Cores:     8,
+				Nodes:     []int{2, 4},
+				NodeCores: map[int]int{2: 4, 4: 16},
+			},
+			expErr: "brokers 2 (4 cores), 4 (16 cores) don't have the 8 cores of the other brokers",
+		},
+		{
+			name: "it should return a single broker that differs from the majority",
+			brokers: BrokerList{
+				{NodeID: 0, NumCores: 8},
+				{NodeID: 1, NumCores: 8},
+				{NodeID: 2, NumCores: 4},
+			},
+			expected: &NonUniformCoresError{
+				Cores:     8,
+				Nodes:     []int{2},
+				NodeCores: map[int]int{2: 4},
+			},
+			expErr: "broker 2 (4 cores) doesn't have the 8 cores of the other brokers",
+		},
+		{
+			name: "it should return every broker if there's no majority",
+			brokers: BrokerList{
+				{NodeID: 0, NumCores: 8},
+				{NodeID: 1, NumCores: 4},
+			},
+			expected: &NonUniformCoresError{
+				Tie:       true,
+				Nodes:     []int{0, 1},
+				NodeCores: map[int]int{0: 8, 1: 4},
+			},
+			expErr: "the brokers have different numbers of cores, with none more common than the others: 0 (8 cores), 1 (4 cores)",
+		},
+		{
+			name: "it should return brokers without cores if there's no majority",
+			brokers: BrokerList{
+				{NodeID: 0, NumCores: 8},
+				{NodeID: 1, NumCores: 0},
+			},
+			expected: &NonUniformCoresError{
+				Tie:       true,
+				Nodes:     []int{0, 1},
+				NodeCores: map[int]int{0: 8, 1: 0},
+			},
+			expErr: "the brokers have different numbers of cores, with none more common than the others: 0 (8 cores), 1 (0 cores)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.brokers.ValidateUniformCores()
+			if tt.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			var ce *NonUniformCoresError
+			require.True(t, errors.As(err, &ce))
+			require.Equal(t, tt.expected, ce)
+			require.EqualError(t, err, tt.expErr)
+		})
+	}
+}
+
+func TestBrokersByRack(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[
+  {"node_id": 3, "rack": "a"},
+  {"node_id": 1, "rack": "a"},
+  {"node_id": 2, "rack": "b"},
+  {"node_id": 4},
+  {"node_id": 5, "rack": "c", "membership_status": "removed"}
+]`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	byRack, err := adminClient.BrokersByRack(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string][]Broker{
+		"a": {{NodeID: 1, Rack: "a"}, {NodeID: 3, Rack: "a"}},
+		"b": {{NodeID: 2, Rack: "b"}},
+		"":  {{NodeID: 4}},
+	}, byRack)
+}
+
+func TestValidateRackDistribution(t *testing.T) {
+	racks := func(racks ...string) BrokerList {
+		var bs BrokerList
+		for i, rack := range racks {
+			bs = append(bs, Broker{NodeID: i, Rack: rack})
+		}
+		return bs
+	}
+	tests := []struct {
+		name     string
+		brokers  BrokerList
+		rf       int
+		expected *RackDistributionError
+		expErr   string
+	}{
+		{
+			name:    "it should pass if the brokers are evenly spread across enough racks",
+			brokers: racks("a", "b", "c", "a", "b"),
+			rf:      3,
+		},
+		{
+			name:    "it should pass if the cluster isn't rack aware",
+			brokers: racks("", "", ""),
+			rf:      3,
+		},
+		{
+			name: "it should ignore removed brokers",
+			brokers: append(
+				racks("a", "b", "c"),
+				Broker{NodeID: 3, MembershipStatus: MembershipRemoved},
+			),
+			rf: 3,
+		},
+		{
+			name:    "it should fail if some brokers have no rack",
+			brokers: racks("a", "b", "c", ""),
+			rf:      3,
+			expected: &RackDistributionError{
+				ReplicationFactor: 3,
+				Racks:             map[string][]int{"a": {0}, "b": {1}, "c": {2}},
+				NoRack:            []int{3},
+			},
+			expErr: "a rack failure may lose replicas: brokers [3] have no rack",
+		},
+		{
+			name:    "it should fail if there are fewer racks than replicas",
+			brokers: racks("a", "b", "a", "b"),
+			rf:      3,
+			expected: &RackDistributionError{
+				ReplicationFactor: 3,
+				Racks:             map[string][]int{"a": {0, 2}, "b": {1, 3}},
+			},
+			expErr: "a rack failure may lose replicas: there are 2 racks, fewer than the replication factor of 3",
+		},
+		{
+			name:    "it should fail if the brokers are unevenly spread",
+			brokers: racks("a", "b", "c", "a", "a"),
+			rf:      3,
+			expected: &RackDistributionError{
+				ReplicationFactor: 3,
+				Racks:             map[string][]int{"a": {0, 3, 4}, "b": {1}, "c": {2}},
+				Uneven:            true,
+			},
+			expErr: "a rack failure may lose replicas: the brokers are unevenly spread across racks: a has 3, b has 1, c has 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.brokers.ValidateRackDistribution(tt.rf)
+			if tt.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			var re *RackDistributionError
+			require.True(t, errors.As(err, &re))
+			require.Equal(t, tt.expected, re)
+			require.EqualError(t, err, tt.expErr)
+		})
+	}
+}
+
+func TestRestartBroker(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		status      int
+		unsupported bool
+	}{
+		{name: "restarted", status: http.StatusOK},
+		{name: "no endpoint", status: http.StatusNotFound, unsupported: true},
+		{name: "method not allowed", status: http.StatusMethodNotAllowed, unsupported: true},
+		{name: "failed", status: http.StatusInternalServerError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case nodeConfigEndpoint:
+						w.Write([]byte(`{"node_id": 2}`))
+					case restartEndpoint:
+						require.Equal(t, http.MethodPost, r.Method)
+						w.WriteHeader(tt.status)
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			err = adminClient.RestartBroker(context.Background(), 2)
+			if tt.status == http.StatusOK {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var rue *RestartUnsupportedError
+			require.Equal(t, tt.unsupported, errors.As(err, &rue), "got %v", err)
+		})
+	}
+}
+
+func TestDrainAndRestartBroker(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		// brokers is broker 2 at each poll, after maintenance mode is
+		// enabled.
+		brokers []string
+		// dropped is how many requests fail to connect once the broker
+		// is restarting.
+		dropped int
+		// notReady is whether the broker reports it's not ready once
+		// it's restarting.
+		notReady    bool
+		unsupported bool
+		canceled    bool
+		expCalls    []string
+	}{
+		{
+			name: "restarted, seen unreachable",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "active", "is_alive": true, "maintenance_status": {"draining": true, "finished": false}}`,
+				`{"node_id": 2, "membership_status": "active", "is_alive": true, "maintenance_status": {"draining": true, "finished": true}}`,
+				`{"node_id": 2, "membership_status": "active", "is_alive": true}`,
+			},
+			dropped:  3,
+			expCalls: []string{"PUT maintenance", "POST restart", "DELETE maintenance"},
+		},
+		{
+			name: "restarted, seen not ready",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "active", "is_alive": true, "maintenance_status": {"draining": true, "finished": true}}`,
+				`{"node_id": 2, "membership_status": "active", "is_alive": false}`,
+				`{"node_id": 2, "membership_status": "active", "is_alive": true}`,
+			},
+			notReady: true,
+			expCalls: []string{"PUT maintenance", "POST restart", "DELETE maintenance"},
+		},
+		{
+			name: "restarted, seen down by the controller",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "active", "is_alive": true, "maintenance_status": {"draining": true, "finished": true}}`,
+				`{"node_id": 2, "membership_status": "active", "is_alive": false}`,
+				`{"node_id": 2, "membership_status": "active", "is_alive": true}`,
+			},
+			expCalls: []string{"PUT maintenance", "POST restart", "DELETE maintenance"},
+		},
+		{
+			name: "undrained if unsupported",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "active", "is_alive": true, "maintenance_status": {"draining": true, "finished": true}}`,
+			},
+			unsupported: true,
+			expCalls:    []string{"PUT maintenance", "POST restart", "DELETE maintenance"},
+		},
+		{
+			name: "undrained if canceled while draining",
+			brokers: []string{
+				`{"node_id": 2, "membership_status": "active", "is_alive": true, "maintenance_status": {"draining": true, "finished": false}}`,
+			},
+			canceled: true,
+			expCalls: []string{"PUT maintenance", "DELETE maintenance"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu         sync.Mutex
+				calls      []string
+				polls      int
+				restarting bool
+				dropped    int
+			)
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					mu.Lock()
+					defer mu.Unlock()
+					if restarting && dropped < tt.dropped {
+						dropped++
+						conn, _, err := w.(http.Hijacker).Hijack()
+						require.NoError(t, err)
+						conn.Close()
+						return
+					}
+					switch r.URL.Path {
+					case nodeConfigEndpoint:
+						w.Write([]byte(`{"node_id": 2}`))
+					case readyEndpoint:
+						if restarting && tt.notReady {
+							tt.notReady = false
+							w.Write([]byte(`{"status": "booting"}`))
+							return
+						}
+						w.Write([]byte(`{"status": "ready"}`))
+					case brokersEndpoint + "/2/maintenance":
+						calls = append(calls, r.Method+" maintenance")
+					case restartEndpoint:
+						calls = append(calls, r.Method+" restart")
+						if tt.unsupported {
+							w.WriteHeader(http.StatusNotFound)
+							return
+						}
+						restarting = true
+					case brokersEndpoint + "/2":
+						i := polls
+						if i >= len(tt.brokers) {
+							i = len(tt.brokers) - 1
+						}
+						polls++
+						w.Write([]byte(tt.brokers[i]))
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			timeout := 5 * time.Second
+			if tt.canceled {
+				timeout = 50 * time.Millisecond
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			err = adminClient.DrainAndRestartBroker(ctx, 2, time.Millisecond)
+			switch {
+			case tt.unsupported:
+				var rue *RestartUnsupportedError
+				require.True(t, errors.As(err, &rue), "got %v", err)
+			case tt.canceled:
+				require.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tt.dropped, dropped)
+			}
+			require.Equal(t, tt.expCalls, calls)
+		})
+	}
+}
+
+func TestWaitBrokerDownGrace(t *testing.T) {
+	// The broker restarted between polls, so it's never seen going down.
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case nodeConfigEndpoint:
+				w.Write([]byte(`{"node_id": 2}`))
+			case readyEndpoint:
+				w.Write([]byte(`{"status": "ready"}`))
+			case brokersEndpoint + "/2":
+				w.Write([]byte(`{"node_id": 2, "membership_status": "active", "is_alive": true}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = adminClient.waitBrokerDown(ctx, 2, time.Millisecond, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err())
+}
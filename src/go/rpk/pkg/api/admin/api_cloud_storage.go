@@ -0,0 +1,144 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const cloudStorageEndpoint = "/v1/cloud_storage"
+
+// CloudStorageStatus is the tiered storage status of the cluster. If tiered
+// storage is not enabled, only Enabled is set.
+//
+// The admin API does not report how many bytes are pending upload, so there
+// is no such figure here: whether uploads are caught up is instead derived
+// from the offsets each partition uploaded, see
+// PartitionCloudStorageStatus.CaughtUp.
+type CloudStorageStatus struct {
+	Enabled bool
+	// RecoveryState is the state of the topic recovery from cloud
+	// storage, e.g. "inactive" when no recovery is in progress.
+	RecoveryState string
+	// UploadsCaughtUp is whether every partition that uploads to cloud
+	// storage has uploaded all of its local data.
+	UploadsCaughtUp bool
+	// Partitions is the status of every partition that uploads to cloud
+	// storage.
+	Partitions []PartitionCloudStorageStatus
+}
+
+// Recovering returns whether a topic recovery from cloud storage is in
+// progress.
+func (s CloudStorageStatus) Recovering() bool {
+	return s.RecoveryState != "" && s.RecoveryState != "inactive"
+}
+
+// PartitionCloudStorageStatus is the tiered storage status of a partition.
+// Offsets that the server does not report, e.g. because nothing was
+// uploaded yet, are nil.
+type PartitionCloudStorageStatus struct {
+	Topic     string `json:"-"`
+	Partition int    `json:"-"`
+
+	// Mode is how the partition uses cloud storage, e.g. "full" or
+	// "disabled".
+	Mode                      string `json:"cloud_storage_mode"`
+	MsSinceLastManifestUpload *int64 `json:"ms_since_last_manifest_upload"`
+	MsSinceLastSegmentUpload  *int64 `json:"ms_since_last_segment_upload"`
+	// MetadataUpdatePending is whether the partition has changes to its
+	// cloud storage manifest that were not uploaded yet.
+	MetadataUpdatePending bool   `json:"metadata_update_pending"`
+	CloudLogSizeBytes     int64  `json:"cloud_log_size_bytes"`
+	LocalLogSizeBytes     int64  `json:"local_log_size_bytes"`
+	CloudLogLastOffset    *int64 `json:"cloud_log_last_offset"`
+	LocalLogLastOffset    *int64 `json:"local_log_last_offset"`
+}
+
+// CaughtUp returns whether the partition uploaded all of its local data and
+// metadata.
+func (p PartitionCloudStorageStatus) CaughtUp() bool {
+	if p.MetadataUpdatePending {
+		return false
+	}
+	if p.LocalLogLastOffset == nil {
+		return true
+	}
+	return p.CloudLogLastOffset != nil && *p.CloudLogLastOffset >= *p.LocalLogLastOffset
+}
+
+// PartitionCloudStorageStatus returns the tiered storage status of a
+// partition of a Kafka topic.
+func (a *AdminAPI) PartitionCloudStorageStatus(
+	ctx context.Context, topic string, partition int,
+) (PartitionCloudStorageStatus, error) {
+	path := fmt.Sprintf(
+		"%s/status/%s/%d", cloudStorageEndpoint, url.PathEscape(topic), partition,
+	)
+	s := PartitionCloudStorageStatus{Topic: topic, Partition: partition}
+	return s, a.sendAny(ctx, http.MethodGet, path, nil, &s)
+}
+
+// CloudStorageStatus returns the tiered storage status of the cluster, which
+// is built from the status of every partition of every Kafka topic. If
+// tiered storage is not enabled, this returns a status with Enabled false.
+//
+// The admin API has no cluster-wide cloud storage status endpoint, so this
+// issues one request per partition, after listing the partitions. On
+// clusters with many partitions, prefer PartitionCloudStorageStatus for the
+// partitions of interest.
+func (a *AdminAPI) CloudStorageStatus(ctx context.Context) (CloudStorageStatus, error) {
+	var s CloudStorageStatus
+	cfg, err := a.ClusterConfig(ctx)
+	if err != nil {
+		return s, fmt.Errorf("unable to check whether cloud storage is enabled: %w", err)
+	}
+	if enabled, _ := cfg["cloud_storage_enabled"].(bool); !enabled {
+		return s, nil
+	}
+	s.Enabled = true
+
+	var recovery struct {
+		State string `json:"state"`
+	}
+	if err := a.sendAny(ctx, http.MethodGet, cloudStorageEndpoint+"/topic_recovery", nil, &recovery); err != nil {
+		return s, fmt.Errorf("unable to get the cloud storage topic recovery status: %w", err)
+	}
+	s.RecoveryState = recovery.State
+
+	ps, err := a.ClusterPartitions(ctx)
+	if err != nil {
+		return s, err
+	}
+	s.UploadsCaughtUp = true
+	for _, p := range ps {
+		if p.Namespace != "kafka" {
+			continue
+		}
+		status, err := a.PartitionCloudStorageStatus(ctx, p.Topic, p.PartitionID)
+		if err != nil {
+			// Partitions of topics that do not use cloud storage
+			// have no status.
+			if IsNotFound(err) {
+				continue
+			}
+			return s, err
+		}
+		if status.Mode == "disabled" {
+			continue
+		}
+		s.Partitions = append(s.Partitions, status)
+		s.UploadsCaughtUp = s.UploadsCaughtUp && status.CaughtUp()
+	}
+	return s, nil
+}
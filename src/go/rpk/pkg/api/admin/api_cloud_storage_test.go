@@ -0,0 +1,114 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudStorageStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   string
+		foo0      string
+		expStatus CloudStorageStatus
+	}{
+		{
+			name:      "not enabled",
+			enabled:   "false",
+			expStatus: CloudStorageStatus{},
+		},
+		{
+			name:    "caught up",
+			enabled: "true",
+			foo0:    `{"cloud_storage_mode": "full", "metadata_update_pending": false, "cloud_log_last_offset": 10, "local_log_last_offset": 10}`,
+			expStatus: CloudStorageStatus{
+				Enabled:         true,
+				RecoveryState:   "inactive",
+				UploadsCaughtUp: true,
+			},
+		},
+		{
+			name:    "behind",
+			enabled: "true",
+			foo0:    `{"cloud_storage_mode": "full", "metadata_update_pending": false, "cloud_log_last_offset": 7, "local_log_last_offset": 10}`,
+			expStatus: CloudStorageStatus{
+				Enabled:       true,
+				RecoveryState: "inactive",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case clusterConfigEndpoint:
+						w.Write([]byte(`{"cloud_storage_enabled": ` + tt.enabled + `}`))
+					case cloudStorageEndpoint + "/topic_recovery":
+						w.Write([]byte(`{"state": "inactive"}`))
+					case clusterPartitionsEndpoint:
+						w.Write([]byte(`[
+  {"ns": "kafka", "topic": "foo", "partition_id": 0},
+  {"ns": "kafka", "topic": "local", "partition_id": 0},
+  {"ns": "kafka", "topic": "bar", "partition_id": 0}
+]`))
+					case cloudStorageEndpoint + "/status/foo/0":
+						w.Write([]byte(tt.foo0))
+					case cloudStorageEndpoint + "/status/local/0":
+						w.Write([]byte(`{"cloud_storage_mode": "disabled"}`))
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+
+			s, err := adminClient.CloudStorageStatus(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.expStatus.Enabled, s.Enabled)
+			require.Equal(t, tt.expStatus.RecoveryState, s.RecoveryState)
+			require.Equal(t, tt.expStatus.UploadsCaughtUp, s.UploadsCaughtUp)
+			require.False(t, s.Recovering())
+			if !s.Enabled {
+				require.Empty(t, s.Partitions)
+				return
+			}
+			require.Len(t, s.Partitions, 1)
+			require.Equal(t, "foo", s.Partitions[0].Topic)
+			require.Equal(t, 0, s.Partitions[0].Partition)
+			require.Equal(t, "full", s.Partitions[0].Mode)
+		})
+	}
+}
+
+func TestPartitionCloudStorageStatusCaughtUp(t *testing.T) {
+	offset := func(o int64) *int64 { return &o }
+	for _, tt := range []struct {
+		status PartitionCloudStorageStatus
+		exp    bool
+	}{
+		{PartitionCloudStorageStatus{}, true},
+		{PartitionCloudStorageStatus{MetadataUpdatePending: true}, false},
+		{PartitionCloudStorageStatus{LocalLogLastOffset: offset(3)}, false},
+		{PartitionCloudStorageStatus{LocalLogLastOffset: offset(3), CloudLogLastOffset: offset(2)}, false},
+		{PartitionCloudStorageStatus{LocalLogLastOffset: offset(3), CloudLogLastOffset: offset(3)}, true},
+	} {
+		require.Equal(t, tt.exp, tt.status.CaughtUp(), "%+v", tt.status)
+	}
+}
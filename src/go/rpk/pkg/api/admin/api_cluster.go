@@ -0,0 +1,166 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	clusterHealthEndpoint = "/v1/cluster/health_overview"
+	readyEndpoint         = "/v1/status/ready"
+)
+
+// DefaultHealthPoll is the default interval at which WaitForHealthy polls the
+// cluster.
+const DefaultHealthPoll = time.Second
+
+// ClusterHealth is the health overview returned from the Redpanda admin
+// cluster health endpoint. Fields that the server does not report are left
+// zero.
+type ClusterHealth struct {
+	IsHealthy bool `json:"is_healthy"`
+	// ControllerID is the node ID of the controller leader, or -1 if there
+	// is no leader.
+	ControllerID int   `json:"controller_id"`
+	AllNodes     []int `json:"all_nodes"`
+	NodesDown    []int `json:"nodes_down"`
+
+	// LeaderlessPartitions are the partitions without a leader, in the
+	// form namespace/topic/partition. The server may truncate this list,
+	// in which case LeaderlessCount is the full count.
+	LeaderlessPartitions []string `json:"leaderless_partitions"`
+	LeaderlessCount      int      `json:"leaderless_count"`
+	// UnderReplicatedCount is the number of partitions with fewer
+	// replicas than their replication factor.
+	UnderReplicatedCount int `json:"under_replicated_count"`
+}
+
+// ClusterHealth queries one of the client's hosts and returns the health
+// overview of the cluster.
+func (a *AdminAPI) ClusterHealth(ctx context.Context) (ClusterHealth, error) {
+	var h ClusterHealth
+	err := a.sendAny(ctx, http.MethodGet, clusterHealthEndpoint, nil, &h)
+	// Older versions only report the list of leaderless partitions.
+	if h.LeaderlessCount == 0 {
+		h.LeaderlessCount = len(h.LeaderlessPartitions)
+	}
+	return h, err
+}
+
+// WaitForHealthy polls the cluster health every poll until the cluster is
+// healthy or ctx is done. If poll is zero or less, DefaultHealthPoll is used.
+//
+// Failing to reach the hosts, as happens while the cluster starts, is
+// retried; any other error is returned immediately. If ctx is done first,
+// this returns a *ClusterUnhealthyError with the last health observed.
+func (a *AdminAPI) WaitForHealthy(ctx context.Context, poll time.Duration) error {
+	if poll <= 0 {
+		poll = DefaultHealthPoll
+	}
+	unhealthy := new(ClusterUnhealthyError)
+	for {
+		h, err := a.ClusterHealth(ctx)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			unhealthy.ctxErr = ctxErr
+			return unhealthy
+		}
+		if err != nil {
+			var me *multierror.Error
+			if !errors.As(err, &me) && !isHostFailure(err) {
+				return err
+			}
+			unhealthy.Err = err
+		} else {
+			if h.IsHealthy {
+				return nil
+			}
+			unhealthy.Health, unhealthy.Err = &h, nil
+		}
+
+		if err := sleepCtx(ctx, poll); err != nil {
+			unhealthy.ctxErr = err
+			return unhealthy
+		}
+	}
+}
+
+// Ready queries one of the client's hosts and returns whether its node has
+// finished starting and is serving requests.
+func (a *AdminAPI) Ready(ctx context.Context) (bool, error) {
+	var status struct {
+		Status string `json:"status"`
+	}
+	err := a.sendAny(ctx, http.MethodGet, readyEndpoint, nil, &status)
+	return status.Status == "ready", err
+}
+
+// WaitForReady polls the readiness of the client's hosts every poll until
+// one of them is ready or ctx is done. If poll is zero or less,
+// DefaultHealthPoll is used.
+//
+// As in WaitForHealthy, failing to reach the hosts is retried, and any other
+// error is returned immediately. A node that is still starting responds with
+// a server error, so it's retried too.
+func (a *AdminAPI) WaitForReady(ctx context.Context, poll time.Duration) error {
+	if poll <= 0 {
+		poll = DefaultHealthPoll
+	}
+	var lastErr error
+	for {
+		ready, err := a.Ready(ctx)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if lastErr != nil {
+				return fmt.Errorf("node did not become ready: %w, last error: %v", ctxErr, lastErr)
+			}
+			return fmt.Errorf("node did not become ready: %w", ctxErr)
+		}
+		if err != nil {
+			var me *multierror.Error
+			if !errors.As(err, &me) && !isHostFailure(err) {
+				return err
+			}
+		} else if ready {
+			return nil
+		}
+		lastErr = err
+
+		if err := sleepCtx(ctx, poll); err != nil {
+			return fmt.Errorf("node did not become ready: %w", err)
+		}
+	}
+}
+
+// GetController returns the broker that is the controller leader. If no
+// leader is currently elected, this returns ErrNoController.
+func (a *AdminAPI) GetController(ctx context.Context) (Broker, error) {
+	id, err := a.controllerID(ctx)
+	if err != nil {
+		return Broker{}, err
+	}
+	return a.Broker(ctx, id)
+}
+
+func (a *AdminAPI) controllerID(ctx context.Context) (int, error) {
+	p, err := a.partition(ctx, "redpanda", "controller", 0)
+	if err != nil {
+		return 0, fmt.Errorf("unable to query the controller partition: %w", err)
+	}
+	if p.LeaderID < 0 {
+		return 0, ErrNoController
+	}
+	return p.LeaderID, nil
+}
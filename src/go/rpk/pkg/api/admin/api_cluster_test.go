@@ -0,0 +1,260 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterHealth(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		exp  ClusterHealth
+	}{
+		{
+			name: "healthy",
+			body: `{"is_healthy": true, "controller_id": 1, "all_nodes": [0, 1, 2], "nodes_down": [], "leaderless_partitions": [], "leaderless_count": 0, "under_replicated_count": 0}`,
+			exp: ClusterHealth{
+				IsHealthy:            true,
+				ControllerID:         1,
+				AllNodes:             []int{0, 1, 2},
+				NodesDown:            []int{},
+				LeaderlessPartitions: []string{},
+			},
+		},
+		{
+			name: "unhealthy",
+			body: `{"is_healthy": false, "controller_id": -1, "all_nodes": [0, 1, 2], "nodes_down": [2], "leaderless_partitions": ["kafka/foo/0"], "leaderless_count": 3, "under_replicated_count": 4}`,
+			exp: ClusterHealth{
+				ControllerID:         -1,
+				AllNodes:             []int{0, 1, 2},
+				NodesDown:            []int{2},
+				LeaderlessPartitions: []string{"kafka/foo/0"},
+				LeaderlessCount:      3,
+				UnderReplicatedCount: 4,
+			},
+		},
+		{
+			name: "older server",
+			body: `{"is_healthy": false, "controller_id": 0, "all_nodes": [0], "nodes_down": [], "leaderless_partitions": ["kafka/foo/0", "kafka/foo/1"]}`,
+			exp: ClusterHealth{
+				AllNodes:             []int{0},
+				NodesDown:            []int{},
+				LeaderlessPartitions: []string{"kafka/foo/0", "kafka/foo/1"},
+				LeaderlessCount:      2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, clusterHealthEndpoint, r.URL.Path)
+					w.Write([]byte(tt.body))
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			h, err := adminClient.ClusterHealth(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, h)
+		})
+	}
+}
+
+func TestGetController(t *testing.T) {
+	var (
+		leader  int32 = 1
+		decomms [2]int32
+	)
+	newNode := func(id int) *httptest.Server {
+		return httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case nodeConfigEndpoint:
+					fmt.Fprintf(w, `{"node_id": %d}`, id)
+				case "/v1/partitions/redpanda/controller/0":
+					fmt.Fprintf(w, `{"ns": "redpanda", "topic": "controller", "leader_id": %d}`, atomic.LoadInt32(&leader))
+				case "/v1/brokers/1":
+					w.Write([]byte(`{"node_id": 1, "num_cores": 4}`))
+				case "/v1/brokers/0/decommission":
+					atomic.AddInt32(&decomms[id], 1)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}),
+		)
+	}
+	n0, n1 := newNode(0), newNode(1)
+	defer n0.Close()
+	defer n1.Close()
+	urls := []string{n0.URL, n1.URL}
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI(urls, nil)
+	require.NoError(t, err)
+	b, err := adminClient.GetController(ctx)
+	require.NoError(t, err)
+	require.Equal(t, Broker{NodeID: 1, NumCores: 4}, b)
+
+	adminClient, err = NewAdminAPI(urls, nil, WithControllerOnly())
+	require.NoError(t, err)
+	require.NoError(t, adminClient.DecommissionBroker(ctx, 0))
+	require.EqualValues(t, 0, atomic.LoadInt32(&decomms[0]))
+	require.EqualValues(t, 1, atomic.LoadInt32(&decomms[1]))
+
+	atomic.StoreInt32(&leader, -1)
+	_, err = adminClient.GetController(ctx)
+	require.True(t, errors.Is(err, ErrNoController), "got %v", err)
+	err = adminClient.DecommissionBroker(ctx, 0)
+	require.True(t, errors.Is(err, ErrNoController), "got %v", err)
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch atomic.AddInt32(&hits, 1) {
+			case 1:
+				// The admin server is still starting.
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case 2:
+				w.Write([]byte(`{"is_healthy": false, "nodes_down": [2]}`))
+			default:
+				w.Write([]byte(`{"is_healthy": true}`))
+			}
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	err = adminClient.WaitForHealthy(context.Background(), time.Millisecond)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, atomic.LoadInt32(&hits))
+}
+
+func TestWaitForHealthyTimeout(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"is_healthy": false, "nodes_down": [2], "leaderless_partitions": ["kafka/foo/0"]}`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = adminClient.WaitForHealthy(ctx, time.Millisecond)
+
+	var ue *ClusterUnhealthyError
+	require.True(t, errors.As(err, &ue))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.NotNil(t, ue.Health)
+	require.Equal(t, []int{2}, ue.Health.NodesDown)
+	require.Equal(t, 1, ue.Health.LeaderlessCount)
+	require.Contains(t, err.Error(), "1 nodes down, 1 leaderless partitions")
+}
+
+func TestWaitForHealthyClientError(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	err = adminClient.WaitForHealthy(context.Background(), time.Millisecond)
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he))
+	require.Equal(t, http.StatusUnauthorized, he.StatusCode)
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits))
+}
+
+func TestWaitForReady(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v1/status/ready", r.URL.Path)
+			switch atomic.AddInt32(&hits, 1) {
+			case 1:
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"status": "booting"}`))
+			default:
+				w.Write([]byte(`{"status": "ready"}`))
+			}
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	err = adminClient.WaitForReady(context.Background(), time.Millisecond)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits))
+}
+
+func TestWaitForReadyTimeout(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status": "booting"}`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = adminClient.WaitForReady(ctx, time.Millisecond)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Contains(t, err.Error(), "node did not become ready")
+	require.Contains(t, err.Error(), "last error")
+}
+
+func TestWaitForReadyClientError(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	err = adminClient.WaitForReady(context.Background(), time.Millisecond)
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he))
+	require.Equal(t, http.StatusUnauthorized, he.StatusCode)
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"sort"
+)
+
+const clusterConfigEndpoint = "/v1/cluster_config"
+
+// ClusterConfigWriteResult is the result of a cluster configuration write.
+type ClusterConfigWriteResult struct {
+	// ConfigVersion is the cluster configuration version that contains
+	// the write. Comparing it against ClusterConfigStatus shows which
+	// nodes have applied the write.
+	ConfigVersion int `json:"config_version"`
+}
+
+// ClusterConfigNodeStatus is the cluster configuration status of a single
+// node.
+type ClusterConfigNodeStatus struct {
+	NodeID int `json:"node_id"`
+	// Restart is whether the node must be restarted for some of the
+	// applied configuration to take effect.
+	Restart bool `json:"restart"`
+	// ConfigVersion is the latest cluster configuration version the node
+	// has applied.
+	ConfigVersion int `json:"config_version"`
+	// Invalid are the properties the node rejected as invalid.
+	Invalid []string `json:"invalid"`
+	// Unknown are the properties the node does not recognize.
+	Unknown []string `json:"unknown"`
+}
+
+type clusterConfigWrite struct {
+	Upsert map[string]interface{} `json:"upsert"`
+	Remove []string               `json:"remove"`
+}
+
+// ClusterConfig returns the current cluster configuration properties.
+func (a *AdminAPI) ClusterConfig(ctx context.Context) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	return m, a.sendAny(ctx, http.MethodGet, clusterConfigEndpoint, nil, &m)
+}
+
+// NodeConfig returns the effective node configuration of the given node. The
+// request is sent to the node's own host, which must be one of the client's
+// hosts.
+func (a *AdminAPI) NodeConfig(ctx context.Context, node int) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	return m, a.sendToNode(ctx, node, http.MethodGet, nodeConfigEndpoint, nil, &m)
+}
+
+// NodeID queries one of the client's hosts and returns the ID of its node.
+func (a *AdminAPI) NodeID(ctx context.Context) (int, error) {
+	var nc struct {
+		NodeID int `json:"node_id"`
+	}
+	return nc.NodeID, a.sendAny(ctx, http.MethodGet, nodeConfigEndpoint, nil, &nc)
+}
+
+// SetClusterConfig sets the properties in upsert and resets the properties
+// in remove to their defaults, in a single write.
+func (a *AdminAPI) SetClusterConfig(
+	ctx context.Context, upsert map[string]interface{}, remove []string,
+) (ClusterConfigWriteResult, error) {
+	if upsert == nil {
+		upsert = make(map[string]interface{})
+	}
+	if remove == nil {
+		remove = []string{}
+	}
+	var res ClusterConfigWriteResult
+	return res, a.sendAny(
+		ctx,
+		http.MethodPut,
+		clusterConfigEndpoint,
+		clusterConfigWrite{upsert, remove},
+		&res,
+	)
+}
+
+// ClusterConfigStatus returns the cluster configuration status of each node,
+// sorted by node ID.
+func (a *AdminAPI) ClusterConfigStatus(ctx context.Context) ([]ClusterConfigNodeStatus, error) {
+	var ss []ClusterConfigNodeStatus
+	defer func() {
+		sort.Slice(ss, func(i, j int) bool { return ss[i].NodeID < ss[j].NodeID })
+	}()
+	return ss, a.sendAny(ctx, http.MethodGet, clusterConfigEndpoint+"/status", nil, &ss)
+}
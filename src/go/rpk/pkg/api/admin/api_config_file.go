@@ -0,0 +1,214 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/icza/dyno"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// ClusterConfigChange is a change to a cluster configuration property that
+// SetClusterConfigFromFile makes, or would make on a dry run.
+type ClusterConfigChange struct {
+	Property string `json:"property" yaml:"property"`
+	// Current is the property's live value.
+	Current interface{} `json:"current" yaml:"current"`
+	// Value is the property's value in the file, which is nil if the
+	// property is reset to its default.
+	Value interface{} `json:"value" yaml:"value"`
+	Reset bool        `json:"reset" yaml:"reset"`
+}
+
+// ClusterConfigFileResult is the result of SetClusterConfigFromFile.
+type ClusterConfigFileResult struct {
+	// Changes are the properties whose live value differs from the file's,
+	// sorted by property.
+	Changes []ClusterConfigChange `json:"changes" yaml:"changes"`
+	// ConfigVersion is the cluster configuration version that contains
+	// the changes. It's zero on a dry run, or if there was nothing to
+	// change.
+	ConfigVersion int  `json:"config_version" yaml:"config_version"`
+	DryRun        bool `json:"dry_run" yaml:"dry_run"`
+}
+
+// InvalidClusterConfigError is returned from SetClusterConfigFromFile if the
+// file has properties that the cluster doesn't have, or that the cluster
+// rejected.
+type InvalidClusterConfigError struct {
+	// Properties are the invalid properties and why each is invalid.
+	Properties map[string]string
+
+	err error
+}
+
+func (e *InvalidClusterConfigError) Error() string {
+	props := make([]string, 0, len(e.Properties))
+	for p, reason := range e.Properties {
+		props = append(props, fmt.Sprintf("%s: %s", p, reason))
+	}
+	sort.Strings(props)
+	return "invalid cluster configuration properties: " + strings.Join(props, "; ")
+}
+
+// Unwrap returns the underlying *HTTPResponseError, if the cluster rejected
+// the properties.
+func (e *InvalidClusterConfigError) Unwrap() error {
+	return e.err
+}
+
+// SetClusterConfigFromFile sets the cluster configuration to the properties
+// in the given YAML file, in a single write. Only the properties whose live
+// value differs from the file's are written, and the properties set to null
+// in the file are reset to their defaults, unless they're already at them.
+// The properties that aren't in the file are left as they are.
+//
+// On a dry run, the changes are returned without writing them. If the file
+// has properties that the live configuration doesn't, or the cluster rejects
+// some properties, this returns an *InvalidClusterConfigError.
+func (a *AdminAPI) SetClusterConfigFromFile(
+	ctx context.Context, fs afero.Fs, path string, dryRun bool,
+) (ClusterConfigFileResult, error) {
+	res := ClusterConfigFileResult{DryRun: dryRun}
+	desired, err := readClusterConfigFile(fs, path)
+	if err != nil {
+		return res, err
+	}
+	live, err := a.ClusterConfig(ctx)
+	if err != nil {
+		return res, err
+	}
+	var set map[string]interface{}
+	for _, v := range desired {
+		if v == nil {
+			set, err = a.setClusterConfig(ctx)
+			if err != nil {
+				return res, err
+			}
+			break
+		}
+	}
+	upsert, remove, changes, err := diffClusterConfig(live, set, desired)
+	res.Changes = changes
+	if err != nil || dryRun || len(changes) == 0 {
+		return res, err
+	}
+	w, err := a.SetClusterConfig(ctx, upsert, remove)
+	if err != nil {
+		return res, invalidClusterConfig(err)
+	}
+	res.ConfigVersion = w.ConfigVersion
+	return res, nil
+}
+
+// setClusterConfig returns the cluster configuration properties that are set,
+// i.e. that aren't at their defaults.
+func (a *AdminAPI) setClusterConfig(ctx context.Context) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	path := clusterConfigEndpoint + "?include_defaults=false"
+	return m, a.sendAny(ctx, http.MethodGet, path, nil, &m)
+}
+
+func readClusterConfigFile(fs afero.Fs, path string) (map[string]interface{}, error) {
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the cluster configuration file: %v", err)
+	}
+	var m map[string]interface{}
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", path, err)
+	}
+	if len(m) == 0 {
+		return nil, fmt.Errorf("%s has no cluster configuration properties", path)
+	}
+	for k, v := range m {
+		m[k] = dyno.ConvertMapI2MapS(v)
+	}
+	return m, nil
+}
+
+// diffClusterConfig returns the write that changes the live configuration to
+// the desired one, and the changes it makes. Values are compared as they are
+// encoded to JSON, so that 1 in the file is equal to the live 1.0. Properties
+// that are null in the desired configuration are only reset if they're in
+// set, the properties that aren't at their defaults.
+func diffClusterConfig(
+	live, set, desired map[string]interface{},
+) (map[string]interface{}, []string, []ClusterConfigChange, error) {
+	upsert := map[string]interface{}{}
+	remove := []string{}
+	changes := []ClusterConfigChange{}
+	invalid := map[string]string{}
+	for k, v := range desired {
+		current, ok := live[k]
+		if !ok {
+			invalid[k] = "unknown property"
+			continue
+		}
+		if v == nil {
+			if _, ok := set[k]; !ok {
+				continue
+			}
+			remove = append(remove, k)
+			changes = append(changes, ClusterConfigChange{Property: k, Current: current, Reset: true})
+			continue
+		}
+		normalized, err := normalizeJSON(v)
+		if err != nil {
+			invalid[k] = err.Error()
+			continue
+		}
+		if reflect.DeepEqual(normalized, current) {
+			continue
+		}
+		upsert[k] = v
+		changes = append(changes, ClusterConfigChange{Property: k, Current: current, Value: v})
+	}
+	sort.Strings(remove)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Property < changes[j].Property })
+	if len(invalid) > 0 {
+		return nil, nil, changes, &InvalidClusterConfigError{Properties: invalid}
+	}
+	return upsert, remove, changes, nil
+}
+
+func normalizeJSON(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(raw, &normalized)
+	return normalized, err
+}
+
+// invalidClusterConfig returns an *InvalidClusterConfigError if the cluster
+// rejected the write with the reason for each invalid property, and err
+// otherwise.
+func invalidClusterConfig(err error) error {
+	var he *HTTPResponseError
+	if !errors.As(err, &he) || he.StatusCode != http.StatusBadRequest {
+		return err
+	}
+	var props map[string]string
+	if json.Unmarshal(he.Body, &props) != nil || len(props) == 0 {
+		return err
+	}
+	return &InvalidClusterConfigError{Properties: props, err: err}
+}
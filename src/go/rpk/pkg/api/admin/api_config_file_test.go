@@ -0,0 +1,134 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetClusterConfigFromFile(t *testing.T) {
+	const live = `{
+  "log_retention_ms": 1000,
+  "cloud_storage_enabled": false,
+  "auto_create_topics_enabled": true,
+  "kafka_qdc_depth_alpha": 0.8,
+  "kafka_batch_max_bytes": 1048576,
+  "superusers": ["admin"]
+}`
+	// The properties that aren't at their defaults.
+	const set = `{
+  "log_retention_ms": 1000,
+  "cloud_storage_enabled": false,
+  "superusers": ["admin"]
+}`
+	var writes int32
+	reject := false
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == clusterConfigEndpoint:
+				if r.URL.Query().Get("include_defaults") == "false" {
+					w.Write([]byte(set))
+					return
+				}
+				w.Write([]byte(live))
+			case r.Method == http.MethodPut && r.URL.Path == clusterConfigEndpoint:
+				atomic.AddInt32(&writes, 1)
+				if reject {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"log_retention_ms": "expected a positive integer"}`))
+					return
+				}
+				var body map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				require.Equal(t, map[string]interface{}{
+					"upsert": map[string]interface{}{
+						"log_retention_ms": 2000.0,
+						"superusers":       []interface{}{"admin", "ops"},
+					},
+					"remove": []interface{}{"cloud_storage_enabled"},
+				}, body)
+				w.Write([]byte(`{"config_version": 4}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	fs := afero.NewMemMapFs()
+	writeFile := func(content string) string {
+		path := "/etc/redpanda/cluster.yaml"
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+		return path
+	}
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	path := writeFile(`log_retention_ms: 2000
+cloud_storage_enabled: null
+auto_create_topics_enabled: true
+kafka_qdc_depth_alpha: 0.8
+kafka_batch_max_bytes: null
+superusers:
+  - admin
+  - ops
+`)
+	expChanges := []ClusterConfigChange{
+		{Property: "cloud_storage_enabled", Current: false, Reset: true},
+		{Property: "log_retention_ms", Current: 1000.0, Value: 2000},
+		{Property: "superusers", Current: []interface{}{"admin"}, Value: []interface{}{"admin", "ops"}},
+	}
+
+	res, err := adminClient.SetClusterConfigFromFile(ctx, fs, path, true)
+	require.NoError(t, err)
+	require.Equal(t, ClusterConfigFileResult{Changes: expChanges, DryRun: true}, res)
+	require.Zero(t, atomic.LoadInt32(&writes), "a dry run shouldn't write")
+
+	res, err = adminClient.SetClusterConfigFromFile(ctx, fs, path, false)
+	require.NoError(t, err)
+	require.Equal(t, ClusterConfigFileResult{Changes: expChanges, ConfigVersion: 4}, res)
+	require.EqualValues(t, 1, atomic.LoadInt32(&writes))
+
+	// Nothing is written if the file matches the live configuration.
+	res, err = adminClient.SetClusterConfigFromFile(ctx, fs, writeFile("log_retention_ms: 1000\n"), false)
+	require.NoError(t, err)
+	require.Equal(t, ClusterConfigFileResult{Changes: []ClusterConfigChange{}}, res)
+	require.EqualValues(t, 1, atomic.LoadInt32(&writes))
+
+	_, err = adminClient.SetClusterConfigFromFile(ctx, fs, writeFile("log_retention: 1000\n"), false)
+	var invalid *InvalidClusterConfigError
+	require.True(t, errors.As(err, &invalid))
+	require.Equal(t, map[string]string{"log_retention": "unknown property"}, invalid.Properties)
+	require.EqualValues(t, 1, atomic.LoadInt32(&writes))
+
+	reject = true
+	_, err = adminClient.SetClusterConfigFromFile(ctx, fs, writeFile("log_retention_ms: -1\n"), false)
+	require.EqualError(t, err, "invalid cluster configuration properties: log_retention_ms: expected a positive integer")
+	require.True(t, errors.As(err, &invalid))
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he))
+
+	_, err = adminClient.SetClusterConfigFromFile(ctx, fs, writeFile(""), false)
+	require.Error(t, err)
+	_, err = adminClient.SetClusterConfigFromFile(ctx, fs, "/etc/redpanda/missing.yaml", false)
+	require.Error(t, err)
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterConfig(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == clusterConfigEndpoint:
+				w.Write([]byte(`{"log_retention_ms": 1000, "cloud_storage_enabled": false}`))
+			case r.Method == http.MethodPut && r.URL.Path == clusterConfigEndpoint:
+				var body map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				require.Equal(t, map[string]interface{}{
+					"upsert": map[string]interface{}{"log_retention_ms": 2000.0},
+					"remove": []interface{}{"cloud_storage_enabled"},
+				}, body)
+				w.Write([]byte(`{"config_version": 3}`))
+			case r.Method == http.MethodGet && r.URL.Path == clusterConfigEndpoint+"/status":
+				w.Write([]byte(`[
+  {"node_id": 1, "restart": false, "config_version": 2, "invalid": [], "unknown": ["foo"]},
+  {"node_id": 0, "restart": true, "config_version": 3, "invalid": [], "unknown": []}
+]`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	m, err := adminClient.ClusterConfig(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"log_retention_ms":      1000.0,
+		"cloud_storage_enabled": false,
+	}, m)
+
+	res, err := adminClient.SetClusterConfig(
+		ctx,
+		map[string]interface{}{"log_retention_ms": 2000},
+		[]string{"cloud_storage_enabled"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, ClusterConfigWriteResult{ConfigVersion: 3}, res)
+
+	ss, err := adminClient.ClusterConfigStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []ClusterConfigNodeStatus{
+		{NodeID: 0, Restart: true, ConfigVersion: 3, Invalid: []string{}, Unknown: []string{}},
+		{NodeID: 1, ConfigVersion: 2, Invalid: []string{}, Unknown: []string{"foo"}},
+	}, ss)
+}
+
+func TestNodeConfig(t *testing.T) {
+	newNode := func(id int) *httptest.Server {
+		return httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"node_id": %d, "data_directory": "/var/lib/redpanda/data-%d"}`, id, id)
+			}),
+		)
+	}
+	n0, n1 := newNode(0), newNode(1)
+	defer n0.Close()
+	defer n1.Close()
+
+	adminClient, err := NewAdminAPI([]string{n0.URL, n1.URL}, nil)
+	require.NoError(t, err)
+
+	for _, node := range []int{1, 0} {
+		cfg, err := adminClient.NodeConfig(context.Background(), node)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"node_id":        float64(node),
+			"data_directory": fmt.Sprintf("/var/lib/redpanda/data-%d", node),
+		}, cfg)
+	}
+
+	_, err = adminClient.NodeConfig(context.Background(), 2)
+	require.EqualError(t, err, "unknown admin address of node 2: none of the admin hosts is that node")
+}
+
+func TestNodeID(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v1/node_config", r.URL.Path)
+			w.Write([]byte(`{"node_id": 3, "data_directory": "/var/lib/redpanda/data"}`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	id, err := adminClient.NodeID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, id)
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const featuresEndpoint = "/v1/features"
+
+// License is the enterprise license status of the cluster. A cluster without
+// a license, including one whose version does not support licenses, has
+// Loaded false and every other field empty.
+type License struct {
+	Loaded       bool
+	Type         string
+	Organization string
+	// ExpiresUnix is when the license expires, in seconds since the
+	// epoch.
+	ExpiresUnix int64
+	// Expired is whether the license had expired when it was queried.
+	Expired bool
+}
+
+// ExpiresIn returns the time left until the license expires, which is
+// negative if it already has.
+func (l License) ExpiresIn() time.Duration {
+	return time.Until(time.Unix(l.ExpiresUnix, 0))
+}
+
+type licenseResponse struct {
+	Loaded  bool `json:"loaded"`
+	License struct {
+		Org     string `json:"org"`
+		Type    string `json:"type"`
+		Expires int64  `json:"expires"`
+	} `json:"license"`
+}
+
+// License returns the enterprise license status of the cluster.
+func (a *AdminAPI) License(ctx context.Context) (License, error) {
+	var res licenseResponse
+	err := a.sendAny(ctx, http.MethodGet, featuresEndpoint+"/license", nil, &res)
+	if IsNotFound(err) {
+		return License{}, nil
+	}
+	if err != nil || !res.Loaded {
+		return License{}, err
+	}
+	return License{
+		Loaded:       true,
+		Type:         res.License.Type,
+		Organization: res.License.Org,
+		ExpiresUnix:  res.License.Expires,
+		Expired:      time.Now().Unix() >= res.License.Expires,
+	}, nil
+}
+
+// FeatureState is the state of a cluster feature.
+type FeatureState string
+
+const (
+	FeatureActive    FeatureState = "active"
+	FeatureAvailable FeatureState = "available"
+	FeatureDisabled  FeatureState = "disabled"
+)
+
+// Feature is a cluster feature, as returned from the Redpanda admin features
+// endpoint.
+type Feature struct {
+	Name  string       `json:"name"`
+	State FeatureState `json:"state"`
+	// AvailableFromVersion is the cluster logical version the feature is
+	// available from, if the server reports it.
+	AvailableFromVersion int `json:"available_from_version,omitempty"`
+}
+
+type featuresResponse struct {
+	Features []Feature `json:"features"`
+}
+
+// Features returns the features of the cluster, sorted by name.
+func (a *AdminAPI) Features(ctx context.Context) ([]Feature, error) {
+	var res featuresResponse
+	if err := a.sendAny(ctx, http.MethodGet, featuresEndpoint, nil, &res); err != nil {
+		return nil, err
+	}
+	sort.Slice(res.Features, func(i, j int) bool {
+		return res.Features[i].Name < res.Features[j].Name
+	})
+	return res.Features, nil
+}
+
+// IsFeatureActive returns whether the named feature is active. A feature
+// that the cluster does not know of is not active.
+func (a *AdminAPI) IsFeatureActive(ctx context.Context, name string) (bool, error) {
+	fs, err := a.Features(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range fs {
+		if f.Name == name {
+			return f.State == FeatureActive, nil
+		}
+	}
+	return false, nil
+}
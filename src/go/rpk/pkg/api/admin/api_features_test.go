@@ -0,0 +1,103 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicense(t *testing.T) {
+	future := time.Now().Add(40 * 24 * time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+	licensed := func(expires int64) string {
+		return fmt.Sprintf(`{"loaded": true, "license": {"format_version": 0, "org": "acme", "type": "enterprise", "expires": %d}}`, expires)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		exp    License
+	}{
+		{
+			name: "valid",
+			body: licensed(future),
+			exp:  License{Loaded: true, Type: "enterprise", Organization: "acme", ExpiresUnix: future},
+		},
+		{
+			name: "expired",
+			body: licensed(past),
+			exp:  License{Loaded: true, Type: "enterprise", Organization: "acme", ExpiresUnix: past, Expired: true},
+		},
+		{name: "not loaded", body: `{"loaded": false}`},
+		{name: "unsupported", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, "/v1/features/license", r.URL.Path)
+					if tt.status != 0 {
+						w.WriteHeader(tt.status)
+						return
+					}
+					w.Write([]byte(tt.body))
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			l, err := adminClient.License(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, l)
+		})
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, featuresEndpoint, r.URL.Path)
+			w.Write([]byte(`{"cluster_version": 3, "features": [
+  {"name": "maintenance_mode", "state": "available", "was_active": false},
+  {"name": "central_config", "state": "active", "was_active": true, "available_from_version": 2}
+]}`))
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+	fs, err := adminClient.Features(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Feature{
+		{Name: "central_config", State: FeatureActive, AvailableFromVersion: 2},
+		{Name: "maintenance_mode", State: FeatureAvailable},
+	}, fs)
+
+	for name, exp := range map[string]bool{
+		"central_config":   true,
+		"maintenance_mode": false,
+		"unknown":          false,
+	} {
+		active, err := adminClient.IsFeatureActive(ctx, name)
+		require.NoError(t, err)
+		require.Equal(t, exp, active, "feature %s", name)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// ListenerInfo is a Kafka listener that a node serves.
+type ListenerInfo struct {
+	// Name is the name of the listener, which may be empty.
+	Name string `json:"name"`
+	// Address is the host:port that the listener is bound to.
+	Address string `json:"address"`
+	// AdvertisedAddress is the host:port that the node advertises to
+	// clients for the listener, or empty if the node does not advertise
+	// the listener.
+	AdvertisedAddress string `json:"advertised_address"`
+	// TLS is whether the listener requires TLS.
+	TLS bool `json:"tls"`
+	// RequireClientAuth is whether the listener requires clients to
+	// present a certificate, i.e. mTLS.
+	RequireClientAuth bool `json:"require_client_auth"`
+}
+
+type nodeListenerAddress struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+}
+
+func (a nodeListenerAddress) hostPort() string {
+	return net.JoinHostPort(a.Address, strconv.Itoa(a.Port))
+}
+
+type nodeListenerTLS struct {
+	Name              string `json:"name"`
+	Enabled           bool   `json:"enabled"`
+	RequireClientAuth bool   `json:"require_client_auth"`
+}
+
+type nodeListenerConfig struct {
+	KafkaAPI           []nodeListenerAddress `json:"kafka_api"`
+	AdvertisedKafkaAPI []nodeListenerAddress `json:"advertised_kafka_api"`
+	KafkaAPITLS        []nodeListenerTLS     `json:"kafka_api_tls"`
+}
+
+// KafkaListeners returns the Kafka listeners of the given node, in the order
+// of the node's configuration, as read from the node's effective
+// configuration. Listeners are node-local, so the request is sent to the
+// node's own host, which must be one of the client's hosts.
+//
+// If the node advertises no Kafka addresses at all, it advertises the
+// addresses it's bound to, which are then returned as the advertised
+// addresses.
+func (a *AdminAPI) KafkaListeners(ctx context.Context, node int) ([]ListenerInfo, error) {
+	var nc nodeListenerConfig
+	if err := a.sendToNode(ctx, node, http.MethodGet, nodeConfigEndpoint, nil, &nc); err != nil {
+		return nil, err
+	}
+	return kafkaListeners(nc), nil
+}
+
+func kafkaListeners(nc nodeListenerConfig) []ListenerInfo {
+	advertised := make(map[string]string, len(nc.AdvertisedKafkaAPI))
+	for _, a := range nc.AdvertisedKafkaAPI {
+		advertised[a.Name] = a.hostPort()
+	}
+	tls := make(map[string]nodeListenerTLS, len(nc.KafkaAPITLS))
+	for _, t := range nc.KafkaAPITLS {
+		tls[t.Name] = t
+	}
+
+	listeners := make([]ListenerInfo, 0, len(nc.KafkaAPI))
+	for _, l := range nc.KafkaAPI {
+		info := ListenerInfo{
+			Name:              l.Name,
+			Address:           l.hostPort(),
+			AdvertisedAddress: advertised[l.Name],
+		}
+		if len(nc.AdvertisedKafkaAPI) == 0 {
+			info.AdvertisedAddress = info.Address
+		}
+		if t, ok := tls[l.Name]; ok && t.Enabled {
+			info.TLS = true
+			info.RequireClientAuth = t.RequireClientAuth
+		}
+		listeners = append(listeners, info)
+	}
+	return listeners
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaListeners(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodeConfig string
+		expected   []ListenerInfo
+	}{
+		{
+			name: "it should pair each listener with its advertised address and TLS",
+			nodeConfig: `{
+  "node_id": 1,
+  "kafka_api": [
+    {"name": "internal", "address": "0.0.0.0", "port": 9092},
+    {"name": "external", "address": "0.0.0.0", "port": 19092}
+  ],
+  "advertised_kafka_api": [
+    {"name": "internal", "address": "redpanda-1", "port": 9092}
+  ],
+  "kafka_api_tls": [
+    {"name": "external", "enabled": true, "require_client_auth": true},
+    {"name": "internal", "enabled": false}
+  ]
+}`,
+			expected: []ListenerInfo{
+				{Name: "internal", Address: "0.0.0.0:9092", AdvertisedAddress: "redpanda-1:9092"},
+				{Name: "external", Address: "0.0.0.0:19092", TLS: true, RequireClientAuth: true},
+			},
+		},
+		{
+			name: "it should advertise the bound addresses if none are advertised",
+			nodeConfig: `{
+  "node_id": 1,
+  "kafka_api": [{"address": "10.0.0.1", "port": 9092}]
+}`,
+			expected: []ListenerInfo{
+				{Address: "10.0.0.1:9092", AdvertisedAddress: "10.0.0.1:9092"},
+			},
+		},
+		{
+			name:       "it should return no listeners if the node has none",
+			nodeConfig: `{"node_id": 1}`,
+			expected:   []ListenerInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, nodeConfigEndpoint, r.URL.Path)
+					w.Write([]byte(tt.nodeConfig))
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+
+			listeners, err := adminClient.KafkaListeners(context.Background(), 1)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, listeners)
+
+			_, err = adminClient.KafkaListeners(context.Background(), 2)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const logLevelEndpoint = "/v1/config/log_level"
+
+// LogLevels are the valid levels for SetLogLevel, from least to most
+// verbose.
+var LogLevels = []string{"error", "warn", "info", "debug", "trace"}
+
+// CheckLogLevel returns an error if level isn't one of LogLevels.
+func CheckLogLevel(level string) error {
+	for _, l := range LogLevels {
+		if l == level {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid log level '%s', it must be one of %s", level, strings.Join(LogLevels, ", "))
+}
+
+// LogLevel returns the current level of the given logger on the given node.
+// Reading a level needs a Redpanda version that serves GET on the log level
+// endpoint; older versions only allow setting it and respond with an error.
+func (a *AdminAPI) LogLevel(
+	ctx context.Context, node int, logger string,
+) (string, error) {
+	if logger == "" {
+		return "", errors.New("invalid empty logger name")
+	}
+	var resp struct {
+		Level string `json:"level"`
+	}
+	path := fmt.Sprintf("%s/%s", logLevelEndpoint, url.PathEscape(logger))
+	err := a.sendToNode(ctx, node, http.MethodGet, path, nil, &resp)
+	return resp.Level, err
+}
+
+// SetLogLevel sets the level of the given logger on the given node. The
+// level reverts to the node's configured level after expirySeconds; zero
+// uses the server's default expiry.
+func (a *AdminAPI) SetLogLevel(
+	ctx context.Context, node int, logger, level string, expirySeconds int,
+) error {
+	if logger == "" {
+		return errors.New("invalid empty logger name")
+	}
+	if err := CheckLogLevel(level); err != nil {
+		return err
+	}
+	if expirySeconds < 0 {
+		return fmt.Errorf("invalid negative log level expiry %d", expirySeconds)
+	}
+
+	q := url.Values{"level": {level}}
+	if expirySeconds > 0 {
+		q.Set("expires", fmt.Sprint(expirySeconds))
+	}
+	path := fmt.Sprintf("%s/%s?%s", logLevelEndpoint, url.PathEscape(logger), q.Encode())
+	return a.sendToNode(ctx, node, http.MethodPut, path, nil, nil)
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLogLevel(t *testing.T) {
+	var nodeConfigHits, setHits int32
+	newNode := func(id int) *httptest.Server {
+		return httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case nodeConfigEndpoint:
+					atomic.AddInt32(&nodeConfigHits, 1)
+					fmt.Fprintf(w, `{"node_id": %d}`, id)
+				case logLevelEndpoint + "/raft":
+					require.Equal(t, 1, id, "request sent to the wrong node")
+					if r.Method == http.MethodGet {
+						fmt.Fprint(w, `{"name": "raft", "level": "info"}`)
+						return
+					}
+					require.Equal(t, http.MethodPut, r.Method)
+					require.Equal(t, "trace", r.URL.Query().Get("level"))
+					require.Equal(t, "60", r.URL.Query().Get("expires"))
+					atomic.AddInt32(&setHits, 1)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}),
+		)
+	}
+	n0, n1 := newNode(0), newNode(1)
+	defer n0.Close()
+	defer n1.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{n0.URL, n1.URL}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, adminClient.SetLogLevel(ctx, 1, "raft", "trace", 60))
+	require.NoError(t, adminClient.SetLogLevel(ctx, 1, "raft", "trace", 60))
+	require.EqualValues(t, 2, atomic.LoadInt32(&setHits))
+	require.EqualValues(t, 2, atomic.LoadInt32(&nodeConfigHits), "node hosts should be cached")
+
+	err = adminClient.SetLogLevel(ctx, 2, "raft", "trace", 60)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node 2")
+
+	level, err := adminClient.LogLevel(ctx, 1, "raft")
+	require.NoError(t, err)
+	require.Equal(t, "info", level)
+	_, err = adminClient.LogLevel(ctx, 1, "")
+	require.Error(t, err)
+
+	for _, test := range []struct {
+		logger, level string
+		expiry        int
+	}{
+		{"", "info", 0},
+		{"raft", "verbose", 0},
+		{"raft", "", 0},
+		{"raft", "info", -1},
+	} {
+		err := adminClient.SetLogLevel(ctx, 1, test.logger, test.level, test.expiry)
+		require.Error(t, err, "%+v", test)
+	}
+	require.EqualValues(t, 2, atomic.LoadInt32(&setHits))
+
+	err = adminClient.SetLogLevel(ctx, 1, "raft", "verbose", 0)
+	require.EqualError(t, err, "invalid log level 'verbose', it must be one of error, warn, info, debug, trace")
+}
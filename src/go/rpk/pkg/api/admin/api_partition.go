@@ -0,0 +1,240 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	partitionsEndpoint             = "/v1/partitions"
+	clusterPartitionsEndpoint      = "/v1/cluster/partitions"
+	cancelReconfigurationsEndpoint = "/v1/cluster/cancel_reconfigurations"
+	reconfigurationsEndpoint       = "/v1/partitions/reconfigurations"
+)
+
+// Partition is the information returned from the Redpanda admin partition
+// endpoint.
+type Partition struct {
+	Namespace   string    `json:"ns"`
+	Topic       string    `json:"topic"`
+	PartitionID int       `json:"partition_id"`
+	Status      string    `json:"status"`
+	LeaderID    int       `json:"leader_id"`
+	RaftGroupID int       `json:"raft_group_id"`
+	Replicas    []Replica `json:"replicas"`
+}
+
+// Replica is a replica of a partition.
+type Replica struct {
+	NodeID int `json:"node_id"`
+	Core   int `json:"core"`
+}
+
+// HasReplica returns whether the given node hosts a replica of the partition.
+func (p Partition) HasReplica(node int) bool {
+	for _, r := range p.Replicas {
+		if r.NodeID == node {
+			return true
+		}
+	}
+	return false
+}
+
+// Reconfiguration is an in-progress reconfiguration, i.e. replica movement,
+// of a partition, as returned from the Redpanda admin reconfigurations
+// endpoint.
+type Reconfiguration struct {
+	Namespace        string            `json:"ns"`
+	Topic            string            `json:"topic"`
+	PartitionID      int               `json:"partition"`
+	PreviousReplicas []Replica         `json:"previous_replicas"`
+	TargetReplicas   []Replica         `json:"target_replicas"`
+	ReplicaProgress  []ReplicaProgress `json:"replica_progress"`
+}
+
+// ReplicaProgress is how much of a partition's data has been moved to a
+// replica that the partition is being reconfigured to.
+type ReplicaProgress struct {
+	NodeID     int   `json:"node_id"`
+	BytesMoved int64 `json:"bytes_moved"`
+	BytesTotal int64 `json:"bytes_total"`
+}
+
+// Progress returns the bytes moved and the total bytes to move across every
+// replica of the reconfiguration.
+func (r Reconfiguration) Progress() (moved, total int64) {
+	for _, p := range r.ReplicaProgress {
+		moved += p.BytesMoved
+		total += p.BytesTotal
+	}
+	return moved, total
+}
+
+func partitionPath(namespace, topic string, partition int) string {
+	return fmt.Sprintf(
+		"%s/%s/%s/%d",
+		partitionsEndpoint, url.PathEscape(namespace), url.PathEscape(topic), partition,
+	)
+}
+
+// Partition returns the information of a partition of a Kafka topic.
+func (a *AdminAPI) Partition(
+	ctx context.Context, topic string, partition int,
+) (Partition, error) {
+	return a.partition(ctx, "kafka", topic, partition)
+}
+
+func (a *AdminAPI) partition(
+	ctx context.Context, namespace, topic string, partition int,
+) (Partition, error) {
+	var p Partition
+	return p, a.sendAny(ctx, http.MethodGet, partitionPath(namespace, topic, partition), nil, &p)
+}
+
+// Partitions returns the information of every partition of a Kafka topic,
+// sorted by partition.
+func (a *AdminAPI) Partitions(ctx context.Context, topic string) ([]Partition, error) {
+	return a.NamespacePartitions(ctx, "kafka", topic)
+}
+
+// NamespacePartitions returns the information of every partition of a topic
+// in the given namespace, sorted by partition. The namespace is "kafka" for
+// Kafka topics, and "redpanda" for internal topics such as the controller.
+func (a *AdminAPI) NamespacePartitions(
+	ctx context.Context, namespace, topic string,
+) ([]Partition, error) {
+	path := fmt.Sprintf(
+		"%s/%s/%s", partitionsEndpoint, url.PathEscape(namespace), url.PathEscape(topic),
+	)
+	var ps []Partition
+	err := a.sendAny(ctx, http.MethodGet, path, nil, &ps)
+	sortPartitions(ps)
+	return ps, err
+}
+
+// ClusterPartitions returns the information of every partition in the
+// cluster, sorted by namespace, topic, and partition. This pairs with Brokers
+// to find how partition replicas and leaders are placed across brokers.
+func (a *AdminAPI) ClusterPartitions(ctx context.Context) ([]Partition, error) {
+	var ps []Partition
+	err := a.sendAny(ctx, http.MethodGet, clusterPartitionsEndpoint, nil, &ps)
+	sortPartitions(ps)
+	return ps, err
+}
+
+// Reconfigurations returns every in-progress partition reconfiguration in
+// the cluster, sorted by namespace, topic, and partition.
+func (a *AdminAPI) Reconfigurations(ctx context.Context) ([]Reconfiguration, error) {
+	var rs []Reconfiguration
+	err := a.sendAny(ctx, http.MethodGet, reconfigurationsEndpoint, nil, &rs)
+	sort.Slice(rs, func(i, j int) bool {
+		l, r := rs[i], rs[j]
+		if l.Namespace != r.Namespace {
+			return l.Namespace < r.Namespace
+		}
+		if l.Topic != r.Topic {
+			return l.Topic < r.Topic
+		}
+		return l.PartitionID < r.PartitionID
+	})
+	for i := range rs {
+		sort.Slice(rs[i].ReplicaProgress, func(l, r int) bool {
+			return rs[i].ReplicaProgress[l].NodeID < rs[i].ReplicaProgress[r].NodeID
+		})
+	}
+	return rs, err
+}
+
+func sortPartitions(ps []Partition) {
+	sort.Slice(ps, func(i, j int) bool {
+		l, r := ps[i], ps[j]
+		if l.Namespace != r.Namespace {
+			return l.Namespace < r.Namespace
+		}
+		if l.Topic != r.Topic {
+			return l.Topic < r.Topic
+		}
+		return l.PartitionID < r.PartitionID
+	})
+}
+
+// TransferLeadership transfers the leadership of a partition of a Kafka topic
+// to the given node. If the node does not host a replica of the partition,
+// this returns a *NotReplicaError without issuing the transfer.
+func (a *AdminAPI) TransferLeadership(
+	ctx context.Context, topic string, partition, targetNode int,
+) error {
+	p, err := a.Partition(ctx, topic, partition)
+	if err != nil {
+		return err
+	}
+	if !p.HasReplica(targetNode) {
+		return &NotReplicaError{Topic: topic, Partition: partition, NodeID: targetNode}
+	}
+	if p.LeaderID == targetNode {
+		return nil
+	}
+
+	path := fmt.Sprintf(
+		"%s/transfer_leadership?target=%d",
+		partitionPath("kafka", topic, partition), targetNode,
+	)
+	// The transfer is handled by the current leader, if there is one.
+	if p.LeaderID >= 0 {
+		return a.sendToNode(ctx, p.LeaderID, http.MethodPost, path, nil, nil)
+	}
+	return a.sendAny(ctx, http.MethodPost, path, nil, nil)
+}
+
+// CancelReconfiguration cancels the in-progress reconfiguration, i.e. the
+// replica movement, of a partition of a Kafka topic. If the partition is not
+// being reconfigured, this returns a *NoReconfigurationError.
+func (a *AdminAPI) CancelReconfiguration(
+	ctx context.Context, topic string, partition int,
+) error {
+	path := partitionPath("kafka", topic, partition) + "/cancel_reconfiguration"
+	err := a.sendAny(ctx, http.MethodPost, path, nil, nil)
+	if IsNotFound(err) {
+		return &NoReconfigurationError{Topic: topic, Partition: partition, err: err}
+	}
+	return err
+}
+
+// CancelAllReconfigurations cancels every in-progress partition
+// reconfiguration in the cluster. If any of them could not be canceled, the
+// returned error reports each such partition.
+func (a *AdminAPI) CancelAllReconfigurations(ctx context.Context) error {
+	var results []struct {
+		Namespace string `json:"ns"`
+		Topic     string `json:"topic"`
+		Partition int    `json:"partition"`
+		Result    string `json:"result"`
+	}
+	if err := a.sendAny(ctx, http.MethodPost, cancelReconfigurationsEndpoint, nil, &results); err != nil {
+		return err
+	}
+	var errs *multierror.Error
+	for _, r := range results {
+		if r.Result != "" && r.Result != "success" {
+			errs = multierror.Append(errs, fmt.Errorf(
+				"unable to cancel the reconfiguration of %s/%s/%d: %s",
+				r.Namespace, r.Topic, r.Partition, r.Result,
+			))
+		}
+	}
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,218 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferLeadership(t *testing.T) {
+	var transfers int32
+	newNode := func(id int) *httptest.Server {
+		return httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case nodeConfigEndpoint:
+					fmt.Fprintf(w, `{"node_id": %d}`, id)
+				case "/v1/partitions/kafka/foo/3":
+					w.Write([]byte(`{
+  "ns": "kafka", "topic": "foo", "partition_id": 3, "status": "done",
+  "leader_id": 0, "raft_group_id": 7,
+  "replicas": [{"node_id": 0, "core": 1}, {"node_id": 1, "core": 0}]
+}`))
+				case "/v1/partitions/kafka/foo/3/transfer_leadership":
+					require.Equal(t, 0, id, "transfer should be sent to the leader")
+					require.Equal(t, http.MethodPost, r.Method)
+					require.Equal(t, "1", r.URL.Query().Get("target"))
+					atomic.AddInt32(&transfers, 1)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}),
+		)
+	}
+	n0, n1 := newNode(0), newNode(1)
+	defer n0.Close()
+	defer n1.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{n1.URL, n0.URL}, nil)
+	require.NoError(t, err)
+
+	p, err := adminClient.Partition(ctx, "foo", 3)
+	require.NoError(t, err)
+	require.Equal(t, Partition{
+		Namespace:   "kafka",
+		Topic:       "foo",
+		PartitionID: 3,
+		Status:      "done",
+		LeaderID:    0,
+		RaftGroupID: 7,
+		Replicas:    []Replica{{NodeID: 0, Core: 1}, {NodeID: 1, Core: 0}},
+	}, p)
+
+	require.NoError(t, adminClient.TransferLeadership(ctx, "foo", 3, 1))
+	require.EqualValues(t, 1, atomic.LoadInt32(&transfers))
+
+	// Transferring to the current leader is a no-op.
+	require.NoError(t, adminClient.TransferLeadership(ctx, "foo", 3, 0))
+	require.EqualValues(t, 1, atomic.LoadInt32(&transfers))
+
+	err = adminClient.TransferLeadership(ctx, "foo", 3, 2)
+	var nre *NotReplicaError
+	require.True(t, errors.As(err, &nre), "got %v", err)
+	require.Equal(t, NotReplicaError{Topic: "foo", Partition: 3, NodeID: 2}, *nre)
+	require.EqualValues(t, 1, atomic.LoadInt32(&transfers))
+
+	err = adminClient.TransferLeadership(ctx, "bar", 0, 1)
+	require.True(t, IsNotFound(err))
+}
+
+func TestCancelReconfiguration(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			switch r.URL.Path {
+			case "/v1/partitions/kafka/foo/3/cancel_reconfiguration":
+			case cancelReconfigurationsEndpoint:
+				w.Write([]byte(`[
+  {"ns": "kafka", "topic": "foo", "partition": 3, "result": "success"},
+  {"ns": "kafka", "topic": "bar", "partition": 0, "result": "partition_not_exists"}
+]`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, adminClient.CancelReconfiguration(ctx, "foo", 3))
+
+	err = adminClient.CancelReconfiguration(ctx, "foo", 4)
+	var nre *NoReconfigurationError
+	require.True(t, errors.As(err, &nre), "got %v", err)
+	require.Equal(t, "foo", nre.Topic)
+	require.Equal(t, 4, nre.Partition)
+	require.True(t, IsNotFound(err))
+
+	err = adminClient.CancelAllReconfigurations(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kafka/bar/0: partition_not_exists")
+	require.NotContains(t, err.Error(), "kafka/foo/3")
+}
+
+func TestReconfigurations(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Equal(t, reconfigurationsEndpoint, r.URL.Path)
+			w.Write([]byte(`[
+  {
+    "ns": "kafka", "topic": "foo", "partition": 1,
+    "previous_replicas": [{"node_id": 0, "core": 0}],
+    "target_replicas": [{"node_id": 2, "core": 1}, {"node_id": 1, "core": 0}],
+    "replica_progress": [
+      {"node_id": 2, "bytes_moved": 100, "bytes_total": 400},
+      {"node_id": 1, "bytes_moved": 400, "bytes_total": 400}
+    ]
+  },
+  {"ns": "kafka", "topic": "bar", "partition": 0}
+]`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	rs, err := adminClient.Reconfigurations(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []Reconfiguration{
+		{Namespace: "kafka", Topic: "bar", PartitionID: 0},
+		{
+			Namespace:        "kafka",
+			Topic:            "foo",
+			PartitionID:      1,
+			PreviousReplicas: []Replica{{NodeID: 0, Core: 0}},
+			TargetReplicas:   []Replica{{NodeID: 2, Core: 1}, {NodeID: 1, Core: 0}},
+			ReplicaProgress: []ReplicaProgress{
+				{NodeID: 1, BytesMoved: 400, BytesTotal: 400},
+				{NodeID: 2, BytesMoved: 100, BytesTotal: 400},
+			},
+		},
+	}, rs)
+
+	moved, total := rs[1].Progress()
+	require.EqualValues(t, 500, moved)
+	require.EqualValues(t, 800, total)
+}
+
+func TestPartitions(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.EscapedPath() {
+			case "/v1/partitions/kafka/foo":
+				w.Write([]byte(`[
+  {"ns": "kafka", "topic": "foo", "partition_id": 1, "leader_id": 2, "replicas": [{"node_id": 2, "core": 0}]},
+  {"ns": "kafka", "topic": "foo", "partition_id": 0, "leader_id": 1, "replicas": [{"node_id": 1, "core": 3}]}
+]`))
+			case "/v1/partitions/redpanda/controller":
+				w.Write([]byte(`[{"ns": "redpanda", "topic": "controller", "partition_id": 0, "leader_id": 1}]`))
+			case clusterPartitionsEndpoint:
+				w.Write([]byte(`[
+  {"ns": "kafka", "topic": "foo", "partition_id": 0, "leader_id": 1},
+  {"ns": "kafka", "topic": "bar", "partition_id": 0, "leader_id": 2}
+]`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	ps, err := adminClient.Partitions(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []Partition{
+		{Namespace: "kafka", Topic: "foo", PartitionID: 0, LeaderID: 1, Replicas: []Replica{{NodeID: 1, Core: 3}}},
+		{Namespace: "kafka", Topic: "foo", PartitionID: 1, LeaderID: 2, Replicas: []Replica{{NodeID: 2, Core: 0}}},
+	}, ps)
+
+	ps, err = adminClient.NamespacePartitions(ctx, "redpanda", "controller")
+	require.NoError(t, err)
+	require.Equal(t, []Partition{
+		{Namespace: "redpanda", Topic: "controller", PartitionID: 0, LeaderID: 1},
+	}, ps)
+
+	ps, err = adminClient.ClusterPartitions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Partition{
+		{Namespace: "kafka", Topic: "bar", PartitionID: 0, LeaderID: 2},
+		{Namespace: "kafka", Topic: "foo", PartitionID: 0, LeaderID: 1},
+	}, ps)
+
+	_, err = adminClient.Partitions(ctx, "baz")
+	require.True(t, IsNotFound(err))
+}
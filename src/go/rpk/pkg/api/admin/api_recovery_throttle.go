@@ -0,0 +1,62 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// recoveryRateProperty is the cluster configuration property that limits the
+// bandwidth each node uses to recover partition replicas, such as while
+// partitions are moved between nodes.
+const recoveryRateProperty = "raft_learner_recovery_rate"
+
+// unlimitedRecoveryRate is the recovery rate that SetRecoveryThrottle writes
+// for an unlimited throttle.
+const unlimitedRecoveryRate = math.MaxInt64
+
+// SetRecoveryThrottle limits the bandwidth, in bytes per second, that each
+// node uses to recover partition replicas, which is what moving a partition
+// to another node does. Zero removes the limit.
+func (a *AdminAPI) SetRecoveryThrottle(ctx context.Context, bytesPerSec int) error {
+	if bytesPerSec < 0 {
+		return fmt.Errorf("invalid negative recovery throttle %d", bytesPerSec)
+	}
+	rate := int64(bytesPerSec)
+	if rate == 0 {
+		rate = unlimitedRecoveryRate
+	}
+	_, err := a.SetClusterConfig(ctx, map[string]interface{}{recoveryRateProperty: rate}, nil)
+	return err
+}
+
+// GetRecoveryThrottle returns the bandwidth, in bytes per second, that each
+// node can use to recover partition replicas, or zero if it's unlimited.
+func (a *AdminAPI) GetRecoveryThrottle(ctx context.Context) (int, error) {
+	cfg, err := a.ClusterConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+	v, ok := cfg[recoveryRateProperty]
+	if !ok {
+		return 0, errors.New("the cluster doesn't support a recovery throttle")
+	}
+	rate, ok := v.(float64)
+	if !ok || rate < 0 {
+		return 0, fmt.Errorf("invalid %s %v", recoveryRateProperty, v)
+	}
+	if rate >= unlimitedRecoveryRate {
+		return 0, nil
+	}
+	return int(rate), nil
+}
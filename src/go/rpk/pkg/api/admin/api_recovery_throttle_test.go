@@ -0,0 +1,68 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryThrottle(t *testing.T) {
+	var rate interface{} = 104857600
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				json.NewEncoder(w).Encode(map[string]interface{}{recoveryRateProperty: rate})
+			case http.MethodPut:
+				var body struct {
+					Upsert map[string]json.Number `json:"upsert"`
+				}
+				d := json.NewDecoder(r.Body)
+				d.UseNumber()
+				require.NoError(t, d.Decode(&body))
+				rate = body.Upsert[recoveryRateProperty]
+				w.Write([]byte(`{"config_version": 2}`))
+			}
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	throttle, err := adminClient.GetRecoveryThrottle(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 104857600, throttle)
+
+	require.NoError(t, adminClient.SetRecoveryThrottle(ctx, 52428800))
+	require.Equal(t, json.Number("52428800"), rate)
+	throttle, err = adminClient.GetRecoveryThrottle(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 52428800, throttle)
+
+	require.NoError(t, adminClient.SetRecoveryThrottle(ctx, 0))
+	require.Equal(t, json.Number("9223372036854775807"), rate)
+	throttle, err = adminClient.GetRecoveryThrottle(ctx)
+	require.NoError(t, err)
+	require.Zero(t, throttle, "the maximum rate should be unlimited")
+
+	require.Error(t, adminClient.SetRecoveryThrottle(ctx, -1))
+
+	rate = nil
+	_, err = adminClient.GetRecoveryThrottle(ctx)
+	require.Error(t, err)
+}
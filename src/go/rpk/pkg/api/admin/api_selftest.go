@@ -0,0 +1,130 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const selfTestEndpoint = "/v1/debug/self_test"
+
+// SelfTestRequest describes which self tests to run, and where.
+type SelfTestRequest struct {
+	// Disk runs the disk throughput and latency test.
+	Disk bool
+	// Network runs the network throughput and latency test between the
+	// tested nodes.
+	Network bool
+	// Duration is how long each test runs for. If zero, the server's
+	// default is used.
+	Duration time.Duration
+	// Nodes are the IDs of the nodes to test. If empty, every node is
+	// tested.
+	Nodes []int
+}
+
+type selfTestStart struct {
+	Nodes []int           `json:"nodes,omitempty"`
+	Tests []selfTestParam `json:"tests"`
+}
+
+type selfTestParam struct {
+	Type       string `json:"type"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// SelfTestResults is the self test status of each node, sorted by node ID.
+type SelfTestResults []SelfTestNodeReport
+
+// SelfTestNodeReport is the self test status of a node, and the results of
+// the tests it last ran.
+type SelfTestNodeReport struct {
+	NodeID int `json:"node_id"`
+	// Status is "running" while the node runs tests, and "idle"
+	// otherwise.
+	Status  string           `json:"status"`
+	Results []SelfTestResult `json:"results"`
+}
+
+// SelfTestResult is the result of a single test. Latencies are in
+// microseconds.
+type SelfTestResult struct {
+	TestID     string `json:"test_id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Info       string `json:"info,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Warning    string `json:"warning,omitempty"`
+	DurationMs int64  `json:"duration"`
+	P50        int64  `json:"p50"`
+	P90        int64  `json:"p90"`
+	P99        int64  `json:"p99"`
+	P999       int64  `json:"p999"`
+	MaxLatency int64  `json:"max_latency"`
+	// RPS is the number of requests per second, and BPS the throughput in
+	// bytes per second.
+	RPS      int64 `json:"rps"`
+	BPS      int64 `json:"bps"`
+	Timeouts int   `json:"timeouts"`
+}
+
+// StartSelfTest starts the requested self tests and returns the ID of the
+// test run. Tests run in the background; use SelfTestStatus to wait for
+// their results.
+func (a *AdminAPI) StartSelfTest(
+	ctx context.Context, req SelfTestRequest,
+) (string, error) {
+	if !req.Disk && !req.Network {
+		return "", errors.New("invalid self test request: at least one of the disk or network tests is required")
+	}
+	if req.Duration < 0 {
+		return "", errors.New("invalid self test request: negative duration")
+	}
+	start := selfTestStart{Nodes: req.Nodes}
+	for _, test := range []struct {
+		enabled bool
+		typ     string
+	}{
+		{req.Disk, "disk"},
+		{req.Network, "network"},
+	} {
+		if test.enabled {
+			start.Tests = append(start.Tests, selfTestParam{
+				Type:       test.typ,
+				DurationMs: req.Duration.Milliseconds(),
+			})
+		}
+	}
+	// The ID is a JSON string: unlike a *string, which receives the raw
+	// body, a *jsonString is json decoded.
+	var testID jsonString
+	err := a.sendAny(ctx, http.MethodPost, selfTestEndpoint+"/start", start, &testID)
+	return string(testID), err
+}
+
+// StopSelfTest stops any running self tests on every node.
+func (a *AdminAPI) StopSelfTest(ctx context.Context) error {
+	return a.sendAny(ctx, http.MethodPost, selfTestEndpoint+"/stop", nil, nil)
+}
+
+// SelfTestStatus returns the self test status and latest results of each
+// node.
+func (a *AdminAPI) SelfTestStatus(ctx context.Context) (SelfTestResults, error) {
+	var results SelfTestResults
+	err := a.sendAny(ctx, http.MethodGet, selfTestEndpoint+"/status", nil, &results)
+	sort.Slice(results, func(i, j int) bool { return results[i].NodeID < results[j].NodeID })
+	return results, err
+}
+
+type jsonString string
@@ -0,0 +1,110 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	var (
+		started []byte
+		stopped bool
+	)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case selfTestEndpoint + "/start":
+				require.Equal(t, http.MethodPost, r.Method)
+				var err error
+				started, err = ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				w.Write([]byte(`"b4292c8e-5de4-4c8e-8bc4-9e4b5d3f1a2c"`))
+			case selfTestEndpoint + "/stop":
+				require.Equal(t, http.MethodPost, r.Method)
+				stopped = true
+			case selfTestEndpoint + "/status":
+				w.Write([]byte(`[
+  {"node_id": 2, "status": "running", "results": []},
+  {"node_id": 1, "status": "idle", "results": [
+    {"test_id": "b4292c8e", "name": "disk", "type": "disk", "duration": 5000,
+     "p50": 120, "p90": 300, "p99": 900, "p999": 1500, "max_latency": 2100,
+     "rps": 4000, "bps": 524288000, "timeouts": 0}
+  ]}
+]`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer ts.Close()
+
+	ctx := context.Background()
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	id, err := adminClient.StartSelfTest(ctx, SelfTestRequest{
+		Disk:     true,
+		Network:  true,
+		Duration: 5 * time.Second,
+		Nodes:    []int{1, 2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "b4292c8e-5de4-4c8e-8bc4-9e4b5d3f1a2c", id)
+	var start map[string]interface{}
+	require.NoError(t, json.Unmarshal(started, &start))
+	require.Equal(t, map[string]interface{}{
+		"nodes": []interface{}{1.0, 2.0},
+		"tests": []interface{}{
+			map[string]interface{}{"type": "disk", "duration_ms": 5000.0},
+			map[string]interface{}{"type": "network", "duration_ms": 5000.0},
+		},
+	}, start)
+
+	results, err := adminClient.SelfTestStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, SelfTestResults{
+		{NodeID: 1, Status: "idle", Results: []SelfTestResult{{
+			TestID:     "b4292c8e",
+			Name:       "disk",
+			Type:       "disk",
+			DurationMs: 5000,
+			P50:        120,
+			P90:        300,
+			P99:        900,
+			P999:       1500,
+			MaxLatency: 2100,
+			RPS:        4000,
+			BPS:        524288000,
+		}}},
+		{NodeID: 2, Status: "running", Results: []SelfTestResult{}},
+	}, results)
+
+	require.NoError(t, adminClient.StopSelfTest(ctx))
+	require.True(t, stopped)
+}
+
+func TestStartSelfTestInvalid(t *testing.T) {
+	adminClient, err := NewAdminAPI([]string{"localhost:9644"}, nil)
+	require.NoError(t, err)
+
+	_, err = adminClient.StartSelfTest(context.Background(), SelfTestRequest{})
+	require.Error(t, err)
+	_, err = adminClient.StartSelfTest(context.Background(), SelfTestRequest{Disk: true, Duration: -time.Second})
+	require.Error(t, err)
+}
@@ -10,9 +10,12 @@
 package admin
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 )
 
 const usersEndpoint = "/v1/security/users"
@@ -23,34 +26,64 @@ type newUser struct {
 	Algorithm string `json:"algorithm"`
 }
 
+// ScramMechanism is a SASL/SCRAM mechanism that a user's credentials are
+// created for.
+type ScramMechanism string
+
+const (
+	ScramSha256 ScramMechanism = "SCRAM-SHA-256"
+	ScramSha512 ScramMechanism = "SCRAM-SHA-512"
+)
+
 // CreateUser creates a user with the given username and password using the
-// SCRAM-SHA-256 algorithm.
-func (a *AdminAPI) CreateUser(username, password string) error {
+// given SCRAM mechanism. If the user already exists, this returns a
+// *UserExistsError.
+//
+// The password is only sent in the request body, which is never passed to
+// the request hook nor included in errors.
+func (a *AdminAPI) CreateUser(
+	ctx context.Context, username, password string, mechanism ScramMechanism,
+) error {
 	if username == "" {
 		return errors.New("invalid empty username")
 	}
 	if password == "" {
 		return errors.New("invalid empty password")
 	}
+	switch mechanism {
+	case ScramSha256, ScramSha512:
+	default:
+		return fmt.Errorf("unsupported scram mechanism %q", mechanism)
+	}
 	u := newUser{
 		User:      username,
 		Password:  password,
-		Algorithm: "SCRAM-SHA-256",
+		Algorithm: string(mechanism),
+	}
+	err := a.sendToLeader(ctx, http.MethodPost, usersEndpoint, u, nil)
+	if hasStatus(err, http.StatusConflict) {
+		return &UserExistsError{Username: username, err: err}
 	}
-	return a.sendAll(http.MethodPost, usersEndpoint, u, nil)
+	return err
 }
 
-// DeleteUser deletes the given username, if it exists.
-func (a *AdminAPI) DeleteUser(username string) error {
+// DeleteUser deletes the given username. If the user does not exist, this
+// returns a *UserNotFoundError, for which IsNotFound is true.
+func (a *AdminAPI) DeleteUser(ctx context.Context, username string) error {
 	if username == "" {
 		return errors.New("invalid empty username")
 	}
 	path := usersEndpoint + "/" + url.PathEscape(username)
-	return a.sendAll(http.MethodDelete, path, nil, nil)
+	err := a.sendToLeader(ctx, http.MethodDelete, path, nil, nil)
+	if IsNotFound(err) {
+		return &UserNotFoundError{Username: username, err: err}
+	}
+	return err
 }
 
-// ListUsers returns the current users.
-func (a *AdminAPI) ListUsers() ([]string, error) {
+// ListUsers returns the current users, sorted.
+func (a *AdminAPI) ListUsers(ctx context.Context) ([]string, error) {
 	var users []string
-	return users, a.sendAll(http.MethodGet, usersEndpoint, nil, &users)
+	defer func() { sort.Strings(users) }()
+	return users, a.sendToLeader(ctx, http.MethodGet, usersEndpoint, nil, &users)
 }
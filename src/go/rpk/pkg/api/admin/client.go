@@ -0,0 +1,91 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// AdminClient is the interface of the admin API client, which *AdminAPI
+// implements. Code that only issues requests can take an AdminClient so
+// that tests can use a fake, such as the one in the mocks package.
+type AdminClient interface {
+	// Brokers
+	Brokers(ctx context.Context) ([]Broker, error)
+	AliveBrokers(ctx context.Context) ([]Broker, error)
+	BrokersWithStatus(ctx context.Context, status MembershipStatus) ([]Broker, error)
+	Broker(ctx context.Context, node int) (Broker, error)
+	ClusterVersions(ctx context.Context) (map[int]string, error)
+	IsMixedVersion(ctx context.Context) (bool, error)
+	BrokersByRack(ctx context.Context) (map[string][]Broker, error)
+	DecommissionBroker(ctx context.Context, node int) error
+	DecommissionBrokers(ctx context.Context, nodes []int, dryRun bool) error
+	RecommissionBroker(ctx context.Context, node int) error
+	DecommissionStatus(ctx context.Context, node int) (DecommissionStatus, error)
+	WaitForBrokerRemoved(ctx context.Context, node int, poll time.Duration) error
+	WaitForBrokerActive(ctx context.Context, node int, poll time.Duration) error
+	EnableMaintenanceMode(ctx context.Context, node int) error
+	DisableMaintenanceMode(ctx context.Context, node int) error
+	MaintenanceStatus(ctx context.Context, node int) (MaintenanceStatus, error)
+	RestartBroker(ctx context.Context, node int) error
+	DrainAndRestartBroker(ctx context.Context, node int, poll time.Duration) error
+
+	// Cluster
+	ClusterHealth(ctx context.Context) (ClusterHealth, error)
+	WaitForHealthy(ctx context.Context, poll time.Duration) error
+	Ready(ctx context.Context) (bool, error)
+	WaitForReady(ctx context.Context, poll time.Duration) error
+	GetController(ctx context.Context) (Broker, error)
+	License(ctx context.Context) (License, error)
+	Features(ctx context.Context) ([]Feature, error)
+	IsFeatureActive(ctx context.Context, name string) (bool, error)
+
+	// Configuration
+	ClusterConfig(ctx context.Context) (map[string]interface{}, error)
+	SetClusterConfig(ctx context.Context, upsert map[string]interface{}, remove []string) (ClusterConfigWriteResult, error)
+	SetClusterConfigFromFile(ctx context.Context, fs afero.Fs, path string, dryRun bool) (ClusterConfigFileResult, error)
+	ClusterConfigStatus(ctx context.Context) ([]ClusterConfigNodeStatus, error)
+	NodeConfig(ctx context.Context, node int) (map[string]interface{}, error)
+	NodeID(ctx context.Context) (int, error)
+	KafkaListeners(ctx context.Context, node int) ([]ListenerInfo, error)
+	LogLevel(ctx context.Context, node int, logger string) (string, error)
+	SetLogLevel(ctx context.Context, node int, logger, level string, expirySeconds int) error
+
+	// Partitions
+	Partition(ctx context.Context, topic string, partition int) (Partition, error)
+	Partitions(ctx context.Context, topic string) ([]Partition, error)
+	NamespacePartitions(ctx context.Context, namespace, topic string) ([]Partition, error)
+	ClusterPartitions(ctx context.Context) ([]Partition, error)
+	TransferLeadership(ctx context.Context, topic string, partition, targetNode int) error
+	CancelReconfiguration(ctx context.Context, topic string, partition int) error
+	CancelAllReconfigurations(ctx context.Context) error
+	TriggerPartitionBalancer(ctx context.Context) error
+	PartitionBalancerStatus(ctx context.Context) (BalancerStatus, error)
+	Reconfigurations(ctx context.Context) ([]Reconfiguration, error)
+	SetRecoveryThrottle(ctx context.Context, bytesPerSec int) error
+	GetRecoveryThrottle(ctx context.Context) (int, error)
+	CloudStorageStatus(ctx context.Context) (CloudStorageStatus, error)
+	PartitionCloudStorageStatus(ctx context.Context, topic string, partition int) (PartitionCloudStorageStatus, error)
+
+	// Self tests
+	StartSelfTest(ctx context.Context, req SelfTestRequest) (string, error)
+	StopSelfTest(ctx context.Context) error
+	SelfTestStatus(ctx context.Context) (SelfTestResults, error)
+
+	// Users
+	CreateUser(ctx context.Context, username, password string, mechanism ScramMechanism) error
+	DeleteUser(ctx context.Context, username string) error
+	ListUsers(ctx context.Context) ([]string, error)
+}
+
+var _ AdminClient = (*AdminAPI)(nil)
@@ -0,0 +1,127 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	vtls "github.com/vectorizedio/redpanda/src/go/rpk/pkg/tls"
+)
+
+// NewAdminAPIFromConfig returns a client for the admin API described by the
+// rpk config, reading any TLS files from fs. The opts are applied after the
+// options derived from the config.
+//
+// The hosts are rpk.admin_api.addresses or, if empty, the node's own
+// redpanda.admin listeners. The client uses https if rpk.admin_api.tls is
+// set, or if a listener in use has TLS enabled in redpanda.admin_api_tls.
+// The rpk.kafka_api.sasl credentials, if any, are used for basic
+// authentication.
+func NewAdminAPIFromConfig(
+	fs afero.Fs, cfg *config.Config, opts ...Opt,
+) (*AdminAPI, error) {
+	urls, listeners := configAddrs(cfg)
+
+	tlsConfig, err := configTLS(fs, cfg, listeners)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfgOpts []Opt
+	sasl := cfg.Rpk.KafkaApi.SASL
+	if sasl == nil {
+		sasl = cfg.Rpk.SASL // deprecated
+	}
+	if sasl != nil {
+		switch {
+		case sasl.User != "" && sasl.Password == "":
+			return nil, fmt.Errorf("rpk.kafka_api.sasl.password is required when rpk.kafka_api.sasl.user is set")
+		case sasl.User == "" && sasl.Password != "":
+			return nil, fmt.Errorf("rpk.kafka_api.sasl.user is required when rpk.kafka_api.sasl.password is set")
+		case sasl.User != "":
+			cfgOpts = append(cfgOpts, WithBasicAuth(sasl.User, sasl.Password))
+		}
+	}
+	return NewAdminAPI(urls, tlsConfig, append(cfgOpts, opts...)...)
+}
+
+// configAddrs returns the admin hosts of the config and, if they come from
+// redpanda.admin, the names of the listeners they come from.
+func configAddrs(cfg *config.Config) ([]string, []string) {
+	if len(cfg.Rpk.AdminApi.Addresses) > 0 {
+		return cfg.Rpk.AdminApi.Addresses, nil
+	}
+	var urls, listeners []string
+	for _, a := range cfg.Redpanda.AdminApi {
+		urls = append(urls, a.LocalHostPort())
+		listeners = append(listeners, a.Name)
+	}
+	if len(urls) == 0 {
+		urls = []string{net.JoinHostPort("127.0.0.1", strconv.Itoa(config.DefaultAdminPort))}
+	}
+	return urls, listeners
+}
+
+// configTLS returns the tls configuration to talk to the admin hosts, or nil
+// if they do not use TLS.
+func configTLS(
+	fs afero.Fs, cfg *config.Config, listeners []string,
+) (*tls.Config, error) {
+	field := "rpk.admin_api.tls"
+	t := cfg.Rpk.AdminApi.TLS
+	if t == nil && cfg.Rpk.TLS != nil {
+		field, t = "rpk.tls", cfg.Rpk.TLS // deprecated
+	}
+
+	// Without rpk TLS settings, we use TLS if a listener we talk to has
+	// it enabled, trusting the listener's own truststore.
+	var server *config.ServerTLS
+	for _, name := range listeners {
+		for i := range cfg.Redpanda.AdminApiTLS {
+			if s := &cfg.Redpanda.AdminApiTLS[i]; s.Enabled && s.Name == name {
+				server = s
+			}
+		}
+	}
+	if t == nil {
+		if server == nil {
+			return nil, nil
+		}
+		if server.RequireClientAuth {
+			return nil, fmt.Errorf(
+				"redpanda.admin_api_tls requires client authentication for listener %q, but rpk.admin_api.tls.cert_file and rpk.admin_api.tls.key_file are not set",
+				server.Name,
+			)
+		}
+		t = &config.TLS{TruststoreFile: server.TruststoreFile}
+	}
+
+	switch {
+	case t.CertFile != "" && t.KeyFile == "":
+		return nil, fmt.Errorf("%s.key_file is required when %s.cert_file is set", field, field)
+	case t.KeyFile != "" && t.CertFile == "":
+		return nil, fmt.Errorf("%s.cert_file is required when %s.key_file is set", field, field)
+	case server != nil && server.RequireClientAuth && t.CertFile == "":
+		return nil, fmt.Errorf(
+			"redpanda.admin_api_tls requires client authentication for listener %q, but %s.cert_file is not set",
+			server.Name, field,
+		)
+	}
+	tlsConfig, err := vtls.LoadTLSConfig(fs, t.TruststoreFile, t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %s: %w", field, err)
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func TestNewAdminAPIFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     func(*config.Config)
+		expURLs []string
+		expErr  string
+		expAuth *basicAuth
+	}{
+		{
+			name:    "default listener",
+			expURLs: []string{"http://127.0.0.1:9644"},
+		},
+		{
+			name: "rpk addresses",
+			cfg: func(c *config.Config) {
+				c.Rpk.AdminApi.Addresses = []string{"10.0.0.1:9644", "10.0.0.2:9644"}
+			},
+			expURLs: []string{"http://10.0.0.1:9644", "http://10.0.0.2:9644"},
+		},
+		{
+			name: "listener with tls",
+			cfg: func(c *config.Config) {
+				c.Redpanda.AdminApi = []config.NamedSocketAddress{{
+					Name:          "internal",
+					SocketAddress: config.SocketAddress{Address: "10.0.0.1", Port: 9644},
+				}}
+				c.Redpanda.AdminApiTLS = []config.ServerTLS{{Name: "internal", Enabled: true}}
+			},
+			expURLs: []string{"https://10.0.0.1:9644"},
+		},
+		{
+			name: "listener requiring client auth",
+			cfg: func(c *config.Config) {
+				c.Redpanda.AdminApiTLS = []config.ServerTLS{{Enabled: true, RequireClientAuth: true}}
+			},
+			expErr: "rpk.admin_api.tls.cert_file",
+		},
+		{
+			name: "cert without key",
+			cfg: func(c *config.Config) {
+				c.Rpk.AdminApi.TLS = &config.TLS{CertFile: "/etc/cert.pem"}
+			},
+			expErr: "rpk.admin_api.tls.key_file is required",
+		},
+		{
+			name: "deprecated tls key without cert",
+			cfg: func(c *config.Config) {
+				c.Rpk.TLS = &config.TLS{KeyFile: "/etc/key.pem"}
+			},
+			expErr: "rpk.tls.cert_file is required",
+		},
+		{
+			name: "missing truststore",
+			cfg: func(c *config.Config) {
+				c.Rpk.AdminApi.TLS = &config.TLS{TruststoreFile: "/etc/ca.pem"}
+			},
+			expErr: "unable to load rpk.admin_api.tls",
+		},
+		{
+			name: "sasl",
+			cfg: func(c *config.Config) {
+				c.Rpk.KafkaApi.SASL = &config.SASL{User: "admin", Password: "secret"}
+			},
+			expURLs: []string{"http://127.0.0.1:9644"},
+			expAuth: &basicAuth{user: "admin", password: "secret"},
+		},
+		{
+			name: "sasl without password",
+			cfg: func(c *config.Config) {
+				c.Rpk.KafkaApi.SASL = &config.SASL{User: "admin"}
+			},
+			expErr: "rpk.kafka_api.sasl.password is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			if tt.cfg != nil {
+				tt.cfg(cfg)
+			}
+			a, err := NewAdminAPIFromConfig(afero.NewMemMapFs(), cfg)
+			if tt.expErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expURLs, a.urls)
+			require.Equal(t, tt.expAuth, a.basicAuth)
+		})
+	}
+}
+
+func TestNewAdminAPIFromConfigTruststore(t *testing.T) {
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"node_id": 1}`))
+		}),
+	)
+	defer ts.Close()
+
+	fs := afero.NewMemMapFs()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	require.NoError(t, afero.WriteFile(fs, "/etc/redpanda/ca.pem", ca, 0o644))
+
+	cfg := config.Default()
+	cfg.Rpk.AdminApi.Addresses = []string{ts.Listener.Addr().String()}
+	cfg.Rpk.AdminApi.TLS = &config.TLS{TruststoreFile: "/etc/redpanda/ca.pem"}
+	a, err := NewAdminAPIFromConfig(fs, cfg)
+	require.NoError(t, err)
+	b, err := a.Broker(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, b.NodeID)
+}
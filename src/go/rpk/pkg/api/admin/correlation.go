@@ -0,0 +1,46 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CorrelationIDHeader is the header that carries the correlation ID of a
+// request.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context that makes every admin request issued
+// with it carry the given correlation ID, which allows threading the ID of an
+// upstream request through to the admin API. Requests issued without one
+// get a new random ID for each client call.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID in the context, if any.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// ensureCorrelationID returns ctx with a new correlation ID if it does not
+// already have one. This is called once per logical call so that the ID is
+// shared by every attempt and host the call reaches.
+func ensureCorrelationID(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+	return WithCorrelationID(ctx, uuid.New().String())
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelationID(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		headers []string
+		infos   []RequestInfo
+		hits    int32
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Get(CorrelationIDHeader))
+		mu.Unlock()
+		// Fail the very first request to check that the retry
+		// keeps the ID.
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	ts1 := httptest.NewServer(handler)
+	defer ts1.Close()
+	ts2 := httptest.NewServer(handler)
+	defer ts2.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{ts1.URL, ts2.URL},
+		nil,
+		WithRetries(1, time.Millisecond, time.Millisecond),
+		WithRequestHook(func(info RequestInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			infos = append(infos, info)
+		}),
+	)
+	require.NoError(t, err)
+
+	reset := func() {
+		headers, infos = nil, nil
+	}
+	requireOneID := func() string {
+		require.Len(t, headers, 3)
+		require.Len(t, infos, 3)
+		id := headers[0]
+		require.NotEmpty(t, id)
+		for i := range headers {
+			require.Equal(t, id, headers[i])
+			require.Equal(t, id, infos[i].CorrelationID)
+		}
+		return id
+	}
+
+	err = adminClient.RecommissionBroker(context.Background(), 1)
+	require.NoError(t, err)
+	first := requireOneID()
+
+	reset()
+	atomic.StoreInt32(&hits, 0)
+	err = adminClient.RecommissionBroker(context.Background(), 1)
+	require.NoError(t, err)
+	second := requireOneID()
+	require.NotEqual(t, first, second)
+
+	reset()
+	atomic.StoreInt32(&hits, 0)
+	ctx := WithCorrelationID(context.Background(), "upstream-id")
+	err = adminClient.RecommissionBroker(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "upstream-id", requireOneID())
+}
+
+func TestCorrelationIDError(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+	require.NoError(t, err)
+
+	ctx := WithCorrelationID(context.Background(), "upstream-id")
+	_, err = adminClient.Broker(ctx, 1)
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he))
+	require.Equal(t, "upstream-id", he.CorrelationID)
+}
@@ -0,0 +1,351 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ErrResponseTooLarge is returned when reading a response body that is larger
+// than the limit set with WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ErrNoController is returned when the cluster has no controller leader,
+// which is usually transient while a new leader is elected.
+var ErrNoController = errors.New("no controller leader is currently elected")
+
+// HTTPResponseError is the error returned when the admin server responds to
+// a request with a non-2xx status code.
+type HTTPResponseError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// Body is the raw response body, which usually explains the failure.
+	Body []byte
+	// CorrelationID is the X-Correlation-ID the request was sent with.
+	CorrelationID string
+
+	readErr error
+}
+
+func (e *HTTPResponseError) Error() string {
+	status := http.StatusText(e.StatusCode)
+	if e.readErr != nil {
+		return fmt.Sprintf("request %s %s failed: %s, unable to read body: %v", e.Method, e.URL, status, e.readErr)
+	}
+	return fmt.Sprintf("request %s %s failed: %s, body: %q", e.Method, e.URL, status, e.Body)
+}
+
+// Unwrap returns the error encountered while reading the response body, if
+// any.
+func (e *HTTPResponseError) Unwrap() error {
+	return e.readErr
+}
+
+// SendAllError is the error returned when a request that is sent to every
+// admin host fails on all of them.
+type SendAllError struct {
+	Method string
+	Path   string
+
+	perHost map[string]error
+}
+
+// PerHost returns the error of the request on each host.
+func (e *SendAllError) PerHost() map[string]error {
+	m := make(map[string]error, len(e.perHost))
+	for host, err := range e.perHost {
+		m[host] = err
+	}
+	return m
+}
+
+func (e *SendAllError) Error() string {
+	failed := e.failed()
+	msgs := make([]string, 0, len(failed))
+	for _, host := range failed {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", host, e.perHost[host]))
+	}
+	return fmt.Sprintf(
+		"request %s %s failed on %d of %d hosts: %s",
+		e.Method, e.Path, len(failed), len(e.perHost), strings.Join(msgs, "; "),
+	)
+}
+
+// Is returns whether the error of any host is target.
+func (e *SendAllError) Is(target error) bool {
+	for _, host := range e.failed() {
+		if errors.Is(e.perHost[host], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error, in host order, that matches target.
+func (e *SendAllError) As(target interface{}) bool {
+	for _, host := range e.failed() {
+		if errors.As(e.perHost[host], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// failed returns the sorted hosts that failed.
+func (e *SendAllError) failed() []string {
+	var hosts []string
+	for host, err := range e.perHost {
+		if err != nil {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// BrokerRecommissionedError is returned from WaitForBrokerRemoved if the
+// broker became active again while waiting for it to be removed.
+type BrokerRecommissionedError struct {
+	NodeID int
+}
+
+func (e *BrokerRecommissionedError) Error() string {
+	return fmt.Sprintf("broker %d was recommissioned while waiting for it to be removed", e.NodeID)
+}
+
+// BrokerDecommissionedError is returned from WaitForBrokerActive if the broker
+// started draining again while waiting for it to be active.
+type BrokerDecommissionedError struct {
+	NodeID int
+}
+
+func (e *BrokerDecommissionedError) Error() string {
+	return fmt.Sprintf("broker %d was decommissioned while waiting for it to be active", e.NodeID)
+}
+
+// RestartUnsupportedError is returned from RestartBroker if the broker's
+// version has no restart endpoint.
+type RestartUnsupportedError struct {
+	NodeID int
+
+	err error
+}
+
+func (e *RestartUnsupportedError) Error() string {
+	return fmt.Sprintf("broker %d does not support being restarted through the admin API", e.NodeID)
+}
+
+// Unwrap returns the underlying *HTTPResponseError.
+func (e *RestartUnsupportedError) Unwrap() error {
+	return e.err
+}
+
+// ClusterUnhealthyError is returned from WaitForHealthy if the context is
+// done before the cluster is healthy. It unwraps to the context's error.
+type ClusterUnhealthyError struct {
+	// Health is the last health overview observed, or nil if the cluster
+	// health could never be queried.
+	Health *ClusterHealth
+	// Err is the error of the last attempt to query the cluster health, if
+	// that attempt failed.
+	Err error
+
+	ctxErr error
+}
+
+func (e *ClusterUnhealthyError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("cluster did not become healthy: %v, last error: %v", e.ctxErr, e.Err)
+	case e.Health != nil:
+		h := e.Health
+		return fmt.Sprintf(
+			"cluster did not become healthy: %v, last seen with %d nodes down, %d leaderless partitions, %d under-replicated partitions",
+			e.ctxErr, len(h.NodesDown), h.LeaderlessCount, h.UnderReplicatedCount,
+		)
+	default:
+		return fmt.Sprintf("cluster did not become healthy: %v", e.ctxErr)
+	}
+}
+
+func (e *ClusterUnhealthyError) Unwrap() error {
+	return e.ctxErr
+}
+
+// NotReplicaError is returned from TransferLeadership if the target node
+// does not host a replica of the partition.
+type NotReplicaError struct {
+	Topic     string
+	Partition int
+	NodeID    int
+}
+
+func (e *NotReplicaError) Error() string {
+	return fmt.Sprintf("node %d does not host a replica of %s/%d", e.NodeID, e.Topic, e.Partition)
+}
+
+// NoReconfigurationError is returned from CancelReconfiguration if the
+// partition is not being reconfigured. IsNotFound is true for it.
+type NoReconfigurationError struct {
+	Topic     string
+	Partition int
+
+	err error
+}
+
+func (e *NoReconfigurationError) Error() string {
+	return fmt.Sprintf("partition %s/%d has no reconfiguration in progress", e.Topic, e.Partition)
+}
+
+// Unwrap returns the underlying *HTTPResponseError.
+func (e *NoReconfigurationError) Unwrap() error {
+	return e.err
+}
+
+// NonUniformCoresError is returned from BrokerList.ValidateUniformCores if
+// some brokers have a different number of cores than most brokers.
+type NonUniformCoresError struct {
+	// Cores is the number of cores of most brokers. It's unset if Tie.
+	Cores int
+	// Tie is whether no number of cores is more common than the others.
+	Tie bool
+	// Nodes are the IDs of the brokers that don't have Cores cores,
+	// sorted. They're every broker if Tie.
+	Nodes []int
+	// NodeCores is the number of cores of each of the Nodes.
+	NodeCores map[int]int
+}
+
+func (e *NonUniformCoresError) Error() string {
+	nodes := make([]string, 0, len(e.Nodes))
+	for _, node := range e.Nodes {
+		nodes = append(nodes, fmt.Sprintf("%d (%d cores)", node, e.NodeCores[node]))
+	}
+	if e.Tie {
+		return "the brokers have different numbers of cores, with none more common than the others: " +
+			strings.Join(nodes, ", ")
+	}
+	if len(nodes) == 1 {
+		return fmt.Sprintf("broker %s doesn't have the %d cores of the other brokers", nodes[0], e.Cores)
+	}
+	return fmt.Sprintf(
+		"brokers %s don't have the %d cores of the other brokers",
+		strings.Join(nodes, ", "),
+		e.Cores,
+	)
+}
+
+// RackDistributionError is returned from BrokerList.ValidateRackDistribution
+// if the brokers' racks can't keep every replica of a partition on a
+// different rack.
+type RackDistributionError struct {
+	ReplicationFactor int
+	// Racks are the IDs of the brokers in each rack, sorted.
+	Racks map[string][]int
+	// NoRack are the IDs of the brokers without a rack, sorted.
+	NoRack []int
+	// Uneven is whether some racks have more than one broker more than
+	// others.
+	Uneven bool
+}
+
+func (e *RackDistributionError) Error() string {
+	var problems []string
+	if len(e.NoRack) > 0 {
+		problems = append(problems, fmt.Sprintf("brokers %v have no rack", e.NoRack))
+	}
+	if len(e.Racks) < e.ReplicationFactor {
+		problems = append(problems, fmt.Sprintf(
+			"there are %d racks, fewer than the replication factor of %d",
+			len(e.Racks),
+			e.ReplicationFactor,
+		))
+	}
+	if e.Uneven {
+		racks := make([]string, 0, len(e.Racks))
+		for rack, nodes := range e.Racks {
+			racks = append(racks, fmt.Sprintf("%s has %d", rack, len(nodes)))
+		}
+		sort.Strings(racks)
+		problems = append(problems, "the brokers are unevenly spread across racks: "+strings.Join(racks, ", "))
+	}
+	return "a rack failure may lose replicas: " + strings.Join(problems, "; ")
+}
+
+// UserExistsError is returned from CreateUser if the user already exists.
+type UserExistsError struct {
+	Username string
+
+	err error
+}
+
+func (e *UserExistsError) Error() string {
+	return fmt.Sprintf("user %q already exists", e.Username)
+}
+
+// Unwrap returns the underlying *HTTPResponseError.
+func (e *UserExistsError) Unwrap() error {
+	return e.err
+}
+
+// UserNotFoundError is returned from DeleteUser if the user does not exist.
+type UserNotFoundError struct {
+	Username string
+
+	err error
+}
+
+func (e *UserNotFoundError) Error() string {
+	return fmt.Sprintf("user %q does not exist", e.Username)
+}
+
+// Unwrap returns the underlying *HTTPResponseError.
+func (e *UserNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// IsNotFound returns whether err is or wraps an *HTTPResponseError with a 404
+// status code.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// hasStatus returns whether err is or wraps an *HTTPResponseError with the
+// given status code. If err combines the errors of many hosts, any of them
+// may have the status: errors.As alone would only check the first.
+func hasStatus(err error, code int) bool {
+	var me *multierror.Error
+	if errors.As(err, &me) {
+		for _, err := range me.Errors {
+			if hasStatus(err, code) {
+				return true
+			}
+		}
+		return false
+	}
+	var se *SendAllError
+	if errors.As(err, &se) {
+		for _, err := range se.perHost {
+			if hasStatus(err, code) {
+				return true
+			}
+		}
+		return false
+	}
+	var he *HTTPResponseError
+	return errors.As(err, &he) && he.StatusCode == code
+}
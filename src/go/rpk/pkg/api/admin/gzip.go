@@ -0,0 +1,68 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// We request gzip ourselves rather than relying on the transport's implicit
+// compression so that we control how decoding errors are reported: the
+// transport reports a truncated stream as a bare unexpected EOF, which
+// reads like a network problem.
+func maybeDecompress(res *http.Response) {
+	if res.Header.Get("Content-Encoding") != "gzip" {
+		return
+	}
+	res.Body = &gzipBody{body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+}
+
+// gzipBody lazily decompresses body on the first Read, so that an invalid
+// gzip header is reported when the body is read, like any other error.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+	if g.zr == nil {
+		zr, err := gzip.NewReader(g.body)
+		if err != nil {
+			g.err = fmt.Errorf("unable to decompress gzip response body: %w", err)
+			return 0, g.err
+		}
+		g.zr = zr
+	}
+	n, err := g.zr.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		g.err = fmt.Errorf("gzip response body is truncated: %w", err)
+		return n, g.err
+	}
+	return n, err
+}
+
+func (g *gzipBody) Close() error {
+	if g.zr != nil {
+		g.zr.Close()
+	}
+	return g.body.Close()
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzipResponse(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`{"node_id": 1, "num_cores": 2, "membership_status": "active"}`))
+	require.NoError(t, zw.Close())
+	compressed := buf.Bytes()
+
+	tests := []struct {
+		name   string
+		body   []byte
+		expErr string
+	}{
+		{name: "complete", body: compressed},
+		{name: "truncated", body: compressed[:len(compressed)/2], expErr: "truncated"},
+		{name: "not gzip", body: []byte(`{"node_id": 1}`), expErr: "decompress"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+					w.Header().Set("Content-Encoding", "gzip")
+					w.Write(tt.body)
+				}),
+			)
+			defer ts.Close()
+
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil)
+			require.NoError(t, err)
+			b, err := adminClient.Broker(context.Background(), 1)
+			if tt.expErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, Broker{NodeID: 1, NumCores: 2, MembershipStatus: "active"}, b)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import "time"
+
+// RequestInfo describes a single HTTP request that the client issued to a
+// single host.
+type RequestInfo struct {
+	// Host is the scheme and host the request was sent to, e.g.
+	// "http://127.0.0.1:9644".
+	Host string
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the path of the request, e.g. "/v1/brokers".
+	Path string
+	// StatusCode is the status code of the response, or 0 if no response
+	// was received.
+	StatusCode int
+	// Duration is how long the request took until the response headers
+	// were received or the request failed.
+	Duration time.Duration
+	// Attempt is the 1-based attempt number of this request against Host;
+	// it is greater than 1 for retries.
+	Attempt int
+	// CorrelationID is the X-Correlation-ID the request was sent with,
+	// which is shared by every attempt and host of a single client call.
+	CorrelationID string
+	// Err is the error the request failed with, if any. A non-2xx response
+	// is reported as an *HTTPResponseError.
+	Err error
+	// Warning, if non-empty, is a warning about how the client is
+	// configured. It is reported once, on the first request.
+	Warning string
+}
+
+const insecureWarning = "tls certificate verification is disabled for the admin api"
+
+func (a *AdminAPI) observe(info RequestInfo) {
+	if a.hook == nil {
+		return
+	}
+	if a.insecure {
+		a.warnOnce.Do(func() { info.Warning = insecureWarning })
+	}
+	a.hook(info)
+}
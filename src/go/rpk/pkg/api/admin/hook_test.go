@@ -0,0 +1,91 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHook(t *testing.T) {
+	var hits int32
+	flaky := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&hits, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer flaky.Close()
+	notLeader := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer notLeader.Close()
+
+	var (
+		mu    sync.Mutex
+		infos []RequestInfo
+	)
+	adminClient, err := NewAdminAPI(
+		[]string{flaky.URL, notLeader.URL},
+		nil,
+		// The retry backoff gives the other host time to respond
+		// before the retry succeeds and cancels it.
+		WithRetries(1, 50*time.Millisecond, 50*time.Millisecond),
+		WithRequestHook(func(info RequestInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			infos = append(infos, info)
+		}),
+	)
+	require.NoError(t, err)
+
+	err = adminClient.CreateUser(context.Background(), "Joss", "momorocks", ScramSha256)
+	require.NoError(t, err)
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Host != infos[j].Host {
+			return infos[i].Host == flaky.URL
+		}
+		return infos[i].Attempt < infos[j].Attempt
+	})
+	require.Len(t, infos, 3)
+	for i, exp := range []struct {
+		host    string
+		status  int
+		attempt int
+		err     bool
+	}{
+		{flaky.URL, http.StatusServiceUnavailable, 1, true},
+		{flaky.URL, http.StatusOK, 2, false},
+		{notLeader.URL, http.StatusBadRequest, 1, true},
+	} {
+		info := infos[i]
+		require.Equal(t, exp.host, info.Host)
+		require.Equal(t, http.MethodPost, info.Method)
+		require.Equal(t, usersEndpoint, info.Path)
+		require.Equal(t, exp.status, info.StatusCode)
+		require.Equal(t, exp.attempt, info.Attempt)
+		require.Equal(t, exp.err, info.Err != nil)
+		require.Greater(t, int64(info.Duration), int64(0))
+	}
+}
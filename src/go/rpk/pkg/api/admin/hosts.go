@@ -0,0 +1,96 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// DefaultHostCooldown is how long a host that failed a request is tried after
+// every other host.
+const DefaultHostCooldown = 30 * time.Second
+
+// HostStrategy is how the client picks the first host to try for a request
+// that only needs to reach one host.
+type HostStrategy int
+
+const (
+	// RandomHost starts each request at a random host. This is the
+	// default.
+	RandomHost HostStrategy = iota
+	// RoundRobin starts each request at the host following the one the
+	// previous request started at, spreading load across all hosts.
+	RoundRobin
+)
+
+// hostOrder returns the order in which the client's hosts should be tried for
+// a request that only needs to reach one host. The order starts at the host
+// chosen by the client's host strategy and wraps around, and hosts that are
+// cooling down after a failure are moved to the end. If every host is
+// cooling down, every host is still returned.
+func (a *AdminAPI) hostOrder() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var start int
+	switch a.strategy {
+	case RoundRobin:
+		start = a.next % len(a.urls)
+		a.next = start + 1
+	default:
+		start = rng(len(a.urls))
+	}
+	hosts := append(append([]string(nil), a.urls[start:]...), a.urls[:start]...)
+
+	now := time.Now()
+	cooling := func(host string) bool {
+		failedAt, ok := a.failedAt[host]
+		return ok && now.Sub(failedAt) < a.cooldown
+	}
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return !cooling(hosts[i]) && cooling(hosts[j])
+	})
+	return hosts
+}
+
+// setHosts replaces the client's hosts, and the host of each node. Replacing
+// rather than modifying the slice keeps it safe to use for any request that
+// already got the previous hosts. The caller must hold a.mu.
+func (a *AdminAPI) setHosts(hosts []string, nodeHosts map[int]string) {
+	a.urls = hosts
+	a.nodeHosts = nodeHosts
+}
+
+func (a *AdminAPI) markFailed(host string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failedAt[host] = time.Now()
+}
+
+func (a *AdminAPI) markHealthy(host string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.failedAt, host)
+}
+
+// isHostFailure returns whether err indicates that the host itself failed,
+// meaning the request may succeed against another host: the host could not
+// be reached, did not respond in time, or responded with a server error.
+func isHostFailure(err error) bool {
+	var he *HTTPResponseError
+	if errors.As(err, &he) {
+		return he.StatusCode/100 == 5
+	}
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendAnyFailover(t *testing.T) {
+	var downHits, upHits int32
+	down := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&downHits, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)
+	defer down.Close()
+	up := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&upHits, 1)
+			w.Write([]byte(`{"node_id": 1}`))
+		}),
+	)
+	defer up.Close()
+
+	adminClient, err := NewAdminAPI([]string{down.URL, up.URL}, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		b, err := adminClient.Broker(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, 1, b.NodeID)
+	}
+	require.EqualValues(t, 10, atomic.LoadInt32(&upHits))
+	// The down host is tried at most once: after failing, it is
+	// deprioritized for the cooldown.
+	require.LessOrEqual(t, atomic.LoadInt32(&downHits), int32(1))
+}
+
+func TestSendAnyNoFailoverOnClientError(t *testing.T) {
+	var hits int32
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}
+	ts1 := httptest.NewServer(http.HandlerFunc(notFound))
+	defer ts1.Close()
+	ts2 := httptest.NewServer(http.HandlerFunc(notFound))
+	defer ts2.Close()
+
+	adminClient, err := NewAdminAPI([]string{ts1.URL, ts2.URL}, nil)
+	require.NoError(t, err)
+	_, err = adminClient.Broker(context.Background(), 1)
+	require.True(t, IsNotFound(err))
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits))
+}
+
+func TestSendAnyAllHostsCooling(t *testing.T) {
+	var hits int32
+	down := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	ts1 := httptest.NewServer(http.HandlerFunc(down))
+	defer ts1.Close()
+	ts2 := httptest.NewServer(http.HandlerFunc(down))
+	defer ts2.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{ts1.URL, ts2.URL}, nil, WithHostCooldown(time.Hour),
+	)
+	require.NoError(t, err)
+	for i := 1; i <= 2; i++ {
+		_, err = adminClient.Broker(context.Background(), 1)
+		var merr *multierror.Error
+		require.True(t, errors.As(err, &merr))
+		require.Len(t, merr.Errors, 2)
+		require.EqualValues(t, 2*i, atomic.LoadInt32(&hits))
+	}
+}
+
+func TestHostOrder(t *testing.T) {
+	adminClient, err := NewAdminAPI(
+		[]string{"1.1.1.1", "2.2.2.2", "3.3.3.3"}, nil,
+	)
+	require.NoError(t, err)
+	adminClient.markFailed("http://2.2.2.2")
+	for i := 0; i < 20; i++ {
+		order := adminClient.hostOrder()
+		require.Len(t, order, 3)
+		require.Equal(t, "http://2.2.2.2", order[2])
+	}
+
+	adminClient.cooldown = 0
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		seen[adminClient.hostOrder()[0]] = true
+	}
+	require.Len(t, seen, 3)
+}
+
+func TestRoundRobin(t *testing.T) {
+	const nHosts = 3
+	var (
+		hits  [nHosts]int32
+		hosts []string
+	)
+	for i := 0; i < nHosts; i++ {
+		i := i
+		ts := httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits[i], 1)
+				if i == 2 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}),
+		)
+		defer ts.Close()
+		hosts = append(hosts, ts.URL)
+	}
+
+	adminClient, err := NewAdminAPI(
+		hosts, nil, WithHostStrategy(RoundRobin), WithHostCooldown(0),
+	)
+	require.NoError(t, err)
+
+	// Host 2 always fails, so its requests fail over to host 0.
+	for i := 0; i < 3*nHosts; i++ {
+		_, err := adminClient.Broker(context.Background(), 1)
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 6, atomic.LoadInt32(&hits[0]))
+	require.EqualValues(t, 3, atomic.LoadInt32(&hits[1]))
+	require.EqualValues(t, 3, atomic.LoadInt32(&hits[2]))
+}
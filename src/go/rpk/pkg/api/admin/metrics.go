@@ -0,0 +1,88 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"strings"
+	"time"
+)
+
+// Metricer observes the requests issued by the client, e.g. to export
+// them as Prometheus metrics.
+type Metricer interface {
+	// ObserveRequest is called once for every HTTP request the client
+	// issues, including every retry and every host that a request is sent
+	// to. The endpoint is the templated path of the request, e.g.
+	// "/v1/brokers/{id}/decommission", so that it is safe to use as a
+	// metric label. The status is the response status code, or 0 if no
+	// response was received.
+	ObserveRequest(endpoint string, status int, d time.Duration)
+}
+
+type nopMetricer struct{}
+
+func (nopMetricer) ObserveRequest(string, int, time.Duration) {}
+
+// endpointTemplates are the templates of the admin endpoints that have
+// non-numeric path parameters. Numeric path segments of any other endpoint
+// are templated as "{id}".
+var endpointTemplates = []string{
+	usersEndpoint + "/{user}",
+	logLevelEndpoint + "/{logger}",
+	partitionsEndpoint + "/{namespace}/{topic}",
+	partitionsEndpoint + "/{namespace}/{topic}/{partition}",
+	partitionsEndpoint + "/{namespace}/{topic}/{partition}/transfer_leadership",
+	partitionsEndpoint + "/{namespace}/{topic}/{partition}/cancel_reconfiguration",
+	cloudStorageEndpoint + "/status/{topic}/{partition}",
+}
+
+// endpointTemplate returns the templated form of the request path.
+func endpointTemplate(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for _, template := range endpointTemplates {
+		if matchesTemplate(segments, strings.Split(template, "/")) {
+			return template
+		}
+	}
+	for i, segment := range segments {
+		if isNumeric(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func matchesTemplate(segments, template []string) bool {
+	if len(segments) != len(template) {
+		return false
+	}
+	for i, t := range template {
+		isParam := strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}")
+		if !isParam && t != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
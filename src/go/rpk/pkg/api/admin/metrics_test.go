@@ -0,0 +1,80 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testMetricer struct {
+	mu       sync.Mutex
+	observed map[string][]int
+}
+
+func (m *testMetricer) ObserveRequest(endpoint string, status int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observed[endpoint] = append(m.observed[endpoint], status)
+}
+
+func TestWithMetrics(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/brokers/7/decommission" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}),
+	)
+	defer ts.Close()
+
+	m := &testMetricer{observed: make(map[string][]int)}
+	adminClient, err := NewAdminAPI([]string{ts.URL}, nil, WithMetrics(m))
+	require.NoError(t, err)
+
+	_, err = adminClient.Broker(context.Background(), 7)
+	require.NoError(t, err)
+	_, err = adminClient.Broker(context.Background(), 8)
+	require.NoError(t, err)
+	err = adminClient.DecommissionBroker(context.Background(), 7)
+	require.Error(t, err)
+	err = adminClient.DeleteUser(context.Background(), "Lola")
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][]int{
+		"/v1/brokers/{id}":              {200, 200},
+		"/v1/brokers/{id}/decommission": {409},
+		"/v1/security/users/{user}":     {200},
+	}, m.observed)
+}
+
+func TestEndpointTemplate(t *testing.T) {
+	for path, exp := range map[string]string{
+		"/v1/brokers":                   "/v1/brokers",
+		"/v1/brokers/12":                "/v1/brokers/{id}",
+		"/v1/brokers/12/recommission":   "/v1/brokers/{id}/recommission",
+		"/v1/security/users":            "/v1/security/users",
+		"/v1/security/users/1234":       "/v1/security/users/{user}",
+		"/v1/security/users/a%2Fb":      "/v1/security/users/{user}",
+		"/v1/brokers/12/decommission?x": "/v1/brokers/{id}/decommission",
+		"/v1/config/log_level/raft?x=1": "/v1/config/log_level/{logger}",
+		"/v1/partitions/kafka/foo/3":    "/v1/partitions/{namespace}/{topic}/{partition}",
+	} {
+		require.Equal(t, exp, endpointTemplate(path), "path %s", path)
+	}
+}
@@ -0,0 +1,492 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package mocks has a fake of the admin API client for tests.
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+)
+
+// MockAdminAPI is an admin.AdminClient that calls the Mock function of each
+// method if it is set, and otherwise returns zero values and a nil error.
+type MockAdminAPI struct {
+	MockBrokers                     func() ([]admin.Broker, error)
+	MockAliveBrokers                func() ([]admin.Broker, error)
+	MockBrokersWithStatus           func(status admin.MembershipStatus) ([]admin.Broker, error)
+	MockBroker                      func(node int) (admin.Broker, error)
+	MockClusterVersions             func() (map[int]string, error)
+	MockIsMixedVersion              func() (bool, error)
+	MockBrokersByRack               func() (map[string][]admin.Broker, error)
+	MockDecommissionBroker          func(node int) error
+	MockDecommissionBrokers         func(nodes []int, dryRun bool) error
+	MockRecommissionBroker          func(node int) error
+	MockDecommissionStatus          func(node int) (admin.DecommissionStatus, error)
+	MockWaitForBrokerRemoved        func(node int, poll time.Duration) error
+	MockWaitForBrokerActive         func(node int, poll time.Duration) error
+	MockEnableMaintenanceMode       func(node int) error
+	MockDisableMaintenanceMode      func(node int) error
+	MockMaintenanceStatus           func(node int) (admin.MaintenanceStatus, error)
+	MockRestartBroker               func(node int) error
+	MockDrainAndRestartBroker       func(node int, poll time.Duration) error
+	MockClusterHealth               func() (admin.ClusterHealth, error)
+	MockWaitForHealthy              func(poll time.Duration) error
+	MockReady                       func() (bool, error)
+	MockWaitForReady                func(poll time.Duration) error
+	MockGetController               func() (admin.Broker, error)
+	MockLicense                     func() (admin.License, error)
+	MockFeatures                    func() ([]admin.Feature, error)
+	MockIsFeatureActive             func(name string) (bool, error)
+	MockClusterConfig               func() (map[string]interface{}, error)
+	MockSetClusterConfig            func(upsert map[string]interface{}, remove []string) (admin.ClusterConfigWriteResult, error)
+	MockSetClusterConfigFromFile    func(path string, dryRun bool) (admin.ClusterConfigFileResult, error)
+	MockClusterConfigStatus         func() ([]admin.ClusterConfigNodeStatus, error)
+	MockNodeConfig                  func(node int) (map[string]interface{}, error)
+	MockNodeID                      func() (int, error)
+	MockKafkaListeners              func(node int) ([]admin.ListenerInfo, error)
+	MockLogLevel                    func(node int, logger string) (string, error)
+	MockSetLogLevel                 func(node int, logger, level string, expirySeconds int) error
+	MockPartition                   func(topic string, partition int) (admin.Partition, error)
+	MockPartitions                  func(topic string) ([]admin.Partition, error)
+	MockNamespacePartitions         func(namespace, topic string) ([]admin.Partition, error)
+	MockClusterPartitions           func() ([]admin.Partition, error)
+	MockTransferLeadership          func(topic string, partition, targetNode int) error
+	MockCancelReconfiguration       func(topic string, partition int) error
+	MockCancelAllReconfigurations   func() error
+	MockTriggerPartitionBalancer    func() error
+	MockPartitionBalancerStatus     func() (admin.BalancerStatus, error)
+	MockReconfigurations            func() ([]admin.Reconfiguration, error)
+	MockSetRecoveryThrottle         func(bytesPerSec int) error
+	MockGetRecoveryThrottle         func() (int, error)
+	MockCloudStorageStatus          func() (admin.CloudStorageStatus, error)
+	MockPartitionCloudStorageStatus func(topic string, partition int) (admin.PartitionCloudStorageStatus, error)
+	MockStartSelfTest               func(req admin.SelfTestRequest) (string, error)
+	MockStopSelfTest                func() error
+	MockSelfTestStatus              func() (admin.SelfTestResults, error)
+	MockCreateUser                  func(username, password string, mechanism admin.ScramMechanism) error
+	MockDeleteUser                  func(username string) error
+	MockListUsers                   func() ([]string, error)
+}
+
+var _ admin.AdminClient = MockAdminAPI{}
+
+func (m MockAdminAPI) Brokers(_ context.Context) ([]admin.Broker, error) {
+	if m.MockBrokers != nil {
+		return m.MockBrokers()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) AliveBrokers(_ context.Context) ([]admin.Broker, error) {
+	if m.MockAliveBrokers != nil {
+		return m.MockAliveBrokers()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) BrokersWithStatus(
+	_ context.Context, status admin.MembershipStatus,
+) ([]admin.Broker, error) {
+	if m.MockBrokersWithStatus != nil {
+		return m.MockBrokersWithStatus(status)
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) Broker(_ context.Context, node int) (admin.Broker, error) {
+	if m.MockBroker != nil {
+		return m.MockBroker(node)
+	}
+	return admin.Broker{}, nil
+}
+
+func (m MockAdminAPI) ClusterVersions(_ context.Context) (map[int]string, error) {
+	if m.MockClusterVersions != nil {
+		return m.MockClusterVersions()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) IsMixedVersion(_ context.Context) (bool, error) {
+	if m.MockIsMixedVersion != nil {
+		return m.MockIsMixedVersion()
+	}
+	return false, nil
+}
+
+func (m MockAdminAPI) BrokersByRack(_ context.Context) (map[string][]admin.Broker, error) {
+	if m.MockBrokersByRack != nil {
+		return m.MockBrokersByRack()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) DecommissionBroker(_ context.Context, node int) error {
+	if m.MockDecommissionBroker != nil {
+		return m.MockDecommissionBroker(node)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) DecommissionBrokers(_ context.Context, nodes []int, dryRun bool) error {
+	if m.MockDecommissionBrokers != nil {
+		return m.MockDecommissionBrokers(nodes, dryRun)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) RecommissionBroker(_ context.Context, node int) error {
+	if m.MockRecommissionBroker != nil {
+		return m.MockRecommissionBroker(node)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) DecommissionStatus(
+	_ context.Context, node int,
+) (admin.DecommissionStatus, error) {
+	if m.MockDecommissionStatus != nil {
+		return m.MockDecommissionStatus(node)
+	}
+	return admin.DecommissionStatus{}, nil
+}
+
+func (m MockAdminAPI) WaitForBrokerRemoved(_ context.Context, node int, poll time.Duration) error {
+	if m.MockWaitForBrokerRemoved != nil {
+		return m.MockWaitForBrokerRemoved(node, poll)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) WaitForBrokerActive(_ context.Context, node int, poll time.Duration) error {
+	if m.MockWaitForBrokerActive != nil {
+		return m.MockWaitForBrokerActive(node, poll)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) EnableMaintenanceMode(_ context.Context, node int) error {
+	if m.MockEnableMaintenanceMode != nil {
+		return m.MockEnableMaintenanceMode(node)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) DisableMaintenanceMode(_ context.Context, node int) error {
+	if m.MockDisableMaintenanceMode != nil {
+		return m.MockDisableMaintenanceMode(node)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) MaintenanceStatus(
+	_ context.Context, node int,
+) (admin.MaintenanceStatus, error) {
+	if m.MockMaintenanceStatus != nil {
+		return m.MockMaintenanceStatus(node)
+	}
+	return admin.MaintenanceStatus{}, nil
+}
+
+func (m MockAdminAPI) RestartBroker(_ context.Context, node int) error {
+	if m.MockRestartBroker != nil {
+		return m.MockRestartBroker(node)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) DrainAndRestartBroker(_ context.Context, node int, poll time.Duration) error {
+	if m.MockDrainAndRestartBroker != nil {
+		return m.MockDrainAndRestartBroker(node, poll)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) ClusterHealth(_ context.Context) (admin.ClusterHealth, error) {
+	if m.MockClusterHealth != nil {
+		return m.MockClusterHealth()
+	}
+	return admin.ClusterHealth{}, nil
+}
+
+func (m MockAdminAPI) WaitForHealthy(_ context.Context, poll time.Duration) error {
+	if m.MockWaitForHealthy != nil {
+		return m.MockWaitForHealthy(poll)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) Ready(_ context.Context) (bool, error) {
+	if m.MockReady != nil {
+		return m.MockReady()
+	}
+	return false, nil
+}
+
+func (m MockAdminAPI) WaitForReady(_ context.Context, poll time.Duration) error {
+	if m.MockWaitForReady != nil {
+		return m.MockWaitForReady(poll)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) GetController(_ context.Context) (admin.Broker, error) {
+	if m.MockGetController != nil {
+		return m.MockGetController()
+	}
+	return admin.Broker{}, nil
+}
+
+func (m MockAdminAPI) License(_ context.Context) (admin.License, error) {
+	if m.MockLicense != nil {
+		return m.MockLicense()
+	}
+	return admin.License{}, nil
+}
+
+func (m MockAdminAPI) Features(_ context.Context) ([]admin.Feature, error) {
+	if m.MockFeatures != nil {
+		return m.MockFeatures()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) IsFeatureActive(_ context.Context, name string) (bool, error) {
+	if m.MockIsFeatureActive != nil {
+		return m.MockIsFeatureActive(name)
+	}
+	return false, nil
+}
+
+func (m MockAdminAPI) ClusterConfig(_ context.Context) (map[string]interface{}, error) {
+	if m.MockClusterConfig != nil {
+		return m.MockClusterConfig()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) SetClusterConfig(
+	_ context.Context, upsert map[string]interface{}, remove []string,
+) (admin.ClusterConfigWriteResult, error) {
+	if m.MockSetClusterConfig != nil {
+		return m.MockSetClusterConfig(upsert, remove)
+	}
+	return admin.ClusterConfigWriteResult{}, nil
+}
+
+func (m MockAdminAPI) SetClusterConfigFromFile(
+	_ context.Context, _ afero.Fs, path string, dryRun bool,
+) (admin.ClusterConfigFileResult, error) {
+	if m.MockSetClusterConfigFromFile != nil {
+		return m.MockSetClusterConfigFromFile(path, dryRun)
+	}
+	return admin.ClusterConfigFileResult{}, nil
+}
+
+func (m MockAdminAPI) ClusterConfigStatus(
+	_ context.Context,
+) ([]admin.ClusterConfigNodeStatus, error) {
+	if m.MockClusterConfigStatus != nil {
+		return m.MockClusterConfigStatus()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) NodeID(_ context.Context) (int, error) {
+	if m.MockNodeID != nil {
+		return m.MockNodeID()
+	}
+	return 0, nil
+}
+
+func (m MockAdminAPI) KafkaListeners(_ context.Context, node int) ([]admin.ListenerInfo, error) {
+	if m.MockKafkaListeners != nil {
+		return m.MockKafkaListeners(node)
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) NodeConfig(_ context.Context, node int) (map[string]interface{}, error) {
+	if m.MockNodeConfig != nil {
+		return m.MockNodeConfig(node)
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) LogLevel(
+	_ context.Context, node int, logger string,
+) (string, error) {
+	if m.MockLogLevel != nil {
+		return m.MockLogLevel(node, logger)
+	}
+	return "", nil
+}
+
+func (m MockAdminAPI) SetLogLevel(
+	_ context.Context, node int, logger, level string, expirySeconds int,
+) error {
+	if m.MockSetLogLevel != nil {
+		return m.MockSetLogLevel(node, logger, level, expirySeconds)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) Partition(
+	_ context.Context, topic string, partition int,
+) (admin.Partition, error) {
+	if m.MockPartition != nil {
+		return m.MockPartition(topic, partition)
+	}
+	return admin.Partition{}, nil
+}
+
+func (m MockAdminAPI) Partitions(_ context.Context, topic string) ([]admin.Partition, error) {
+	if m.MockPartitions != nil {
+		return m.MockPartitions(topic)
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) NamespacePartitions(
+	_ context.Context, namespace, topic string,
+) ([]admin.Partition, error) {
+	if m.MockNamespacePartitions != nil {
+		return m.MockNamespacePartitions(namespace, topic)
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) ClusterPartitions(_ context.Context) ([]admin.Partition, error) {
+	if m.MockClusterPartitions != nil {
+		return m.MockClusterPartitions()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) TransferLeadership(
+	_ context.Context, topic string, partition, targetNode int,
+) error {
+	if m.MockTransferLeadership != nil {
+		return m.MockTransferLeadership(topic, partition, targetNode)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) CancelReconfiguration(_ context.Context, topic string, partition int) error {
+	if m.MockCancelReconfiguration != nil {
+		return m.MockCancelReconfiguration(topic, partition)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) CancelAllReconfigurations(_ context.Context) error {
+	if m.MockCancelAllReconfigurations != nil {
+		return m.MockCancelAllReconfigurations()
+	}
+	return nil
+}
+
+func (m MockAdminAPI) TriggerPartitionBalancer(_ context.Context) error {
+	if m.MockTriggerPartitionBalancer != nil {
+		return m.MockTriggerPartitionBalancer()
+	}
+	return nil
+}
+
+func (m MockAdminAPI) PartitionBalancerStatus(_ context.Context) (admin.BalancerStatus, error) {
+	if m.MockPartitionBalancerStatus != nil {
+		return m.MockPartitionBalancerStatus()
+	}
+	return admin.BalancerStatus{}, nil
+}
+
+func (m MockAdminAPI) Reconfigurations(_ context.Context) ([]admin.Reconfiguration, error) {
+	if m.MockReconfigurations != nil {
+		return m.MockReconfigurations()
+	}
+	return nil, nil
+}
+
+func (m MockAdminAPI) SetRecoveryThrottle(_ context.Context, bytesPerSec int) error {
+	if m.MockSetRecoveryThrottle != nil {
+		return m.MockSetRecoveryThrottle(bytesPerSec)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) GetRecoveryThrottle(_ context.Context) (int, error) {
+	if m.MockGetRecoveryThrottle != nil {
+		return m.MockGetRecoveryThrottle()
+	}
+	return 0, nil
+}
+
+func (m MockAdminAPI) CloudStorageStatus(_ context.Context) (admin.CloudStorageStatus, error) {
+	if m.MockCloudStorageStatus != nil {
+		return m.MockCloudStorageStatus()
+	}
+	return admin.CloudStorageStatus{}, nil
+}
+
+func (m MockAdminAPI) PartitionCloudStorageStatus(
+	_ context.Context, topic string, partition int,
+) (admin.PartitionCloudStorageStatus, error) {
+	if m.MockPartitionCloudStorageStatus != nil {
+		return m.MockPartitionCloudStorageStatus(topic, partition)
+	}
+	return admin.PartitionCloudStorageStatus{}, nil
+}
+
+func (m MockAdminAPI) StartSelfTest(_ context.Context, req admin.SelfTestRequest) (string, error) {
+	if m.MockStartSelfTest != nil {
+		return m.MockStartSelfTest(req)
+	}
+	return "", nil
+}
+
+func (m MockAdminAPI) StopSelfTest(_ context.Context) error {
+	if m.MockStopSelfTest != nil {
+		return m.MockStopSelfTest()
+	}
+	return nil
+}
+
+func (m MockAdminAPI) SelfTestStatus(_ context.Context) (admin.SelfTestResults, error) {
+	if m.MockSelfTestStatus != nil {
+		return m.MockSelfTestStatus()
+	}
+	return admin.SelfTestResults{}, nil
+}
+
+func (m MockAdminAPI) CreateUser(
+	_ context.Context, username, password string, mechanism admin.ScramMechanism,
+) error {
+	if m.MockCreateUser != nil {
+		return m.MockCreateUser(username, password, mechanism)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) DeleteUser(_ context.Context, username string) error {
+	if m.MockDeleteUser != nil {
+		return m.MockDeleteUser(username)
+	}
+	return nil
+}
+
+func (m MockAdminAPI) ListUsers(_ context.Context) ([]string, error) {
+	if m.MockListUsers != nil {
+		return m.MockListUsers()
+	}
+	return nil, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const nodeConfigEndpoint = "/v1/node_config"
+
+// sendToNode sends a request to the host of the given node, for requests
+// that only affect the node that receives them.
+func (a *AdminAPI) sendToNode(
+	ctx context.Context, node int, method, path string, body, into interface{},
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx = ensureCorrelationID(ctx)
+	if err := a.discover(ctx); err != nil {
+		return err
+	}
+	host, err := a.hostForNode(ctx, node)
+	if err != nil {
+		return err
+	}
+	res, err := a.sendAndReceive(ctx, method, host, path, body)
+	if err != nil {
+		return err
+	}
+	return maybeUnmarshalRespInto(method, host+path, res, into)
+}
+
+// sendToController sends a request to the host of the controller leader.
+func (a *AdminAPI) sendToController(
+	ctx context.Context, method, path string, body, into interface{},
+) error {
+	ctx = ensureCorrelationID(ctx)
+	id, err := a.controllerID(ctx)
+	if err != nil {
+		return err
+	}
+	return a.sendToNode(ctx, id, method, path, body, into)
+}
+
+// hostForNode returns which of the client's hosts is the given node. Each
+// host's node ID is asked for once and then cached.
+func (a *AdminAPI) hostForNode(ctx context.Context, node int) (string, error) {
+	a.mu.Lock()
+	host, ok := a.nodeHosts[node]
+	a.mu.Unlock()
+	if ok {
+		return host, nil
+	}
+
+	var errs *multierror.Error
+	for _, host := range a.currentURLs() {
+		var nc struct {
+			NodeID int `json:"node_id"`
+		}
+		res, err := a.sendAndReceive(ctx, http.MethodGet, host, nodeConfigEndpoint, nil)
+		if err == nil {
+			err = maybeUnmarshalRespInto(http.MethodGet, host+nodeConfigEndpoint, res, &nc)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", err
+			}
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		a.mu.Lock()
+		a.nodeHosts[nc.NodeID] = host
+		a.mu.Unlock()
+		if nc.NodeID == node {
+			return host, nil
+		}
+	}
+	if err := errs.ErrorOrNil(); err != nil {
+		return "", fmt.Errorf("unable to find the admin host of node %d: %w", node, err)
+	}
+	return "", fmt.Errorf("unknown admin address of node %d: none of the admin hosts is that node", node)
+}
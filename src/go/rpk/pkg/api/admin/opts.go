@@ -0,0 +1,231 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Opt is an option to configure an AdminAPI.
+type Opt func(*AdminAPI)
+
+// WithMaxResponseBytes limits how many bytes of a single response body the
+// client reads into memory. Reading past the limit fails with
+// ErrResponseTooLarge.
+//
+// The default is DefaultMaxResponseBytes. A limit of zero or less means no
+// limit.
+func WithMaxResponseBytes(n int64) Opt {
+	return func(a *AdminAPI) { a.maxBody = n }
+}
+
+// WithTimeout sets the timeout for a single request to a single admin host,
+// which includes reading the response body. This does not bound an entire
+// operation: a request sent to every host has each of its requests bounded
+// individually.
+//
+// The default is DefaultRequestTimeout. A zero timeout means no timeout.
+func WithTimeout(d time.Duration) Opt {
+	return func(a *AdminAPI) { a.timeout = d }
+}
+
+// SetRequestTimeout sets the timeout for a single request to a single admin
+// host, with the same semantics as WithTimeout. The new timeout applies to
+// requests that are issued after this returns.
+func (a *AdminAPI) SetRequestTimeout(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.timeout = d
+}
+
+func (a *AdminAPI) requestTimeout() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.timeout
+}
+
+// WithRetries retries a failed request to a host up to n times, waiting
+// between each attempt with an exponential, jittered backoff that starts at
+// base and is capped at max.
+//
+// Only attempts that failed transiently are retried: 5xx and 429 responses,
+// refused connections, and connections closed before a response (EOF). Other
+// 4xx responses are never retried. If a 429 or 503 response has a
+// Retry-After header, that delay is used instead of the backoff, still capped
+// at max. Canceling the request context aborts any pending backoff.
+func WithRetries(n int, base, max time.Duration) Opt {
+	return func(a *AdminAPI) {
+		if max < base {
+			max = base
+		}
+		a.retry = retryPolicy{retries: n, base: base, max: max}
+	}
+}
+
+// WithTLS talks to the admin hosts over https, presenting cert as the client
+// certificate and verifying the servers against the CAs in caPool. If caPool
+// is nil, the system's root CAs are used.
+//
+// The server name used for SNI and certificate verification is the host of
+// each URL, so hosts should be passed as the names the certificates were
+// issued for. This option takes precedence over the tls configuration passed
+// to NewAdminAPI.
+func WithTLS(cert tls.Certificate, caPool *x509.CertPool) Opt {
+	return func(a *AdminAPI) {
+		a.tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      caPool,
+		}
+	}
+}
+
+// WithInsecureSkipVerify, if skip is true, disables verification of the
+// admin servers' certificates. This is meant only for development clusters
+// using self-signed certificates and is deliberately separate from WithTLS.
+//
+// This implies https, even if no other tls configuration is given. If a
+// request hook is configured, the first request reports a warning that
+// verification is disabled.
+func WithInsecureSkipVerify(skip bool) Opt {
+	return func(a *AdminAPI) { a.insecure = skip }
+}
+
+// WithFastestHost, if enabled, sends read-only (GET) requests that would be
+// sent to any single host to every host concurrently instead, using the first
+// successful response and canceling the rest. If every host fails, the
+// returned error contains each host's failure. Requests that must reach every
+// host, such as decommissioning a broker, are unaffected.
+func WithFastestHost(enabled bool) Opt {
+	return func(a *AdminAPI) { a.fastest = enabled }
+}
+
+// WithControllerOnly sends every request that is not a GET, other than
+// requests for a single node such as SetLogLevel, only to the controller
+// leader rather than to one or all hosts. The controller is looked up
+// before each such request, and the hosts must include the controller.
+func WithControllerOnly() Opt {
+	return func(a *AdminAPI) { a.controllerOnly = true }
+}
+
+// WithHostStrategy sets how the client picks the first host to try for a
+// request that only needs to reach one host. If that host fails, the request
+// still fails over to the next host. The default is RandomHost.
+func WithHostStrategy(strategy HostStrategy) Opt {
+	return func(a *AdminAPI) { a.strategy = strategy }
+}
+
+// WithHostCooldown sets how long a host that failed a request is tried after
+// every other host, for requests that only need to reach one host. The
+// default is DefaultHostCooldown.
+func WithHostCooldown(d time.Duration) Opt {
+	return func(a *AdminAPI) { a.cooldown = d }
+}
+
+// WithHeader adds the given header to every request. This option can be used
+// multiple times; values for the same key accumulate rather than overwrite
+// each other. Headers that the client sets itself, such as Content-Type, are
+// not overridden.
+func WithHeader(key, value string) Opt {
+	return func(a *AdminAPI) { a.headers.Add(key, value) }
+}
+
+// WithBasicAuth sends the given user and password with every request using
+// HTTP basic authentication. This cannot be combined with WithBearerToken or
+// WithTokenProvider.
+func WithBasicAuth(user, password string) Opt {
+	return func(a *AdminAPI) { a.basicAuth = &basicAuth{user, password} }
+}
+
+// WithBearerToken sends "Authorization: Bearer <token>" with every request.
+// This cannot be combined with WithBasicAuth.
+func WithBearerToken(token string) Opt {
+	return WithTokenProvider(func() (string, error) { return token, nil })
+}
+
+// WithTokenProvider sends "Authorization: Bearer <token>" with every request,
+// calling provider for the token before each attempt (including retries) so
+// that short lived tokens can be refreshed. If the provider returns an error,
+// the request is aborted with that error.
+func WithTokenProvider(provider func() (string, error)) Opt {
+	return func(a *AdminAPI) { a.tokenProvider = provider }
+}
+
+// WithRequestHook calls hook once for every HTTP request the client issues,
+// including every retry and every host that a request is sent to. The hook
+// may be called concurrently and should not block.
+func WithRequestHook(hook func(RequestInfo)) Opt {
+	return func(a *AdminAPI) { a.hook = hook }
+}
+
+// WithMetrics reports every HTTP request the client issues to m.
+func WithMetrics(m Metricer) Opt {
+	return func(a *AdminAPI) {
+		if m != nil {
+			a.metrics = m
+		}
+	}
+}
+
+// WithProxy sends every request through the proxy at the given URL, e.g.
+// "http://proxy.internal:3128", rather than the proxy in the HTTP_PROXY and
+// HTTPS_PROXY environment variables. Hosts in NO_PROXY are still not proxied.
+func WithProxy(url string) Opt {
+	return func(a *AdminAPI) { a.proxy = url }
+}
+
+// WithSRVDiscovery discovers the admin hosts from the targets of the given
+// DNS SRV record, e.g. "_admin._tcp.redpanda.example.com", which is resolved
+// again every DefaultSRVRefresh. If the record cannot be resolved, the
+// client keeps using the hosts it last discovered, or the urls it was built
+// with, and requests fail with ErrNoHosts only if there are none.
+//
+// With this option, the client may be built without any urls.
+func WithSRVDiscovery(service string) Opt {
+	return func(a *AdminAPI) {
+		a.srv = &srvDiscovery{
+			service: service,
+			lookup:  net.DefaultResolver.LookupSRV,
+			refresh: DefaultSRVRefresh,
+		}
+	}
+}
+
+// WithAutoRefresh refreshes the client's hosts from the cluster's brokers, as
+// RefreshHosts does, before the first request and then at most every
+// interval. The refresh is done as part of a request once the hosts are due;
+// if it fails, the client keeps its current hosts.
+//
+// This has no effect on a client built WithSRVDiscovery.
+func WithAutoRefresh(interval time.Duration) Opt {
+	return func(a *AdminAPI) { a.autoRefresh = interval }
+}
+
+// WithTracer starts a span with t around every HTTP request the client
+// issues.
+func WithTracer(t Tracer) Opt {
+	return func(a *AdminAPI) {
+		if t != nil {
+			a.tracer = t
+		}
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second across all of
+// its hosts, allowing bursts of up to burst requests. Every HTTP request is
+// limited, including retries and each host that a request is sent to.
+// Waiting for the limiter is aborted if the request context is canceled.
+func WithRateLimit(rps float64, burst int) Opt {
+	return func(a *AdminAPI) { a.limiter = rate.NewLimiter(rate.Limit(rps), burst) }
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc returns the function that the transport uses to choose the proxy
+// of each request. The proxy is read from the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables, with the proxy set WithProxy, if any,
+// overriding the former two. Hosts that NO_PROXY exempts, loopback hosts, and
+// unix sockets are never proxied.
+func (a *AdminAPI) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	cfg := httpproxy.FromEnvironment()
+	if a.proxy != "" {
+		u, err := url.Parse(a.proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin api proxy %q: %w", a.proxy, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid admin api proxy %q: missing scheme or host", a.proxy)
+		}
+		cfg.HTTPProxy = a.proxy
+		cfg.HTTPSProxy = a.proxy
+	}
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		if _, ok := a.sockets[req.URL.Host+":80"]; ok {
+			return nil, nil
+		}
+		return proxy(req.URL)
+	}, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setProxyEnv(t *testing.T, env map[string]string) {
+	for _, k := range []string{
+		"HTTP_PROXY", "http_proxy",
+		"HTTPS_PROXY", "https_proxy",
+		"NO_PROXY", "no_proxy",
+		"REQUEST_METHOD",
+	} {
+		prev, ok := os.LookupEnv(k)
+		os.Unsetenv(k)
+		k := k
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		proxy string
+		host  string
+		exp   string
+	}{
+		{
+			name: "no proxy by default",
+			host: "http://redpanda-0.internal:9644",
+		},
+		{
+			name: "http proxy from the environment",
+			env:  map[string]string{"HTTP_PROXY": "http://env-proxy:3128"},
+			host: "http://redpanda-0.internal:9644",
+			exp:  "http://env-proxy:3128",
+		},
+		{
+			name: "https proxy from the environment",
+			env: map[string]string{
+				"HTTP_PROXY":  "http://env-proxy:3128",
+				"HTTPS_PROXY": "http://secure-proxy:3128",
+			},
+			host: "https://redpanda-0.internal:9644",
+			exp:  "http://secure-proxy:3128",
+		},
+		{
+			name:  "explicit proxy overrides the environment",
+			env:   map[string]string{"HTTP_PROXY": "http://env-proxy:3128"},
+			proxy: "http://my-proxy:8080",
+			host:  "http://redpanda-0.internal:9644",
+			exp:   "http://my-proxy:8080",
+		},
+		{
+			name: "NO_PROXY exempts hosts",
+			env: map[string]string{
+				"HTTP_PROXY": "http://env-proxy:3128",
+				"NO_PROXY":   ".internal",
+			},
+			host: "http://redpanda-0.internal:9644",
+		},
+		{
+			name:  "NO_PROXY exempts hosts from the explicit proxy",
+			env:   map[string]string{"NO_PROXY": "redpanda-0.internal"},
+			proxy: "http://my-proxy:8080",
+			host:  "http://redpanda-0.internal:9644",
+		},
+		{
+			name:  "unix sockets are never proxied",
+			proxy: "http://my-proxy:8080",
+			host:  "unix:///var/run/redpanda/admin.sock",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setProxyEnv(t, tt.env)
+			var opts []Opt
+			if tt.proxy != "" {
+				opts = append(opts, WithProxy(tt.proxy))
+			}
+			adminClient, err := NewAdminAPI([]string{tt.host}, nil, opts...)
+			require.NoError(t, err)
+
+			proxy := adminClient.client.Transport.(*http.Transport).Proxy
+			req, err := http.NewRequest(http.MethodGet, adminClient.urls[0]+brokersEndpoint, nil)
+			require.NoError(t, err)
+			u, err := proxy(req)
+			require.NoError(t, err)
+			if tt.exp == "" {
+				require.Nil(t, u)
+				return
+			}
+			require.NotNil(t, u)
+			require.Equal(t, tt.exp, u.String())
+		})
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	setProxyEnv(t, nil)
+	var got *url.URL
+	proxy := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL
+			w.Write([]byte(`[]`))
+		}),
+	)
+	defer proxy.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{"redpanda-0.internal:9644"}, nil, WithProxy(proxy.URL),
+	)
+	require.NoError(t, err)
+
+	_, err = adminClient.Brokers(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "http://redpanda-0.internal:9644"+brokersEndpoint, got.String())
+}
+
+func TestWithProxyInvalid(t *testing.T) {
+	setProxyEnv(t, nil)
+	_, err := NewAdminAPI([]string{"localhost:9644"}, nil, WithProxy("my-proxy"))
+	require.Error(t, err)
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// rawBody is a request body that is sent without being json encoded.
+type rawBody []byte
+
+// DoRaw sends a request to one of the client's hosts and returns the
+// response without reading it, for endpoints or response headers that the
+// typed methods do not expose. The request goes through the same host
+// failover, authentication, retries, and hooks as every other request. The
+// path includes any query, e.g. "/v1/cluster_config?include_defaults=true",
+// and body, if non-nil, is sent as is.
+//
+// The caller owns the returned response and must close its body. A non-2xx
+// response is returned as an *HTTPResponseError, with the body already read
+// and closed.
+func (a *AdminAPI) DoRaw(
+	ctx context.Context, method, path string, body io.Reader,
+) (*http.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ctx = ensureCorrelationID(ctx)
+	if err := a.discover(ctx); err != nil {
+		return nil, err
+	}
+	// The body is buffered so that it can be sent again to other hosts
+	// and on retries.
+	var rb interface{}
+	if body != nil {
+		bs, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read request body for %s %s: %w", method, path, err)
+		}
+		rb = rawBody(bs)
+	}
+	_, res, err := a.sendFailover(ctx, method, path, rb)
+	return res, err
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoRaw(t *testing.T) {
+	down := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)
+	defer down.Close()
+	up := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v1/echo":
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, "v=1", r.URL.RawQuery)
+				w.Header().Set("ETag", `"7"`)
+				w.Write(body)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer up.Close()
+
+	adminClient, err := NewAdminAPI([]string{down.URL, up.URL}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The body is not json encoded and is sent again to the host that is
+	// up, whichever host is tried first.
+	res, err := adminClient.DoRaw(ctx, http.MethodPost, "/v1/echo?v=1", strings.NewReader("not json"))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, `"7"`, res.Header.Get("ETag"))
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "not json", string(body))
+
+	_, err = adminClient.DoRaw(ctx, http.MethodGet, "/v1/missing", nil)
+	var he *HTTPResponseError
+	require.True(t, errors.As(err, &he), "got %v", err)
+	require.Equal(t, http.StatusNotFound, he.StatusCode)
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RefreshHosts replaces the hosts the client sends requests to with the admin
+// addresses of the cluster's brokers, which allows building the client with a
+// single seed host. Removed brokers are dropped.
+//
+// Brokers on versions that do not report their admin address keep the host
+// the client already knows them by. If the host of any broker is unknown,
+// the client's current hosts are kept alongside the refreshed hosts, so that
+// no broker becomes unreachable.
+func (a *AdminAPI) RefreshHosts(ctx context.Context) error {
+	if a.srv != nil {
+		return errors.New("unable to refresh the hosts of an admin api client that discovers hosts from SRV records")
+	}
+	bs, err := a.Brokers(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to refresh admin api hosts: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var (
+		hosts   []string
+		seen    = make(map[string]bool)
+		unknown bool
+	)
+	add := func(host string) {
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	nodeHosts := make(map[int]string, len(bs))
+	for _, b := range bs {
+		if b.MembershipStatus == MembershipRemoved {
+			continue
+		}
+		if b.AdminAddress == "" {
+			host, ok := a.nodeHosts[b.NodeID]
+			if !ok {
+				unknown = true
+				continue
+			}
+			nodeHosts[b.NodeID] = host
+			add(host)
+			continue
+		}
+		host, err := a.hostURL(b.AdminAddress)
+		if err != nil {
+			return fmt.Errorf("unable to refresh admin api hosts: broker %d: %w", b.NodeID, err)
+		}
+		nodeHosts[b.NodeID] = host
+		add(host)
+	}
+	if unknown {
+		for _, host := range a.urls {
+			add(host)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	a.setHosts(hosts, nodeHosts)
+	return nil
+}
+
+// maybeRefreshHosts refreshes the client's hosts if the client was built
+// WithAutoRefresh and the hosts are due a refresh. Failing to refresh keeps
+// the current hosts, and is retried once the hosts are due again.
+func (a *AdminAPI) maybeRefreshHosts(ctx context.Context) {
+	if a.autoRefresh <= 0 {
+		return
+	}
+	a.mu.Lock()
+	due := time.Since(a.refreshedAt) >= a.autoRefresh
+	if due {
+		// Marking the hosts as refreshed before refreshing them keeps
+		// the request that RefreshHosts issues, and any concurrent
+		// request, from refreshing them too.
+		a.refreshedAt = time.Now()
+	}
+	a.mu.Unlock()
+	if due {
+		a.RefreshHosts(ctx)
+	}
+}
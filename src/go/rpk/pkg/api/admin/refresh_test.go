@@ -0,0 +1,112 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshHosts(t *testing.T) {
+	var brokers string
+	seed := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(brokers))
+		}),
+	)
+	defer seed.Close()
+	seedAddr := seed.Listener.Addr().String()
+
+	tests := []struct {
+		name    string
+		brokers string
+		exp     []string
+	}{
+		{
+			name: "brokers report their addresses",
+			brokers: fmt.Sprintf(`[
+{"node_id": 1, "membership_status": "active", "admin_address": %q},
+{"node_id": 2, "membership_status": "active", "admin_address": "10.0.0.2:9644"},
+{"node_id": 3, "membership_status": "removed", "admin_address": "10.0.0.3:9644"}
+]`, seedAddr),
+			exp: []string{seed.URL, "http://10.0.0.2:9644"},
+		},
+		{
+			name: "unknown hosts keep the current hosts",
+			brokers: `[
+{"node_id": 1, "membership_status": "active"},
+{"node_id": 2, "membership_status": "active", "admin_address": "http://10.0.0.2:9644"}
+]`,
+			exp: []string{"http://10.0.0.2:9644", seed.URL},
+		},
+		{
+			name:    "no addresses keep the current hosts",
+			brokers: `[{"node_id": 1, "membership_status": "active"}]`,
+			exp:     []string{seed.URL},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brokers = tt.brokers
+			adminClient, err := NewAdminAPI([]string{seed.URL}, nil)
+			require.NoError(t, err)
+
+			err = adminClient.RefreshHosts(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, adminClient.currentURLs())
+		})
+	}
+}
+
+func TestWithAutoRefresh(t *testing.T) {
+	var hits2 int32
+	ts2 := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits2, 1)
+		}),
+	)
+	defer ts2.Close()
+	var brokersHits int32
+	seed := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == brokersEndpoint {
+				atomic.AddInt32(&brokersHits, 1)
+				fmt.Fprintf(w, `[{"node_id": 1, "admin_address": %q}, {"node_id": 2, "admin_address": %q}]`,
+					r.Host, ts2.Listener.Addr().String())
+			}
+		}),
+	)
+	defer seed.Close()
+
+	adminClient, err := NewAdminAPI([]string{seed.URL}, nil, WithAutoRefresh(time.Hour))
+	require.NoError(t, err)
+
+	// The first request refreshes the hosts before it is sent, and
+	// later requests do not until the interval passed.
+	for i := 1; i <= 2; i++ {
+		err = adminClient.RecommissionBroker(context.Background(), 1)
+		require.NoError(t, err)
+		require.EqualValues(t, i, atomic.LoadInt32(&hits2))
+		require.EqualValues(t, 1, atomic.LoadInt32(&brokersHits))
+	}
+}
+
+func TestRefreshHostsSRV(t *testing.T) {
+	adminClient, err := NewAdminAPI(nil, nil, WithSRVDiscovery("_admin._tcp.redpanda.example.com"))
+	require.NoError(t, err)
+	require.Error(t, adminClient.RefreshHosts(context.Background()))
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// retryPolicy configures how many times and how often a request to a single
+// host is retried. The zero value does not retry.
+type retryPolicy struct {
+	retries int
+	base    time.Duration
+	max     time.Duration
+}
+
+// backoff returns how long to wait before the retry following the given
+// (zero based) attempt. The delay doubles with each attempt up to the
+// policy's max, and half of it is jittered so that concurrent clients do not
+// retry in lockstep.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	d := p.max
+	if attempt < 32 {
+		if exp := p.base << uint(attempt); exp > 0 && exp < p.max {
+			d = exp
+		}
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rng(int(half)))
+}
+
+func (a *AdminAPI) retryPolicy() retryPolicy {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.retry
+}
+
+// delay returns how long to wait before the retry following the given
+// attempt. If the server rate limited us or is unavailable and says when to
+// retry with a Retry-After header, we wait that long, capped by the policy's
+// max; otherwise we use the exponential backoff.
+func (p retryPolicy) delay(attempt int, res *http.Response) time.Duration {
+	if res != nil &&
+		(res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			if d > p.max {
+				d = p.max
+			}
+			return d
+		}
+	}
+	return p.backoff(attempt)
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	d := time.Until(t)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// isRetryable returns whether a failed attempt may succeed if retried: the
+// server responded with a 5xx or rate limited us with a 429, refused the
+// connection, or closed it early. Other client errors (4xx) are never
+// retried.
+func isRetryable(res *http.Response, err error) bool {
+	if res != nil {
+		return res.StatusCode/100 == 5 || res.StatusCode == http.StatusTooManyRequests
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// sleepCtx sleeps for d, returning early with the context's error if the
+// context is canceled first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
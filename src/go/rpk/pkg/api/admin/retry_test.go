@@ -0,0 +1,229 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  int
+		expHits  int32
+		expErr   bool
+	}{
+		{
+			name:     "retries 5xx until success",
+			statuses: []int{503, 500, 200},
+			retries:  3,
+			expHits:  3,
+		},
+		{
+			name:     "gives up after the configured retries",
+			statuses: []int{503, 503, 503, 503},
+			retries:  2,
+			expHits:  3,
+			expErr:   true,
+		},
+		{
+			name:     "never retries 4xx",
+			statuses: []int{409, 200},
+			retries:  3,
+			expHits:  1,
+			expErr:   true,
+		},
+		{
+			name:     "does not retry by default",
+			statuses: []int{503, 200},
+			expHits:  1,
+			expErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			ts := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					n := atomic.AddInt32(&hits, 1)
+					w.WriteHeader(tt.statuses[n-1])
+				}),
+			)
+			defer ts.Close()
+
+			var opts []Opt
+			if tt.retries > 0 {
+				opts = append(opts, WithRetries(tt.retries, time.Millisecond, 5*time.Millisecond))
+			}
+			adminClient, err := NewAdminAPI([]string{ts.URL}, nil, opts...)
+			require.NoError(t, err)
+
+			err = adminClient.DecommissionBroker(context.Background(), 1)
+			if tt.expErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expHits, atomic.LoadInt32(&hits))
+		})
+	}
+}
+
+func TestRetriesConnectionRefused(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{url}, nil, WithRetries(2, time.Millisecond, time.Millisecond),
+	)
+	require.NoError(t, err)
+	_, err = adminClient.Broker(context.Background(), 1)
+	require.Error(t, err)
+	require.True(t, isRetryable(nil, err))
+}
+
+func TestRetriesCancelBackoff(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{ts.URL}, nil, WithRetries(5, time.Hour, time.Hour),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = adminClient.DecommissionBroker(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, int64(time.Since(start)), int64(time.Minute))
+}
+
+func TestBackoff(t *testing.T) {
+	p := retryPolicy{retries: 10, base: 100 * time.Millisecond, max: time.Second}
+	for attempt := 0; attempt < 64; attempt++ {
+		exp := p.base << uint(attempt)
+		if attempt >= 32 || exp <= 0 || exp > p.max {
+			exp = p.max
+		}
+		d := p.backoff(attempt)
+		require.GreaterOrEqual(t, int64(d), int64(exp/2))
+		require.LessOrEqual(t, int64(d), int64(exp))
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	p := retryPolicy{retries: 3, base: time.Millisecond, max: 10 * time.Second}
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		exp        time.Duration
+		backoff    bool
+	}{
+		{name: "429 with seconds", status: 429, retryAfter: "2", exp: 2 * time.Second},
+		{name: "503 with seconds", status: 503, retryAfter: "3", exp: 3 * time.Second},
+		{name: "capped by max", status: 503, retryAfter: "120", exp: 10 * time.Second},
+		{name: "date in the past", status: 429, retryAfter: "Mon, 02 Jan 2006 15:04:05 GMT", exp: 0},
+		{name: "missing header", status: 503, backoff: true},
+		{name: "invalid header", status: 503, retryAfter: "soon", backoff: true},
+		{name: "negative seconds", status: 429, retryAfter: "-1", backoff: true},
+		{name: "ignored on 500", status: 500, retryAfter: "2", backoff: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{StatusCode: tt.status, Header: make(http.Header)}
+			if tt.retryAfter != "" {
+				res.Header.Set("Retry-After", tt.retryAfter)
+			}
+			d := p.delay(0, res)
+			if tt.backoff {
+				require.LessOrEqual(t, int64(d), int64(p.base))
+				return
+			}
+			require.Equal(t, tt.exp, d)
+		})
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	p := retryPolicy{retries: 3, base: time.Millisecond, max: time.Minute}
+	res := &http.Response{StatusCode: 429, Header: make(http.Header)}
+	res.Header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+	d := p.delay(0, res)
+	require.Greater(t, int64(d), int64(25*time.Second))
+	require.LessOrEqual(t, int64(d), int64(30*time.Second))
+}
+
+func TestRetriesTooManyRequests(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&hits, 1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{ts.URL}, nil, WithRetries(1, time.Hour, time.Hour),
+	)
+	require.NoError(t, err)
+
+	// Retry-After: 0 means we retry immediately rather than waiting for
+	// the hour long backoff.
+	err = adminClient.DecommissionBroker(context.Background(), 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits))
+}
+
+func TestRateLimit(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			w.Write([]byte(`{}`))
+		}),
+	)
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI(
+		[]string{ts.URL, ts.URL}, nil, WithRateLimit(0.001, 2),
+	)
+	require.NoError(t, err)
+
+	// The burst allows both requests of sendAll, after which the
+	// limiter is exhausted.
+	err = adminClient.RecommissionBroker(context.Background(), 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = adminClient.Broker(ctx, 1)
+	require.Error(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits))
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSRVRefresh is how long the hosts discovered from a DNS SRV record
+// are used before the record is resolved again.
+const DefaultSRVRefresh = 30 * time.Second
+
+// ErrNoHosts is returned when the client does not know any admin host, which
+// happens if the SRV record of a client built WithSRVDiscovery could never be
+// resolved.
+var ErrNoHosts = errors.New("no admin api hosts are known")
+
+type srvDiscovery struct {
+	service  string
+	lookup   func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	refresh  time.Duration
+	resolved time.Time
+}
+
+// discover resolves the client's SRV record into its hosts if the client was
+// built WithSRVDiscovery and the hosts are due a refresh. If the record
+// cannot be resolved, the last hosts that were discovered keep being used,
+// and an error is only returned if no hosts are known at all.
+//
+// Otherwise, if the client was built WithAutoRefresh, this refreshes the
+// hosts from the brokers if they are due a refresh.
+func (a *AdminAPI) discover(ctx context.Context) error {
+	if a.srv == nil {
+		a.maybeRefreshHosts(ctx)
+		return nil
+	}
+	a.mu.Lock()
+	stale := time.Since(a.srv.resolved) >= a.srv.refresh
+	a.mu.Unlock()
+	if !stale {
+		return nil
+	}
+
+	hosts, err := a.lookupSRV(ctx)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err != nil {
+		if len(a.urls) == 0 {
+			return fmt.Errorf("%w: %v", ErrNoHosts, err)
+		}
+		return nil
+	}
+	a.srv.resolved = time.Now()
+	if !equalHosts(a.urls, hosts) {
+		a.setHosts(hosts, make(map[int]string))
+	}
+	return nil
+}
+
+func (a *AdminAPI) lookupSRV(ctx context.Context) ([]string, error) {
+	_, srvs, err := a.srv.lookup(ctx, "", "", a.srv.service)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve SRV record %q: %w", a.srv.service, err)
+	}
+	if len(srvs) == 0 {
+		return nil, fmt.Errorf("SRV record %q has no targets", a.srv.service)
+	}
+	scheme := "http"
+	if a.tlsConfig != nil {
+		scheme = "https"
+	}
+	hosts := make([]string, 0, len(srvs))
+	for _, srv := range srvs {
+		target := strings.TrimSuffix(srv.Target, ".")
+		port := strconv.Itoa(int(srv.Port))
+		hosts = append(hosts, scheme+"://"+net.JoinHostPort(target, port))
+	}
+	return hosts, nil
+}
+
+// currentURLs returns the hosts the client currently sends requests to.
+func (a *AdminAPI) currentURLs() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.urls
+}
+
+func equalHosts(l, r []string) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for i := range l {
+		if l[i] != r[i] {
+			return false
+		}
+	}
+	return true
+}
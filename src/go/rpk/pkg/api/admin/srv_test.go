@@ -0,0 +1,103 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func srvTarget(t *testing.T, ts *httptest.Server) *net.SRV {
+	host, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return &net.SRV{Target: host + ".", Port: uint16(p)}
+}
+
+func TestSRVDiscovery(t *testing.T) {
+	var hits1, hits2 int32
+	ts1 := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits1, 1)
+		}),
+	)
+	defer ts1.Close()
+	ts2 := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits2, 1)
+		}),
+	)
+	defer ts2.Close()
+
+	const service = "_admin._tcp.redpanda.example.com"
+	var (
+		srvs      = []*net.SRV{srvTarget(t, ts1), srvTarget(t, ts2)}
+		lookupErr error
+		lookups   int
+	)
+	adminClient, err := NewAdminAPI(nil, nil, WithSRVDiscovery(service))
+	require.NoError(t, err)
+	adminClient.srv.lookup = func(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+		require.Equal(t, service, name)
+		lookups++
+		return "", srvs, lookupErr
+	}
+
+	ctx := context.Background()
+
+	// Both discovered hosts receive a request sent to all hosts.
+	require.NoError(t, adminClient.RecommissionBroker(ctx, 1))
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits1))
+	require.EqualValues(t, 1, atomic.LoadInt32(&hits2))
+	require.Equal(t, 1, lookups)
+
+	// The hosts are not resolved again until they are due a refresh.
+	require.NoError(t, adminClient.RecommissionBroker(ctx, 1))
+	require.Equal(t, 1, lookups)
+
+	// A changed record replaces the hosts on refresh.
+	adminClient.srv.refresh = 0
+	srvs = []*net.SRV{srvTarget(t, ts2)}
+	require.NoError(t, adminClient.RecommissionBroker(ctx, 1))
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits1))
+	require.EqualValues(t, 3, atomic.LoadInt32(&hits2))
+
+	// A failed resolution keeps the last known hosts.
+	lookupErr = errors.New("no such host")
+	require.NoError(t, adminClient.RecommissionBroker(ctx, 1))
+	require.EqualValues(t, 2, atomic.LoadInt32(&hits1))
+	require.EqualValues(t, 4, atomic.LoadInt32(&hits2))
+}
+
+func TestSRVDiscoveryNoHosts(t *testing.T) {
+	adminClient, err := NewAdminAPI(nil, nil, WithSRVDiscovery("_admin._tcp.redpanda.example.com"))
+	require.NoError(t, err)
+	adminClient.srv.lookup = func(context.Context, string, string, string) (string, []*net.SRV, error) {
+		return "", nil, errors.New("no such host")
+	}
+
+	_, err = adminClient.Brokers(context.Background())
+	require.True(t, errors.Is(err, ErrNoHosts))
+	require.Contains(t, err.Error(), "no such host")
+}
+
+func TestNewAdminAPINoURLs(t *testing.T) {
+	_, err := NewAdminAPI(nil, nil)
+	require.Error(t, err)
+}
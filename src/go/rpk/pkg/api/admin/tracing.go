@@ -0,0 +1,49 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import "context"
+
+// Tracer starts a span around every HTTP request the client issues, e.g. to
+// export them with OpenTelemetry. The client does not depend on any tracing
+// library; an OpenTelemetry adapter starts a child span of the span in ctx,
+// if there is one, and records the RequestInfo attributes and error on it:
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, name string) (context.Context, admin.Span) {
+//		if !trace.SpanContextFromContext(ctx).IsValid() {
+//			return ctx, nopSpan{}
+//		}
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	// StartSpan is called before every attempt of every request, for
+	// every host that a request is sent to. The name is the templated
+	// path of the request, e.g. "/v1/brokers/{id}/decommission". The
+	// returned context is used for the attempt.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// End is called once the attempt finished, with the host, method,
+	// path, status code, attempt number, and error of the attempt.
+	End(info RequestInfo)
+}
+
+type nopTracer struct{}
+
+func (nopTracer) StartSpan(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) End(RequestInfo) {}
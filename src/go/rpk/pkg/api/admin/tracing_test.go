@@ -0,0 +1,93 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSpan struct {
+	t    *testTracer
+	name string
+}
+
+func (s *testSpan) End(info RequestInfo) {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.t.ended = append(s.t.ended, endedSpan{s.name, info})
+}
+
+type endedSpan struct {
+	name string
+	info RequestInfo
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	ended []endedSpan
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	s := &testSpan{t: t, name: name}
+	return ctx, s
+}
+
+func TestWithTracer(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&hits, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}),
+	)
+	defer ts.Close()
+
+	tracer := new(testTracer)
+	adminClient, err := NewAdminAPI(
+		[]string{ts.URL},
+		nil,
+		WithTracer(tracer),
+		WithRetries(1, time.Millisecond, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, err = adminClient.Broker(context.Background(), 7)
+	require.NoError(t, err)
+
+	require.Len(t, tracer.ended, 2)
+	for i, exp := range []struct {
+		status  int
+		attempt int
+		err     bool
+	}{
+		{http.StatusServiceUnavailable, 1, true},
+		{http.StatusOK, 2, false},
+	} {
+		span := tracer.ended[i]
+		require.Equal(t, "/v1/brokers/{id}", span.name)
+		require.Equal(t, ts.URL, span.info.Host)
+		require.Equal(t, http.MethodGet, span.info.Method)
+		require.Equal(t, "/v1/brokers/7", span.info.Path)
+		require.Equal(t, exp.status, span.info.StatusCode)
+		require.Equal(t, exp.attempt, span.info.Attempt)
+		require.Equal(t, exp.err, span.info.Err != nil)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const unixScheme = "unix://"
+
+// addSocket registers the unix socket in u, which must be of the form
+// unix:///path/to/admin.sock, and returns the placeholder http URL that
+// requests to it should use. The URL host is only a key for dialContext to
+// find the socket; the request paths are sent as usual.
+func (a *AdminAPI) addSocket(i int, u string) (string, error) {
+	path := strings.TrimPrefix(u, unixScheme)
+	if path == "" {
+		return "", fmt.Errorf("missing socket path in host %q", u)
+	}
+	host := fmt.Sprintf("unix-socket-%d", i)
+	a.sockets[host+":80"] = path
+	return "http://" + host, nil
+}
+
+// dialContext dials the unix socket registered for addr, if any, and
+// otherwise falls back to dialing network and addr as normal. This allows
+// a single client to mix TCP and unix socket hosts.
+func (a *AdminAPI) dialContext() func(context.Context, string, string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if path, ok := a.sockets[addr]; ok {
+			return dialer.DialContext(ctx, "unix", path)
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package admin
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSocket(t *testing.T) {
+	// Socket paths are length limited, so we avoid the long t.TempDir.
+	dir, err := ioutil.TempDir("", "rpk")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "admin.sock")
+
+	var unixHits, tcpHits int32
+	handler := func(hits *int32) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, usersEndpoint, r.URL.Path)
+			atomic.AddInt32(hits, 1)
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+
+	ln, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	us := &httptest.Server{Listener: ln, Config: &http.Server{Handler: handler(&unixHits)}}
+	us.Start()
+	defer us.Close()
+
+	ts := httptest.NewServer(handler(&tcpHits))
+	defer ts.Close()
+
+	adminClient, err := NewAdminAPI([]string{"unix://" + sock, ts.URL}, nil)
+	require.NoError(t, err)
+	err = adminClient.CreateUser(context.Background(), "Joss", "momorocks", ScramSha256)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&unixHits))
+	require.EqualValues(t, 1, atomic.LoadInt32(&tcpHits))
+
+	_, err = NewAdminAPI([]string{"unix://"}, nil)
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@
 package acl
 
 import (
+	"context"
 	"crypto/tls"
 
 	log "github.com/sirupsen/logrus"
@@ -23,6 +24,7 @@ import (
 const (
 	newUserFlag     = "new-username"
 	newPasswordFlag = "new-password"
+	mechanismFlag   = "mechanism"
 
 	deleteUsernameFlag = "delete-username"
 )
@@ -55,15 +57,16 @@ func NewUserCommand(
 
 // UserAPI encapsulates functions needed for a user API.
 type UserAPI interface {
-	CreateUser(username, password string) error
-	DeleteUser(username string) error
-	ListUsers() ([]string, error)
+	CreateUser(ctx context.Context, username, password string, mechanism admin.ScramMechanism) error
+	DeleteUser(ctx context.Context, username string) error
+	ListUsers(ctx context.Context) ([]string, error)
 }
 
 func NewCreateUserCommand(adminApi func() (UserAPI, error)) *cobra.Command {
 	var (
 		newUser     string
 		newPassword string
+		mechanism   string
 	)
 	command := &cobra.Command{
 		Use:          "create",
@@ -74,7 +77,12 @@ func NewCreateUserCommand(adminApi func() (UserAPI, error)) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			err = adminApi.CreateUser(newUser, newPassword)
+			err = adminApi.CreateUser(
+				context.Background(),
+				newUser,
+				newPassword,
+				admin.ScramMechanism(mechanism),
+			)
 			if err != nil {
 				return err
 			}
@@ -99,6 +107,13 @@ func NewCreateUserCommand(adminApi func() (UserAPI, error)) *cobra.Command {
 		"The new user's password",
 	)
 	command.MarkFlagRequired(newPasswordFlag)
+	command.Flags().StringVar(
+		&mechanism,
+		mechanismFlag,
+		string(admin.ScramSha256),
+		"The SCRAM mechanism of the new user's credentials: "+
+			string(admin.ScramSha256)+" or "+string(admin.ScramSha512),
+	)
 
 	return command
 }
@@ -114,7 +129,7 @@ func NewDeleteUserCommand(adminApi func() (UserAPI, error)) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			err = adminApi.DeleteUser(username)
+			err = adminApi.DeleteUser(context.Background(), username)
 			if err != nil {
 				return err
 			}
@@ -147,7 +162,7 @@ func NewListUsersCommand(adminApi func() (UserAPI, error)) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			usernames, err := adminApi.ListUsers()
+			usernames, err := adminApi.ListUsers(context.Background())
 			if err != nil {
 				return err
 			}
@@ -11,36 +11,41 @@ package acl_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/acl"
 )
 
 type mockUserAPI struct {
-	mockCreateUser func(username, password string) error
+	mockCreateUser func(username, password string, mechanism admin.ScramMechanism) error
 	mockDeleteUser func(username string) error
 	mockListUsers  func() ([]string, error)
 }
 
-func (m *mockUserAPI) CreateUser(username, password string) error {
+func (m *mockUserAPI) CreateUser(
+	_ context.Context, username, password string, mechanism admin.ScramMechanism,
+) error {
 	if m.mockCreateUser != nil {
-		return m.mockCreateUser(username, password)
+		return m.mockCreateUser(username, password, mechanism)
 	}
 	return nil
 }
 
-func (m *mockUserAPI) DeleteUser(username string) error {
+func (m *mockUserAPI) DeleteUser(_ context.Context, username string) error {
 	if m.mockDeleteUser != nil {
 		return m.mockDeleteUser(username)
 	}
 	return nil
 }
 
-func (m *mockUserAPI) ListUsers() ([]string, error) {
+func (m *mockUserAPI) ListUsers(_ context.Context) ([]string, error) {
 	if m.mockListUsers != nil {
 		return m.mockListUsers()
 	}
@@ -91,7 +96,7 @@ func TestACLUserCommands(t *testing.T) {
 		command: acl.NewCreateUserCommand,
 		mockUserAPI: func() (acl.UserAPI, error) {
 			return &mockUserAPI{
-				mockCreateUser: func(_, _ string) error {
+				mockCreateUser: func(_, _ string, _ admin.ScramMechanism) error {
 					return errors.New("user creation request failed")
 				},
 			}, nil
@@ -112,6 +117,25 @@ func TestACLUserCommands(t *testing.T) {
 			"--new-password", "pass",
 		},
 		expectedOut: "Created user 'user'",
+	}, {
+		name:    "create should pass the mechanism",
+		command: acl.NewCreateUserCommand,
+		mockUserAPI: func() (acl.UserAPI, error) {
+			return &mockUserAPI{
+				mockCreateUser: func(_, _ string, mechanism admin.ScramMechanism) error {
+					if mechanism != admin.ScramSha512 {
+						return fmt.Errorf("unexpected mechanism %q", mechanism)
+					}
+					return nil
+				},
+			}, nil
+		},
+		args: []string{
+			"--new-username", "user",
+			"--new-password", "pass",
+			"--mechanism", "SCRAM-SHA-512",
+		},
+		expectedOut: "Created user 'user'",
 	}, {
 		name:    "delete should fail if building the admin API client fails",
 		command: acl.NewDeleteUserCommand,
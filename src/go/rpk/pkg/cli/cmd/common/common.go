@@ -208,7 +208,7 @@ func DeduceAdminApiAddrs(
 ) []string {
 	defaultAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(config.DefaultAdminPort))
 	defaultAddrs := []string{defaultAddr}
-	as := *addresses
+	as := cleanAddrs(*addresses)
 	// Prioritize addresses passed through the flag
 	if len(as) != 0 {
 		log.Debugf("Using Admin API addresses: %s", strings.Join(as, ", "))
@@ -216,10 +216,10 @@ func DeduceAdminApiAddrs(
 	}
 	// If no values were passed directly, look for the env vars.
 	envVar := "REDPANDA_API_ADMIN_ADDRS"
-	envAddrs := os.Getenv(envVar)
-	if envAddrs != "" {
-		log.Debugf("Using %s: %s", envVar, envAddrs)
-		return strings.Split(envAddrs, ",")
+	envAddrs := cleanAddrs(strings.Split(os.Getenv(envVar), ","))
+	if len(envAddrs) != 0 {
+		log.Debugf("Using %s: %s", envVar, strings.Join(envAddrs, ", "))
+		return envAddrs
 	}
 
 	// Otherwise, try to find an existing config file.
@@ -234,20 +234,44 @@ func DeduceAdminApiAddrs(
 	}
 
 	// Check rpk.admin_api.addresses
-	if len(conf.Rpk.AdminApi.Addresses) == 0 {
-		// If empty, return the default address
-		log.Debugf(
-			"Empty rpk.admin_api.addresses. Assuming  %s.",
-			defaultAddr,
-		)
-		return defaultAddrs
+	if as := cleanAddrs(conf.Rpk.AdminApi.Addresses); len(as) != 0 {
+		log.Debugf("Using rpk.admin_api.addresses: %s", strings.Join(as, ", "))
+		return as
+	}
+
+	// Then the node's own admin listeners, which are reachable locally
+	// even if they listen on all interfaces.
+	for _, a := range conf.Redpanda.AdminApi {
+		as = append(as, a.LocalHostPort())
+	}
+	if as = cleanAddrs(as); len(as) != 0 {
+		log.Debugf("Using redpanda.admin: %s", strings.Join(as, ", "))
+		return as
 	}
 
 	log.Debugf(
-		"Using rpk.admin_api.addresses: %s",
-		strings.Join(conf.Rpk.AdminApi.Addresses, ", "),
+		"Empty rpk.admin_api.addresses and redpanda.admin. Assuming %s.",
+		defaultAddr,
+	)
+	return defaultAddrs
+}
+
+// cleanAddrs trims whitespace from each address and drops empty and repeated
+// addresses, so that "a:9644, b:9644," is the same as "a:9644,b:9644".
+func cleanAddrs(addrs []string) []string {
+	var (
+		cleaned []string
+		seen    = make(map[string]bool)
 	)
-	return conf.Rpk.AdminApi.Addresses
+	for _, a := range addrs {
+		a = strings.TrimSpace(a)
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		cleaned = append(cleaned, a)
+	}
+	return cleaned
 }
 
 func CreateProducer(
@@ -242,6 +242,28 @@ func TestDeduceAdminApiAddrs(t *testing.T) {
 				"192.168.67.55:9644",
 				"192.168.67.56:9644",
 			},
+		}, {
+			name:     "it should trim and dedupe the flag values",
+			addrs:    []string{" 192.168.34.12:9644", "192.168.34.13:9644 ", "", "192.168.34.12:9644"},
+			expected: []string{"192.168.34.12:9644", "192.168.34.13:9644"},
+		}, {
+			name: "it should fall back to redpanda.admin",
+			config: func() (*config.Config, error) {
+				conf := config.Default()
+				conf.Redpanda.AdminApi = []config.NamedSocketAddress{{
+					SocketAddress: config.SocketAddress{Address: "0.0.0.0", Port: 9645},
+				}, {
+					SocketAddress: config.SocketAddress{Address: "192.168.76.54", Port: 9644},
+				}}
+				return conf, nil
+			},
+			expected: []string{"127.0.0.1:9645", "192.168.76.54:9644"},
+		}, {
+			name: "it should use the default address without any config",
+			config: func() (*config.Config, error) {
+				return nil, errors.New("no config")
+			},
+			expected: []string{"127.0.0.1:9644"},
 		}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(st *testing.T) {
@@ -28,6 +28,8 @@ func NewRedpandaCommand(
 
 	command.AddCommand(redpanda.NewStartCommand(fs, mgr, launcher))
 	command.AddCommand(redpanda.NewStopCommand(fs, mgr))
+	command.AddCommand(redpanda.NewRestartCommand(fs, mgr, launcher))
+	command.AddCommand(redpanda.NewStatusCommand(fs, mgr))
 	command.AddCommand(redpanda.NewCheckCommand(fs, mgr))
 	command.AddCommand(redpanda.NewTuneCommand(fs, mgr))
 	command.AddCommand(redpanda.NewModeCommand(mgr))
@@ -15,6 +15,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/redpanda/admin/brokers"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/redpanda/admin/cluster"
+	configcmd "github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/redpanda/admin/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/redpanda/admin/partitions"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/redpanda/admin/reassignments"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/redpanda/admin/security"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
 )
 
@@ -51,6 +56,13 @@ func NewCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 		"A comma-separated list of Admin API addresses (<IP>:<port>)."+
 			" You must specify one for each node.",
 	)
+	// --api-urls is the name other commands use for the same addresses.
+	cmd.PersistentFlags().StringSliceVar(
+		&hosts,
+		"api-urls",
+		[]string{},
+		"An alias of --hosts",
+	)
 	hostsClosure := func() []string {
 		return common.DeduceAdminApiAddrs(
 			configClosure,
@@ -76,6 +88,11 @@ func NewCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 
 	cmd.AddCommand(
 		brokers.NewCommand(hostsClosure, tlsClosure),
+		cluster.NewCommand(hostsClosure, tlsClosure),
+		configcmd.NewCommand(fs, hostsClosure, tlsClosure),
+		partitions.NewCommand(hostsClosure, tlsClosure),
+		reassignments.NewCommand(hostsClosure, tlsClosure),
+		security.NewCommand(fs, hostsClosure, tlsClosure),
 	)
 
 	return cmd
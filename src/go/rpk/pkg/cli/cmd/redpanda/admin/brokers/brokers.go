@@ -12,9 +12,14 @@
 package brokers
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
@@ -36,6 +41,8 @@ func NewCommand(
 		newDescribeCommand(closures),
 		newDecommissionBroker(closures),
 		newRecommissionBroker(closures),
+		newMaintenanceCommand(closures),
+		newRestartCommand(closures),
 	)
 	return cmd
 }
@@ -52,28 +59,111 @@ func (c closures) eval() ([]string, *tls.Config, error) {
 }
 
 func newListCommand(closures closures) *cobra.Command {
-	return &cobra.Command{
+	var format string
+	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List the brokers in your cluster.",
-		Args:    cobra.ExactArgs(0),
+		Long: `List the brokers in your cluster.
+
+By default, brokers are printed as a table sorted by node ID. The disk column
+is the free space of each of the broker's data disks, if the broker reports it.
+Use --output json or --output yaml to print every field the brokers report.
+
+If the brokers have racks, a warning is printed to stderr when a partition
+with the cluster's default replication factor could lose every replica in a
+single rack failure.
+`,
+		Args: cobra.ExactArgs(0),
 		Run: func(*cobra.Command, []string) {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+
 			hosts, tls, err := closures.eval()
 			out.MaybeDie(err, "unable to load configuration: %v", err)
 
 			cl, err := admin.NewAdminAPI(hosts, tls)
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
 
-			bs, err := cl.Brokers()
+			bs, err := cl.Brokers(context.Background())
 			out.MaybeDie(err, "unable to request brokers: %v", err)
 
-			tw := out.NewTable("Node ID", "Num Cores")
-			defer tw.Flush()
-			for _, b := range bs {
-				tw.Print(b.NodeID, b.NumCores)
+			if format != out.FormatTable {
+				if bs == nil {
+					bs = []admin.Broker{}
+				}
+				out.MaybeDieErr(out.PrintStructured(format, bs))
+			} else {
+				printBrokersTable(bs)
 			}
+			warnRackDistribution(context.Background(), cl, bs)
 		},
 	}
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	return cmd
+}
+
+// printBrokersTable prints the brokers, which must be sorted by node ID, as
+// a table with the numeric columns right aligned.
+func printBrokersTable(bs []admin.Broker) {
+	rows := [][]string{make([]string, len(admin.BrokerHeader))}
+	for i, h := range admin.BrokerHeader {
+		rows[0][i] = strings.ToUpper(h)
+	}
+	for _, b := range bs {
+		rows = append(rows, b.Row())
+	}
+	for _, col := range []int{0, 1} { // node ID and cores
+		values := make([]interface{}, len(rows))
+		for i, row := range rows {
+			values[i] = row[col]
+		}
+		for i, v := range out.RightAlign(values...) {
+			rows[i][col] = v
+		}
+	}
+
+	// The headers are padded too, so we print them ourselves rather than
+	// through NewTable, which would uppercase them again.
+	tw := out.NewTabWriter()
+	defer tw.Flush()
+	for _, row := range rows {
+		tw.PrintStrings(row...)
+	}
+}
+
+// warnRackDistribution prints a warning to stderr if the brokers' racks can't
+// survive the loss of a rack with the cluster's default replication factor.
+// Nothing is checked if no broker has a rack, or if the replication factor
+// can't be requested.
+func warnRackDistribution(
+	ctx context.Context, cl admin.AdminClient, bs []admin.Broker,
+) {
+	var racks bool
+	for _, b := range bs {
+		racks = racks || b.Rack != ""
+	}
+	if !racks {
+		return
+	}
+	conf, err := cl.ClusterConfig(ctx)
+	if err != nil {
+		return
+	}
+	rf, ok := conf["default_topic_replications"].(float64)
+	if !ok {
+		return
+	}
+	err = admin.BrokerList(bs).ValidateRackDistribution(int(rf))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 }
 
 func newDescribeCommand(closures closures) *cobra.Command {
@@ -99,18 +189,23 @@ brokers.
 			cl, err := admin.NewAdminAPI(hosts, tls)
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
 
-			b, err := cl.Broker(broker)
-			out.MaybeDie(err, "unable to request broker: %v", err)
+			b, err := cl.Broker(context.Background(), broker)
+			out.MaybeDie(err, "unable to %v", err)
 
-			tw := out.NewTable("Node ID", "Num Cores", "Membership Status")
+			// NewTable uppercases the headers in place.
+			tw := out.NewTable(append([]string(nil), admin.BrokerHeader...)...)
 			defer tw.Flush()
-			tw.Print(b.NodeID, b.NumCores, b.MembershipStatus)
+			tw.PrintStrings(b.Row()...)
 		},
 	}
 }
 
 func newDecommissionBroker(closures closures) *cobra.Command {
-	return &cobra.Command{
+	var (
+		wait    bool
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
 		Use:   "decommission [BROKER ID]",
 		Short: "Decommission the given broker.",
 		Long: `Decommission the given broker.
@@ -119,6 +214,11 @@ Decommissioning a broker removes it from the cluster.
 
 A decommission request is sent to every broker in the cluster, only the cluster
 leader handles the request.
+
+By default, this returns once the decommission has started. With --wait, this
+prints the progress of moving replicas off of the broker until the broker
+leaves the cluster, and exits non-zero if --timeout (10m by default) elapses
+first. Pass --timeout 0 to wait without a limit.
 `,
 		Args: cobra.ExactArgs(1),
 		Run: func(_ *cobra.Command, args []string) {
@@ -134,12 +234,61 @@ leader handles the request.
 			cl, err := admin.NewAdminAPI(hosts, tls)
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
 
-			err = cl.DecommissionBroker(broker)
-			out.MaybeDie(err, "unable to decommission broker: %v", err)
+			err = cl.DecommissionBroker(context.Background(), broker)
+			out.MaybeDie(err, "unable to %v", err)
+
+			if !wait {
+				fmt.Printf("Success, broker %d decommission started.\n", broker)
+				return
+			}
+
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			err = waitDecommissioned(ctx, cl, broker)
+			if errors.Is(err, context.DeadlineExceeded) {
+				out.Die("timed out after %v waiting for broker %d to be removed", timeout, broker)
+			}
+			out.MaybeDie(err, "unable to wait for broker %d to be removed: %v", broker, err)
 
 			fmt.Printf("Success, broker %d has been decommissioned!\n", broker)
 		},
 	}
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait for the broker to be removed from the cluster")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "How long to --wait before failing; 0 disables the limit")
+	return cmd
+}
+
+// waitDecommissioned waits for the broker to be removed, printing the
+// decommission progress every poll interval.
+func waitDecommissioned(ctx context.Context, cl *admin.AdminAPI, broker int) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- cl.WaitForBrokerRemoved(ctx, broker, admin.DefaultDecommissionPoll)
+	}()
+
+	ticker := time.NewTicker(admin.DefaultDecommissionPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			s, err := cl.DecommissionStatus(ctx, broker)
+			if err != nil {
+				// Progress is informational only; errors that
+				// matter are returned from the wait itself.
+				continue
+			}
+			fmt.Printf("Broker %d: %d replicas left to move\n", broker, s.ReplicasLeft)
+			for _, p := range s.Partitions {
+				fmt.Printf("  %s/%s/%d: %.1f%% moved\n", p.Namespace, p.Topic, p.Partition, p.Completion())
+			}
+		}
+	}
 }
 
 func newRecommissionBroker(closures closures) *cobra.Command {
@@ -171,8 +320,8 @@ the cluster leader handles the request.
 			cl, err := admin.NewAdminAPI(hosts, tls)
 			out.MaybeDie(err, "unable to initialize admin client: %v", err)
 
-			err = cl.RecommissionBroker(broker)
-			out.MaybeDie(err, "unable to recommission broker: %v", err)
+			err = cl.RecommissionBroker(context.Background(), broker)
+			out.MaybeDie(err, "unable to %v", err)
 
 			fmt.Printf("Success, broker %d has been recommissioned!\n", broker)
 		},
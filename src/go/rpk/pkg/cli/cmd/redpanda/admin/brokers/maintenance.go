@@ -0,0 +1,116 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package brokers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func newMaintenanceCommand(closures closures) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Manage the maintenance mode of brokers.",
+		Long: `Manage the maintenance mode of brokers.
+
+A broker in maintenance mode transfers leadership of all of its partitions to
+other brokers, so that it can be restarted without disrupting clients.
+`,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newMaintenanceEnableCommand(closures),
+		newMaintenanceDisableCommand(closures),
+		newMaintenanceStatusCommand(closures),
+	)
+	return cmd
+}
+
+// maintenanceClient parses the broker ID argument and returns it with an
+// admin client, exiting the process on any failure.
+func maintenanceClient(closures closures, arg string) (int, *admin.AdminAPI) {
+	broker, err := strconv.Atoi(arg)
+	out.MaybeDie(err, "invalid broker %s: %v", arg, err)
+	if broker < 0 {
+		out.Die("invalid negative broker id %v", broker)
+	}
+
+	hosts, tls, err := closures.eval()
+	out.MaybeDie(err, "unable to load configuration: %v", err)
+
+	cl, err := admin.NewAdminAPI(hosts, tls)
+	out.MaybeDie(err, "unable to initialize admin client: %v", err)
+	return broker, cl
+}
+
+func newMaintenanceEnableCommand(closures closures) *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable [BROKER ID]",
+		Short: "Put the given broker into maintenance mode.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			broker, cl := maintenanceClient(closures, args[0])
+			s, err := cl.MaintenanceStatus(context.Background(), broker)
+			out.MaybeDie(err, "unable to request maintenance status: %v", err)
+			if s.Draining {
+				fmt.Printf("Broker %d is already in maintenance mode; check progress with 'maintenance status %d'.\n", broker, broker)
+				return
+			}
+
+			err = cl.EnableMaintenanceMode(context.Background(), broker)
+			out.MaybeDie(err, "unable to enable maintenance mode: %v", err)
+
+			fmt.Printf("Success, broker %d is draining; check progress with 'maintenance status %d'.\n", broker, broker)
+		},
+	}
+}
+
+func newMaintenanceDisableCommand(closures closures) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable [BROKER ID]",
+		Short: "Take the given broker out of maintenance mode.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			broker, cl := maintenanceClient(closures, args[0])
+			err := cl.DisableMaintenanceMode(context.Background(), broker)
+			out.MaybeDie(err, "unable to disable maintenance mode: %v", err)
+
+			fmt.Printf("Success, broker %d is out of maintenance mode.\n", broker)
+		},
+	}
+}
+
+func newMaintenanceStatusCommand(closures closures) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [BROKER ID]",
+		Short: "Print the maintenance mode status of the given broker.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			broker, cl := maintenanceClient(closures, args[0])
+			s, err := cl.MaintenanceStatus(context.Background(), broker)
+			out.MaybeDie(err, "unable to request maintenance status: %v", err)
+
+			if !s.Draining {
+				fmt.Printf("Broker %d is not in maintenance mode.\n", broker)
+				return
+			}
+			tw := out.NewTable(
+				"Node ID", "Finished", "Errors", "Partitions", "Eligible", "Transferring", "Failed",
+			)
+			defer tw.Flush()
+			tw.Print(broker, s.Finished, s.Errors, s.Partitions, s.Eligible, s.Transferring, s.Failed)
+		},
+	}
+}
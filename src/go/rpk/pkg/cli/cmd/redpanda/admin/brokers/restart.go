@@ -0,0 +1,81 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package brokers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func newRestartCommand(closures closures) *cobra.Command {
+	var (
+		noDrain bool
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "restart [BROKER ID]",
+		Short: "Restart the given broker.",
+		Long: `Restart the given broker.
+
+The restart request is sent to the broker itself, so the broker's admin address
+must be one of --hosts. This is meant for rolling restarts: restart one broker,
+and only move on to the next once this returns.
+
+By default, the broker is first put into maintenance mode, and is restarted
+only once its leadership has been transferred to other brokers. This then waits
+for the broker to be active again, and takes it out of maintenance mode. If
+--timeout (10m by default) elapses first, the broker is left in maintenance
+mode. Pass --timeout 0 to wait without a limit.
+
+With --no-drain, the broker is restarted right away and this returns without
+waiting for it to be back.
+
+The restart endpoint this uses isn't served by any released Redpanda version
+yet; brokers without it fail the restart, and are taken out of maintenance
+mode again.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			broker, cl := maintenanceClient(closures, args[0])
+
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			if noDrain {
+				err := cl.RestartBroker(ctx, broker)
+				out.MaybeDie(err, "unable to restart broker %d: %v", broker, err)
+				fmt.Printf("Success, broker %d is restarting.\n", broker)
+				return
+			}
+
+			fmt.Printf("Draining and restarting broker %d...\n", broker)
+			err := cl.DrainAndRestartBroker(ctx, broker, admin.DefaultDecommissionPoll)
+			if errors.Is(err, context.DeadlineExceeded) {
+				out.Die("timed out after %v waiting for broker %d to restart", timeout, broker)
+			}
+			out.MaybeDie(err, "unable to restart broker %d: %v", broker, err)
+
+			fmt.Printf("Success, broker %d has been restarted and is active.\n", broker)
+		},
+	}
+	cmd.Flags().BoolVar(&noDrain, "no-drain", false, "Restart the broker without draining it first, and don't wait for it")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "How long to wait for the restart before failing; 0 disables the limit")
+	return cmd
+}
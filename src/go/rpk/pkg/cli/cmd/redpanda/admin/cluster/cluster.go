@@ -0,0 +1,33 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package cluster contains commands to talk to the Redpanda's admin cluster
+// endpoints.
+package cluster
+
+import (
+	"crypto/tls"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the cluster admin command.
+func NewCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "View the state of the cluster through the admin listener.",
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newHealthCommand(hostsClosure, tlsClosure),
+	)
+	return cmd
+}
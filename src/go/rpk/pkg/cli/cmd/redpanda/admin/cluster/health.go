@@ -0,0 +1,151 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func newHealthCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		format   string
+		watch    bool
+		interval time.Duration
+		timeout  time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Print the health of the cluster.",
+		Long: `Print the health of the cluster.
+
+This prints whether the cluster is healthy, the controller's node ID, the
+nodes that are down, and how many partitions have no leader or are
+under-replicated. The cluster is unhealthy if any node is down or any partition
+has no leader.
+
+This exits non-zero if the cluster is unhealthy or its health can't be
+queried, so it can be used as a readiness gate. With --watch, the health is
+printed again every --interval until the command is interrupted. Use --output
+json or --output yaml to print every field the cluster reports.
+`,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(*cobra.Command, []string) error {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+			if interval <= 0 {
+				out.Die("invalid --interval %v, it must be positive", interval)
+			}
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			query := func() (admin.ClusterHealth, error) {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				return cl.ClusterHealth(ctx)
+			}
+			if !watch {
+				h, err := query()
+				out.MaybeDie(err, "unable to request the cluster health: %v", err)
+				out.MaybeDieErr(printHealth(os.Stdout, format, h))
+				if !h.IsHealthy {
+					return &out.ExitCodeError{Code: 1, Msg: "the cluster is unhealthy"}
+				}
+				return nil
+			}
+
+			out.Watch(format, interval, func() bool {
+				h, err := query()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "unable to request the cluster health: %v\n", err)
+					return false
+				}
+				out.MaybeDieErr(printHealth(os.Stdout, format, h))
+				return false
+			})
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	cmd.Flags().BoolVarP(
+		&watch,
+		"watch",
+		"w",
+		false,
+		"Print the health again every --interval until interrupted",
+	)
+	cmd.Flags().DurationVar(
+		&interval,
+		"interval",
+		2*time.Second,
+		"How often the health is printed with --watch",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum time to wait for the cluster health",
+	)
+	return cmd
+}
+
+func printHealth(w io.Writer, format string, h admin.ClusterHealth) error {
+	if format != out.FormatTable {
+		if h.AllNodes == nil {
+			h.AllNodes = []int{}
+		}
+		if h.NodesDown == nil {
+			h.NodesDown = []int{}
+		}
+		if h.LeaderlessPartitions == nil {
+			h.LeaderlessPartitions = []string{}
+		}
+		bs, err := out.Structured(format, h)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	}
+	controller := fmt.Sprint(h.ControllerID)
+	if h.ControllerID < 0 {
+		controller = "none"
+	}
+	tw := out.NewTabWriterTo(w)
+	defer tw.Flush()
+	tw.Print("HEALTHY", h.IsHealthy)
+	tw.Print("CONTROLLER ID", controller)
+	tw.Print("ALL NODES", h.AllNodes)
+	tw.Print("NODES DOWN", h.NodesDown)
+	tw.Print("LEADERLESS PARTITIONS", h.LeaderlessCount)
+	tw.Print("UNDER-REPLICATED PARTITIONS", h.UnderReplicatedCount)
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package cluster
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestPrintHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		health   admin.ClusterHealth
+		expected string
+	}{
+		{
+			name:   "it should print a healthy cluster as a table",
+			format: out.FormatTable,
+			health: admin.ClusterHealth{
+				IsHealthy:    true,
+				ControllerID: 1,
+				AllNodes:     []int{0, 1, 2},
+			},
+			expected: `HEALTHY                      true
+CONTROLLER ID                1
+ALL NODES                    [0 1 2]
+NODES DOWN                   []
+LEADERLESS PARTITIONS        0
+UNDER-REPLICATED PARTITIONS  0
+`,
+		},
+		{
+			name:   "it should print an unhealthy cluster without a controller as a table",
+			format: out.FormatTable,
+			health: admin.ClusterHealth{
+				ControllerID:         -1,
+				AllNodes:             []int{0, 1, 2},
+				NodesDown:            []int{1, 2},
+				LeaderlessCount:      12,
+				UnderReplicatedCount: 3,
+			},
+			expected: `HEALTHY                      false
+CONTROLLER ID                none
+ALL NODES                    [0 1 2]
+NODES DOWN                   [1 2]
+LEADERLESS PARTITIONS        12
+UNDER-REPLICATED PARTITIONS  3
+`,
+		},
+		{
+			name:   "it should print empty lists as json",
+			format: out.FormatJSON,
+			health: admin.ClusterHealth{IsHealthy: true, ControllerID: 0},
+			expected: `{
+  "is_healthy": true,
+  "controller_id": 0,
+  "all_nodes": [],
+  "nodes_down": [],
+  "leaderless_partitions": [],
+  "leaderless_count": 0,
+  "under_replicated_count": 0
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, printHealth(&b, tt.format, tt.health))
+			require.Equal(t, tt.expected, b.String())
+		})
+	}
+}
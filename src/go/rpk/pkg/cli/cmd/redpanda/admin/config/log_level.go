@@ -0,0 +1,231 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+// allNodes is the --node value that sets the level on every node.
+const allNodes = -1
+
+func newLogLevelCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log-level",
+		Short: "Manage the log levels of Redpanda's loggers at runtime.",
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newLogLevelGetCommand(hostsClosure, tlsClosure),
+		newLogLevelSetCommand(hostsClosure, tlsClosure),
+	)
+	return cmd
+}
+
+func newLogLevelGetCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		node    int
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "get [LOGGER]",
+		Short: "Print the current log level of a Redpanda logger.",
+		Long: `Print the current log level of a Redpanda logger.
+
+By default the level is printed for every node in the cluster. Use --node to
+only print it for one node, which must be reachable through --hosts.
+
+Reading the level needs a Redpanda version whose admin API serves it; older
+versions only allow setting it.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			logger := args[0]
+			out.MaybeDieErr(checkLogLevelNode(node))
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			nodes, err := logLevelNodes(ctx, cl, node)
+			out.MaybeDie(err, "unable to list the brokers: %v", err)
+
+			tw := out.NewTable("Node ID", "Level")
+			var failed bool
+			for _, n := range nodes {
+				level, err := cl.LogLevel(ctx, n, logger)
+				if err != nil {
+					failed = true
+					level = fmt.Sprintf("unable to get the level: %v", err)
+				}
+				tw.Print(n, level)
+			}
+			tw.Flush()
+			if failed {
+				out.Die("unable to get the log level of %s on every node", logger)
+			}
+		},
+	}
+	cmd.Flags().IntVar(
+		&node,
+		"node",
+		allNodes,
+		"The ID of the node to get the level of, defaults to every node",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum time to wait for the nodes to respond",
+	)
+	return cmd
+}
+
+func newLogLevelSetCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		node    int
+		expires time.Duration
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "set [LOGGER] [LEVEL]",
+		Short: "Set the log level of a Redpanda logger.",
+		Long: fmt.Sprintf(`Set the log level of a Redpanda logger.
+
+The level must be one of: %s.
+
+The change isn't persisted: the logger reverts to its configured level after
+--expires, or after the server's default expiry if --expires isn't set, and
+when the node restarts.
+
+By default the level is set on every node in the cluster. Use --node to only
+set it on one node, which must be reachable through --hosts.
+
+    rpk redpanda admin config log-level set raft debug --expires 5m --node 1
+`, strings.Join(admin.LogLevels, ", ")),
+		Args: cobra.ExactArgs(2),
+		Run: func(_ *cobra.Command, args []string) {
+			logger, level := args[0], args[1]
+			expirySeconds, err := checkLogLevelArgs(level, node, expires)
+			out.MaybeDieErr(err)
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			nodes, err := logLevelNodes(ctx, cl, node)
+			out.MaybeDie(err, "unable to list the brokers: %v", err)
+
+			var failed bool
+			for _, n := range nodes {
+				err := cl.SetLogLevel(ctx, n, logger, level, expirySeconds)
+				if err != nil {
+					failed = true
+					fmt.Printf("Unable to set the level of %s on node %d: %v\n", logger, n, err)
+					continue
+				}
+				fmt.Printf("Set the level of %s to %s on node %d.\n", logger, level, n)
+			}
+			if failed {
+				out.Die("unable to set the log level on every node")
+			}
+		},
+	}
+	cmd.Flags().IntVar(
+		&node,
+		"node",
+		allNodes,
+		"The ID of the node to set the level on, defaults to every node",
+	)
+	cmd.Flags().DurationVar(
+		&expires,
+		"expires",
+		0,
+		"How long until the logger reverts to its configured level, in whole seconds (e.g. 30s, 5m)",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum time to wait for the nodes to respond",
+	)
+	return cmd
+}
+
+// checkLogLevelArgs validates the level and flags of log-level set before
+// any request is sent, returning the expiry in seconds.
+func checkLogLevelArgs(
+	level string, node int, expires time.Duration,
+) (int, error) {
+	if err := admin.CheckLogLevel(level); err != nil {
+		return 0, err
+	}
+	if err := checkLogLevelNode(node); err != nil {
+		return 0, err
+	}
+	if expires < 0 || expires%time.Second != 0 {
+		return 0, fmt.Errorf(
+			"invalid --expires %v, it must be a positive number of whole seconds",
+			expires,
+		)
+	}
+	return int(expires / time.Second), nil
+}
+
+// checkLogLevelNode validates the --node of the log-level commands.
+func checkLogLevelNode(node int) error {
+	if node < allNodes {
+		return fmt.Errorf("invalid --node %d, it must be a node ID", node)
+	}
+	return nil
+}
+
+// logLevelNodes returns the nodes to set the log level on: the given node,
+// or every broker in the cluster for allNodes.
+func logLevelNodes(
+	ctx context.Context, cl admin.AdminClient, node int,
+) ([]int, error) {
+	if node != allNodes {
+		return []int{node}, nil
+	}
+	brokers, err := cl.Brokers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]int, 0, len(brokers))
+	for _, b := range brokers {
+		nodes = append(nodes, b.NodeID)
+	}
+	sort.Ints(nodes)
+	return nodes, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin/mocks"
+)
+
+func TestCheckLogLevelArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       string
+		node        int
+		expires     time.Duration
+		expected    int
+		expectedErr string
+	}{
+		{
+			name:  "it should accept a known level for every node",
+			level: "debug",
+			node:  allNodes,
+		},
+		{
+			name:     "it should convert --expires to seconds",
+			level:    "trace",
+			node:     1,
+			expires:  5 * time.Minute,
+			expected: 300,
+		},
+		{
+			name:        "it should fail for an unknown level",
+			level:       "verbose",
+			node:        allNodes,
+			expectedErr: "invalid log level 'verbose', it must be one of error, warn, info, debug, trace",
+		},
+		{
+			name:        "it should fail for a negative --node",
+			level:       "info",
+			node:        -2,
+			expectedErr: "invalid --node -2, it must be a node ID",
+		},
+		{
+			name:        "it should fail if --expires isn't whole seconds",
+			level:       "info",
+			node:        allNodes,
+			expires:     1500 * time.Millisecond,
+			expectedErr: "invalid --expires 1.5s, it must be a positive number of whole seconds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seconds, err := checkLogLevelArgs(tt.level, tt.node, tt.expires)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, seconds)
+		})
+	}
+}
+
+func TestLogLevelNodes(t *testing.T) {
+	cl := mocks.MockAdminAPI{
+		MockBrokers: func() ([]admin.Broker, error) {
+			return []admin.Broker{{NodeID: 2}, {NodeID: 0}, {NodeID: 1}}, nil
+		},
+	}
+	nodes, err := logLevelNodes(context.Background(), cl, allNodes)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2}, nodes)
+
+	nodes, err = logLevelNodes(context.Background(), cl, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, nodes)
+
+	cl.MockBrokers = func() ([]admin.Broker, error) {
+		return nil, errors.New("connection refused")
+	}
+	_, err = logLevelNodes(context.Background(), cl, allNodes)
+	require.EqualError(t, err, "connection refused")
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func newSetCommand(
+	fs afero.Fs,
+	hostsClosure func() []string,
+	tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		fromFile string
+		dryRun   bool
+		format   string
+	)
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the cluster configuration properties from a file.",
+		Long: `Set the cluster configuration properties from a file.
+
+The file is a YAML map of cluster configuration properties to their values.
+The properties whose value differs from the cluster's are written in a single
+write, so either all of them are applied or none are. Properties set to null
+are reset to their defaults if they aren't already, and properties that
+aren't in the file are left as they are:
+
+    log_retention_ms: 604800000
+    auto_create_topics_enabled: false
+    cloud_storage_enabled: null
+
+With --dry-run, the changes are printed without writing them. Otherwise, the
+cluster configuration version that contains the changes is printed. The
+command fails, without changing anything, if the file has properties that the
+cluster doesn't know or that it rejects as invalid.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			res, err := cl.SetClusterConfigFromFile(context.Background(), fs, fromFile, dryRun)
+			out.MaybeDie(err, "unable to set the cluster configuration: %v", err)
+			out.MaybeDieErr(printSetResult(os.Stdout, format, res))
+		},
+	}
+	cmd.Flags().StringVar(
+		&fromFile,
+		"from-file",
+		"",
+		"The YAML file with the cluster configuration properties to set",
+	)
+	cmd.MarkFlagRequired("from-file")
+	cmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"Print the changes without writing them",
+	)
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	return cmd
+}
+
+func printSetResult(
+	w io.Writer, format string, res admin.ClusterConfigFileResult,
+) error {
+	if format != out.FormatTable {
+		if res.Changes == nil {
+			res.Changes = []admin.ClusterConfigChange{}
+		}
+		bs, err := out.Structured(format, res)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	}
+	if len(res.Changes) == 0 {
+		fmt.Fprintln(w, "The cluster configuration already matches the file, nothing to change.")
+		return nil
+	}
+	tw := out.NewTabWriterTo(w)
+	tw.PrintStrings("PROPERTY", "CURRENT", "NEW")
+	for _, c := range res.Changes {
+		value := "(default)"
+		if !c.Reset {
+			value = configValue(c.Value)
+		}
+		tw.PrintStrings(c.Property, configValue(c.Current), value)
+	}
+	tw.Flush()
+	if res.DryRun {
+		fmt.Fprintf(w, "\nDry run, %d change(s) were not written.\n", len(res.Changes))
+		return nil
+	}
+	fmt.Fprintf(w, "\nWrote %d change(s) in cluster configuration version %d.\n", len(res.Changes), res.ConfigVersion)
+	return nil
+}
+
+// configValue prints a property's value as it's encoded in JSON, so that
+// lists and maps read as they do in the admin API.
+func configValue(v interface{}) string {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bs)
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestPrintSetResult(t *testing.T) {
+	changes := []admin.ClusterConfigChange{
+		{Property: "cloud_storage_enabled", Current: true, Reset: true},
+		{Property: "superusers", Current: []interface{}{"admin"}, Value: []interface{}{"admin", "ops"}},
+	}
+	tests := []struct {
+		name     string
+		format   string
+		res      admin.ClusterConfigFileResult
+		expected string
+	}{
+		{
+			name:   "it should print the changes of a dry run",
+			format: out.FormatTable,
+			res:    admin.ClusterConfigFileResult{Changes: changes, DryRun: true},
+			expected: `PROPERTY               CURRENT    NEW
+cloud_storage_enabled  true       (default)
+superusers             ["admin"]  ["admin","ops"]
+
+Dry run, 2 change(s) were not written.
+`,
+		},
+		{
+			name:   "it should print the version of the written changes",
+			format: out.FormatTable,
+			res:    admin.ClusterConfigFileResult{Changes: changes[1:], ConfigVersion: 7},
+			expected: `PROPERTY    CURRENT    NEW
+superusers  ["admin"]  ["admin","ops"]
+
+Wrote 1 change(s) in cluster configuration version 7.
+`,
+		},
+		{
+			name:     "it should print that nothing changed",
+			format:   out.FormatTable,
+			res:      admin.ClusterConfigFileResult{},
+			expected: "The cluster configuration already matches the file, nothing to change.\n",
+		},
+		{
+			name:   "it should print the result as JSON",
+			format: out.FormatJSON,
+			res:    admin.ClusterConfigFileResult{ConfigVersion: 7},
+			expected: `{
+  "changes": [],
+  "config_version": 7,
+  "dry_run": false
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, printSetResult(&b, tt.format, tt.res))
+			require.Equal(t, tt.expected, b.String())
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package partitions
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func newBalanceCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		format   string
+		wait     bool
+		interval time.Duration
+		timeout  time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: "Trigger a one-shot run of the partition balancer.",
+		Long: `Trigger a one-shot run of the partition balancer.
+
+The balancer moves partition replicas and leaders to even them out across the
+brokers, and moves replicas off of brokers that are down or low on disk. This
+triggers a single run even if the continuous balancer is disabled, such as to
+rebalance the cluster after maintenance.
+
+The balancer runs in the background. With --wait, this polls the balancer
+every --interval until the triggered run has started and is done, prints its
+status, and exits non-zero if it stalled, i.e. it found violations that it
+can't fix. Use balancer-status to check on a run without waiting.
+`,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(*cobra.Command, []string) error {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+			if interval <= 0 {
+				out.Die("invalid --interval %v, it must be positive", interval)
+			}
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			var before admin.BalancerStatus
+			if wait {
+				before, err = cl.PartitionBalancerStatus(ctx)
+				out.MaybeDie(err, "unable to request the partition balancer status: %v", err)
+			}
+			err = cl.TriggerPartitionBalancer(ctx)
+			out.MaybeDie(err, "unable to trigger the partition balancer: %v", err)
+			if !wait {
+				fmt.Println("Triggered the partition balancer.")
+				return nil
+			}
+
+			s, err := waitForBalancer(ctx, cl, before, interval, balancerStartGrace)
+			out.MaybeDie(err, "unable to wait for the partition balancer: %v", err)
+			out.MaybeDieErr(printBalancerStatus(os.Stdout, format, s))
+			if s.Status == admin.BalancerStatusStalled {
+				return &out.ExitCodeError{Code: 1, Msg: "the partition balancer is stalled"}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format of the status printed with --wait: table, json, or yaml",
+	)
+	cmd.Flags().BoolVarP(
+		&wait,
+		"wait",
+		"w",
+		false,
+		"Wait for the balancer to finish and print its status",
+	)
+	cmd.Flags().DurationVar(
+		&interval,
+		"interval",
+		2*time.Second,
+		"How often the balancer is polled with --wait",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Minute,
+		"The maximum time to wait for the balancer to be triggered, and to finish with --wait",
+	)
+	return cmd
+}
+
+// balancerStartGrace is how long the balancer is given to pick up a
+// triggered run before it's assumed to have finished it without any visible
+// change in its status.
+const balancerStartGrace = 10 * time.Second
+
+// waitForBalancer polls the status of the partition balancer every interval
+// until the run triggered after the status was before has started and is no
+// longer in progress. The run has started once the balancer is in progress or
+// has ticked since before, or its status changed; if none of that happens
+// within grace, the run is assumed to have come and gone between polls.
+func waitForBalancer(
+	ctx context.Context,
+	cl admin.AdminClient,
+	before admin.BalancerStatus,
+	interval time.Duration,
+	grace time.Duration,
+) (admin.BalancerStatus, error) {
+	started := false
+	deadline := time.Now().Add(grace)
+	for {
+		s, err := cl.PartitionBalancerStatus(ctx)
+		if err != nil {
+			return s, err
+		}
+		if !started {
+			started = s.InProgress() ||
+				s.Status != before.Status ||
+				s.SecondsSinceLastTick < before.SecondsSinceLastTick ||
+				!time.Now().Before(deadline)
+		}
+		if started && !s.InProgress() {
+			return s, nil
+		}
+		select {
+		case <-ctx.Done():
+			return s, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func newBalancerStatusCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		format  string
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "balancer-status",
+		Short: "Print the status of the partition balancer.",
+		Long: `Print the status of the partition balancer.
+
+The status is one of:
+
+    off          the continuous balancer is disabled and no run was triggered
+    ready        the balancer is idle, there's nothing to move
+    starting     the balancer is planning which partitions to move
+    in_progress  the balancer is moving partitions
+    stalled      the balancer found violations that it can't fix
+
+This also prints the brokers that violate the balancer's constraints, i.e.
+that are down or over the disk limit, and the number of partition movements
+that the balancer planned and that are not yet done.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			s, err := cl.PartitionBalancerStatus(ctx)
+			out.MaybeDie(err, "unable to request the partition balancer status: %v", err)
+			out.MaybeDieErr(printBalancerStatus(os.Stdout, format, s))
+		},
+	}
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum time to wait for the status",
+	)
+	return cmd
+}
+
+func printBalancerStatus(w io.Writer, format string, s admin.BalancerStatus) error {
+	if s.Violations.UnavailableNodes == nil {
+		s.Violations.UnavailableNodes = []int{}
+	}
+	if s.Violations.OverDiskLimitNodes == nil {
+		s.Violations.OverDiskLimitNodes = []int{}
+	}
+	if format != out.FormatTable {
+		bs, err := out.Structured(format, s)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	}
+	tw := out.NewTabWriterTo(w)
+	defer tw.Flush()
+	tw.Print("STATUS", s.Status)
+	tw.Print("SECONDS SINCE LAST TICK", s.SecondsSinceLastTick)
+	tw.Print("UNAVAILABLE NODES", s.Violations.UnavailableNodes)
+	tw.Print("OVER DISK LIMIT NODES", s.Violations.OverDiskLimitNodes)
+	tw.Print("CURRENT REASSIGNMENTS", s.CurrentReassignments)
+	return nil
+}
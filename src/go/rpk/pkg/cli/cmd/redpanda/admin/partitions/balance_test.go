@@ -0,0 +1,163 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package partitions
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin/mocks"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestWaitForBalancer(t *testing.T) {
+	ready := admin.BalancerStatus{Status: admin.BalancerStatusReady, SecondsSinceLastTick: 30}
+	tests := []struct {
+		name     string
+		before   admin.BalancerStatus
+		statuses []admin.BalancerStatus
+		grace    time.Duration
+		expected admin.BalancerStatus
+		polls    int
+	}{
+		{
+			name:   "it should wait for the run to finish",
+			before: ready,
+			statuses: []admin.BalancerStatus{
+				{Status: admin.BalancerStatusStarting},
+				{Status: admin.BalancerStatusInProgress, CurrentReassignments: 2},
+				{Status: admin.BalancerStatusReady},
+			},
+			grace:    time.Minute,
+			expected: admin.BalancerStatus{Status: admin.BalancerStatusReady},
+			polls:    3,
+		},
+		{
+			name:   "it should wait for the run to start",
+			before: ready,
+			statuses: []admin.BalancerStatus{
+				ready,
+				ready,
+				{Status: admin.BalancerStatusInProgress, CurrentReassignments: 2},
+				{Status: admin.BalancerStatusReady, SecondsSinceLastTick: 1},
+			},
+			grace:    time.Minute,
+			expected: admin.BalancerStatus{Status: admin.BalancerStatusReady, SecondsSinceLastTick: 1},
+			polls:    4,
+		},
+		{
+			name:   "it should finish once the balancer ticked without anything to move",
+			before: ready,
+			statuses: []admin.BalancerStatus{
+				ready,
+				{Status: admin.BalancerStatusReady, SecondsSinceLastTick: 0},
+			},
+			grace:    time.Minute,
+			expected: admin.BalancerStatus{Status: admin.BalancerStatusReady, SecondsSinceLastTick: 0},
+			polls:    2,
+		},
+		{
+			name:   "it should finish once the status changed",
+			before: ready,
+			statuses: []admin.BalancerStatus{
+				{Status: admin.BalancerStatusStalled, SecondsSinceLastTick: 30},
+			},
+			grace:    time.Minute,
+			expected: admin.BalancerStatus{Status: admin.BalancerStatusStalled, SecondsSinceLastTick: 30},
+			polls:    1,
+		},
+		{
+			name:     "it should assume the run is done after the grace period",
+			before:   ready,
+			statuses: []admin.BalancerStatus{ready, ready, ready},
+			expected: ready,
+			polls:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			cl := mocks.MockAdminAPI{
+				MockPartitionBalancerStatus: func() (admin.BalancerStatus, error) {
+					s := tt.statuses[polls]
+					polls++
+					return s, nil
+				},
+			}
+			s, err := waitForBalancer(context.Background(), cl, tt.before, time.Millisecond, tt.grace)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, s)
+			require.Equal(t, tt.polls, polls)
+		})
+	}
+}
+
+func TestWaitForBalancerTimeout(t *testing.T) {
+	cl := mocks.MockAdminAPI{
+		MockPartitionBalancerStatus: func() (admin.BalancerStatus, error) {
+			return admin.BalancerStatus{Status: admin.BalancerStatusInProgress}, nil
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := waitForBalancer(ctx, cl, admin.BalancerStatus{}, time.Millisecond, time.Minute)
+	require.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestPrintBalancerStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		status   admin.BalancerStatus
+		expected string
+	}{
+		{
+			name:   "it should print the status as a table",
+			format: out.FormatTable,
+			status: admin.BalancerStatus{
+				Status:               admin.BalancerStatusStalled,
+				Violations:           admin.BalancerViolations{UnavailableNodes: []int{2}},
+				SecondsSinceLastTick: 5,
+			},
+			expected: `STATUS                   stalled
+SECONDS SINCE LAST TICK  5
+UNAVAILABLE NODES        [2]
+OVER DISK LIMIT NODES    []
+CURRENT REASSIGNMENTS    0
+`,
+		},
+		{
+			name:   "it should print empty violations as json lists",
+			format: out.FormatJSON,
+			status: admin.BalancerStatus{Status: admin.BalancerStatusReady},
+			expected: `{
+  "status": "ready",
+  "violations": {
+    "unavailable_nodes": [],
+    "over_disk_limit_nodes": []
+  },
+  "seconds_since_last_tick": 0,
+  "current_reassignments_count": 0
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, printBalancerStatus(&buf, tt.format, tt.status))
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
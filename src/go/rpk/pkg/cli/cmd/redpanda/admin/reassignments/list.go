@@ -0,0 +1,191 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package reassignments
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func newListCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		format   string
+		watch    bool
+		interval time.Duration
+		timeout  time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the partition reassignments in progress.",
+		Long: `List the partition reassignments in progress.
+
+This prints every partition that is being moved, the replicas it's moving from
+and to, and how much of its data has been moved to each new replica.
+
+With --watch, the reassignments are printed again every --interval until none
+are left in progress, at which point the command exits. This can be used to
+wait for reassignments to finish before starting maintenance. Use --output
+json or --output yaml to print every field the cluster reports.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+			if interval <= 0 {
+				out.Die("invalid --interval %v, it must be positive", interval)
+			}
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			query := func() ([]admin.Reconfiguration, error) {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				return cl.Reconfigurations(ctx)
+			}
+			if !watch {
+				rs, err := query()
+				out.MaybeDie(err, "unable to request the reassignments: %v", err)
+				out.MaybeDieErr(printReconfigurations(os.Stdout, format, rs))
+				return
+			}
+
+			out.Watch(format, interval, func() bool {
+				rs, err := query()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "unable to request the reassignments: %v\n", err)
+					return false
+				}
+				out.MaybeDieErr(printReconfigurations(os.Stdout, format, rs))
+				return len(rs) == 0
+			})
+		},
+	}
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	cmd.Flags().BoolVarP(
+		&watch,
+		"watch",
+		"w",
+		false,
+		"Print the reassignments again every --interval until none are in progress",
+	)
+	cmd.Flags().DurationVar(
+		&interval,
+		"interval",
+		2*time.Second,
+		"How often the reassignments are printed with --watch",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum time to wait for the reassignments",
+	)
+	return cmd
+}
+
+func printReconfigurations(
+	w io.Writer, format string, rs []admin.Reconfiguration,
+) error {
+	if format != out.FormatTable {
+		if rs == nil {
+			rs = []admin.Reconfiguration{}
+		}
+		bs, err := out.Structured(format, rs)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	}
+	if len(rs) == 0 {
+		_, err := fmt.Fprintln(w, "No partition reassignments are in progress.")
+		return err
+	}
+	tw := out.NewTabWriterTo(w)
+	defer tw.Flush()
+	tw.Print("NAMESPACE", "TOPIC", "PARTITION", "FROM", "TO", "MOVED", "REPLICA PROGRESS")
+	for _, r := range rs {
+		moved, total := r.Progress()
+		tw.Print(
+			r.Namespace,
+			r.Topic,
+			r.PartitionID,
+			replicaNodes(r.PreviousReplicas),
+			replicaNodes(r.TargetReplicas),
+			progressString(moved, total),
+			replicaProgressString(r.ReplicaProgress),
+		)
+	}
+	return nil
+}
+
+func replicaNodes(rs []admin.Replica) []int {
+	nodes := make([]int, 0, len(rs))
+	for _, r := range rs {
+		nodes = append(nodes, r.NodeID)
+	}
+	return nodes
+}
+
+// progressString formats the bytes moved out of the total, such as
+// "1MiB/4MiB (25%)".
+func progressString(moved, total int64) string {
+	return fmt.Sprintf(
+		"%s/%s (%s)",
+		units.BytesSize(float64(moved)),
+		units.BytesSize(float64(total)),
+		percent(moved, total),
+	)
+}
+
+// replicaProgressString formats the percentage moved to each replica, such
+// as "1=100% 2=25%".
+func replicaProgressString(ps []admin.ReplicaProgress) string {
+	if len(ps) == 0 {
+		return "-"
+	}
+	progress := make([]string, 0, len(ps))
+	for _, p := range ps {
+		progress = append(progress, fmt.Sprintf("%d=%s", p.NodeID, percent(p.BytesMoved, p.BytesTotal)))
+	}
+	return strings.Join(progress, " ")
+}
+
+// percent returns moved as a percentage of total, which is 100% if there is
+// nothing to move.
+func percent(moved, total int64) string {
+	if total <= 0 {
+		return "100%"
+	}
+	return fmt.Sprintf("%d%%", moved*100/total)
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package reassignments
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestPrintReconfigurations(t *testing.T) {
+	rs := []admin.Reconfiguration{{
+		Namespace:        "kafka",
+		Topic:            "foo",
+		PartitionID:      1,
+		PreviousReplicas: []admin.Replica{{NodeID: 0}},
+		TargetReplicas:   []admin.Replica{{NodeID: 1}, {NodeID: 2}},
+		ReplicaProgress: []admin.ReplicaProgress{
+			{NodeID: 1, BytesMoved: 4 << 20, BytesTotal: 4 << 20},
+			{NodeID: 2, BytesMoved: 1 << 20, BytesTotal: 4 << 20},
+		},
+	}}
+	tests := []struct {
+		name     string
+		format   string
+		rs       []admin.Reconfiguration
+		expected string
+	}{
+		{
+			name:   "it should print the progress of each reassignment",
+			format: out.FormatTable,
+			rs:     rs,
+			expected: `NAMESPACE  TOPIC  PARTITION  FROM  TO     MOVED            REPLICA PROGRESS
+kafka      foo    1          [0]   [1 2]  5MiB/8MiB (62%)  1=100% 2=25%
+`,
+		},
+		{
+			name:     "it should say when no reassignments are in progress",
+			format:   out.FormatTable,
+			expected: "No partition reassignments are in progress.\n",
+		},
+		{
+			name:     "it should print no reassignments as an empty json list",
+			format:   out.FormatJSON,
+			expected: "[]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, printReconfigurations(&buf, tt.format, tt.rs))
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	require.Equal(t, "25%", percent(1, 4))
+	require.Equal(t, "100%", percent(0, 0), "nothing to move should be done")
+}
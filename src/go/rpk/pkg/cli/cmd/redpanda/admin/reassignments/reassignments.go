@@ -0,0 +1,34 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package reassignments contains commands to control partition reassignments
+// through the Redpanda admin listener.
+package reassignments
+
+import (
+	"crypto/tls"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the reassignments admin command.
+func NewCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reassignments",
+		Short: "Control partition reassignments.",
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newListCommand(hostsClosure, tlsClosure),
+		newThrottleCommand(hostsClosure, tlsClosure),
+	)
+	return cmd
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package reassignments
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func newThrottleCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "throttle",
+		Short: "Limit the bandwidth that partition reassignments use.",
+		Long: `Limit the bandwidth that partition reassignments use.
+
+When a partition is moved to a new node, the node recovers the partition's
+data from the leader. The throttle limits the bytes per second that each node
+spends on this recovery, so that reassignments don't starve produce and
+consume traffic. A throttle of 0 means that recovery is unlimited.
+`,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newThrottleSetCommand(hostsClosure, tlsClosure),
+		newThrottleGetCommand(hostsClosure, tlsClosure),
+	)
+	return cmd
+}
+
+func newThrottleSetCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "set [BYTES PER SECOND]",
+		Short: "Set the bandwidth limit of partition reassignments.",
+		Long: `Set the bandwidth limit of partition reassignments.
+
+The limit is in bytes per second, and may have a unit suffix such as 50MiB or
+1G, which are powers of 1024. Use 0 to remove the limit, which lets recovery
+use as much bandwidth as it can.
+
+The limit applies to each node and takes effect without a restart.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			rate, err := parseThrottle(args[0])
+			out.MaybeDieErr(err)
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			err = cl.SetRecoveryThrottle(ctx, rate)
+			out.MaybeDie(err, "unable to set the reassignment throttle: %v", err)
+
+			fmt.Printf("Set the reassignment throttle to %s.\n", throttleString(rate))
+		},
+	}
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum time to wait for the throttle to be set",
+	)
+	return cmd
+}
+
+func newThrottleGetCommand(
+	hostsClosure func() []string, tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	var (
+		format  string
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Print the bandwidth limit of partition reassignments.",
+		Long: `Print the bandwidth limit of partition reassignments.
+
+This prints the limit in bytes per second, or unlimited if there's none. With
+--output json or --output yaml, an unlimited throttle is printed as 0.
+`,
+		Args: cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+
+			tls, err := tlsClosure()
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			cl, err := admin.NewAdminAPI(hostsClosure(), tls)
+			out.MaybeDie(err, "unable to initialize admin client: %v", err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			rate, err := cl.GetRecoveryThrottle(ctx)
+			out.MaybeDie(err, "unable to request the reassignment throttle: %v", err)
+
+			out.MaybeDieErr(printThrottle(os.Stdout, format, rate))
+		},
+	}
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum time to wait for the throttle",
+	)
+	return cmd
+}
+
+// parseThrottle parses a rate in bytes per second, such as 52428800 or 50MiB.
+func parseThrottle(s string) (int, error) {
+	rate, err := units.RAMInBytes(s)
+	if err != nil || rate < 0 {
+		return 0, fmt.Errorf(
+			"invalid throttle '%s', it must be a number of bytes per second such as 50MiB, or 0 for unlimited",
+			s,
+		)
+	}
+	return int(rate), nil
+}
+
+func throttleString(rate int) string {
+	if rate == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d bytes/s (%s/s)", rate, units.BytesSize(float64(rate)))
+}
+
+func printThrottle(w io.Writer, format string, rate int) error {
+	if format != out.FormatTable {
+		bs, err := out.Structured(format, struct {
+			BytesPerSecond int  `json:"bytes_per_second" yaml:"bytes_per_second"`
+			Unlimited      bool `json:"unlimited" yaml:"unlimited"`
+		}{rate, rate == 0})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	}
+	_, err := fmt.Fprintln(w, throttleString(rate))
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package reassignments
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestParseThrottle(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    int
+		expectedErr bool
+	}{
+		{
+			name:     "it should parse a number of bytes",
+			value:    "52428800",
+			expected: 52428800,
+		},
+		{
+			name:     "it should parse a unit suffix",
+			value:    "50MiB",
+			expected: 50 * 1024 * 1024,
+		},
+		{
+			name:     "it should parse 0 as unlimited",
+			value:    "0",
+			expected: 0,
+		},
+		{
+			name:        "it should fail for a negative rate",
+			value:       "-1",
+			expectedErr: true,
+		},
+		{
+			name:        "it should fail for a rate that isn't a size",
+			value:       "fast",
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := parseThrottle(tt.value)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, rate)
+		})
+	}
+}
+
+func TestPrintThrottle(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		rate     int
+		expected string
+	}{
+		{
+			name:     "it should print the rate with its unit",
+			format:   out.FormatTable,
+			rate:     52428800,
+			expected: "52428800 bytes/s (50MiB/s)\n",
+		},
+		{
+			name:     "it should print 0 as unlimited",
+			format:   out.FormatTable,
+			rate:     0,
+			expected: "unlimited\n",
+		},
+		{
+			name:   "it should print the rate as json",
+			format: out.FormatJSON,
+			rate:   0,
+			expected: `{
+  "bytes_per_second": 0,
+  "unlimited": true
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, printThrottle(&buf, tt.format, tt.rate))
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package security contains commands to manage the SASL users of Redpanda
+// through the admin listener.
+package security
+
+import (
+	"crypto/tls"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// NewCommand returns the security admin command.
+func NewCommand(
+	fs afero.Fs,
+	hostsClosure func() []string,
+	tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "security",
+		Short: "Manage Redpanda's security through the admin listener.",
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.AddCommand(
+		newUserCommand(fs, hostsClosure, tlsClosure),
+	)
+	return cmd
+}
@@ -0,0 +1,257 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package security
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+type closures struct {
+	hosts func() []string
+	tls   func() (*tls.Config, error)
+}
+
+func (c closures) client() (*admin.AdminAPI, error) {
+	tls, err := c.tls()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load configuration: %v", err)
+	}
+	cl, err := admin.NewAdminAPI(c.hosts(), tls)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize admin client: %v", err)
+	}
+	return cl, nil
+}
+
+func newUserCommand(
+	fs afero.Fs,
+	hostsClosure func() []string,
+	tlsClosure func() (*tls.Config, error),
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage the SASL/SCRAM users of the cluster.",
+		Args:  cobra.ExactArgs(0),
+	}
+	closures := closures{hostsClosure, tlsClosure}
+	cmd.AddCommand(
+		newUserCreateCommand(fs, closures),
+		newUserDeleteCommand(closures),
+		newUserListCommand(closures),
+	)
+	return cmd
+}
+
+func newUserCreateCommand(fs afero.Fs, closures closures) *cobra.Command {
+	var (
+		password     string
+		passwordFile string
+		mechanism    string
+	)
+	cmd := &cobra.Command{
+		Use:   "create [USER]",
+		Short: "Create a SASL/SCRAM user.",
+		Long: `Create a SASL/SCRAM user.
+
+The password is read from --password, from the first line of --password-file,
+or, if neither is set, from an interactive prompt that doesn't echo it. Prefer
+--password-file or the prompt: a password passed with --password can be seen
+by other users of this host and is saved in the shell history.
+
+The user's credentials are created for the --mechanism that clients
+authenticate with, SCRAM-SHA-256 or SCRAM-SHA-512.
+`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			user := args[0]
+			m, err := parseMechanism(mechanism)
+			out.MaybeDieErr(err)
+
+			pass, err := readPassword(fs, password, passwordFile, promptPassword)
+			out.MaybeDie(err, "unable to read the password: %v", err)
+
+			cl, err := closures.client()
+			out.MaybeDieErr(err)
+
+			err = cl.CreateUser(context.Background(), user, pass, m)
+			out.MaybeDie(err, "unable to create user %q: %v", user, err)
+			fmt.Printf("Created user %q with %s.\n", user, m)
+		},
+	}
+	cmd.Flags().StringVar(
+		&password,
+		"password",
+		"",
+		"The user's password",
+	)
+	cmd.Flags().StringVar(
+		&passwordFile,
+		"password-file",
+		"",
+		"A file whose first line is the user's password",
+	)
+	cmd.Flags().StringVar(
+		&mechanism,
+		"mechanism",
+		string(admin.ScramSha256),
+		"The SASL mechanism to create the credentials for: SCRAM-SHA-256 or SCRAM-SHA-512",
+	)
+	return cmd
+}
+
+func newUserDeleteCommand(closures closures) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete [USER]",
+		Short: "Delete a SASL/SCRAM user.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			user := args[0]
+			cl, err := closures.client()
+			out.MaybeDieErr(err)
+
+			err = cl.DeleteUser(context.Background(), user)
+			out.MaybeDie(err, "unable to delete user %q: %v", user, err)
+			fmt.Printf("Deleted user %q.\n", user)
+		},
+	}
+}
+
+func newUserListCommand(closures closures) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the SASL/SCRAM users.",
+		Args:    cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+
+			cl, err := closures.client()
+			out.MaybeDieErr(err)
+
+			users, err := cl.ListUsers(context.Background())
+			out.MaybeDie(err, "unable to list the users: %v", err)
+			out.MaybeDieErr(printUsers(os.Stdout, format, users))
+		},
+	}
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	return cmd
+}
+
+// parseMechanism returns the SCRAM mechanism for the --mechanism flag, which
+// is case insensitive.
+func parseMechanism(mechanism string) (admin.ScramMechanism, error) {
+	for _, m := range []admin.ScramMechanism{admin.ScramSha256, admin.ScramSha512} {
+		if strings.EqualFold(mechanism, string(m)) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf(
+		"unsupported --mechanism '%s', it must be %s or %s",
+		mechanism,
+		admin.ScramSha256,
+		admin.ScramSha512,
+	)
+}
+
+// readPassword returns the password given with --password or the first line
+// of --password-file, and otherwise prompts for it.
+func readPassword(
+	fs afero.Fs, password, passwordFile string, prompt func() (string, error),
+) (string, error) {
+	if password != "" && passwordFile != "" {
+		return "", errors.New("only one of --password and --password-file can be set")
+	}
+	switch {
+	case password != "":
+		return password, nil
+	case passwordFile != "":
+		bs, err := afero.ReadFile(fs, passwordFile)
+		if err != nil {
+			return "", err
+		}
+		pass := strings.TrimRight(strings.SplitN(string(bs), "\n", 2)[0], "\r")
+		if pass == "" {
+			return "", fmt.Errorf("the first line of %s is empty", passwordFile)
+		}
+		return pass, nil
+	}
+	return prompt()
+}
+
+// promptPassword reads the password twice from the terminal without echoing
+// it, failing if stdin isn't a terminal or the passwords don't match.
+func promptPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return "", errors.New("stdin isn't a terminal, use --password or --password-file")
+	}
+	read := func(msg string) (string, error) {
+		fmt.Fprint(os.Stderr, msg)
+		bs, err := terminal.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(bs), err
+	}
+	pass, err := read("Password: ")
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", errors.New("invalid empty password")
+	}
+	confirm, err := read("Confirm password: ")
+	if err != nil {
+		return "", err
+	}
+	if pass != confirm {
+		return "", errors.New("the passwords don't match")
+	}
+	return pass, nil
+}
+
+func printUsers(w io.Writer, format string, users []string) error {
+	if format != out.FormatTable {
+		if users == nil {
+			users = []string{}
+		}
+		bs, err := out.Structured(format, users)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bs)
+		return err
+	}
+	tw := out.NewTabWriterTo(w)
+	defer tw.Flush()
+	tw.PrintStrings("USERNAME")
+	for _, u := range users {
+		tw.PrintStrings(u)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package security
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestParseMechanism(t *testing.T) {
+	m, err := parseMechanism("scram-sha-512")
+	require.NoError(t, err)
+	require.Equal(t, admin.ScramSha512, m)
+
+	m, err = parseMechanism("SCRAM-SHA-256")
+	require.NoError(t, err)
+	require.Equal(t, admin.ScramSha256, m)
+
+	_, err = parseMechanism("PLAIN")
+	require.EqualError(t, err, "unsupported --mechanism 'PLAIN', it must be SCRAM-SHA-256 or SCRAM-SHA-512")
+}
+
+func TestReadPassword(t *testing.T) {
+	prompted := func() (string, error) { return "prompted", nil }
+	tests := []struct {
+		name         string
+		password     string
+		passwordFile string
+		file         string
+		prompt       func() (string, error)
+		expected     string
+		expectedErr  string
+	}{
+		{
+			name:     "it should use --password",
+			password: "momorocks",
+			expected: "momorocks",
+		},
+		{
+			name:         "it should read the first line of --password-file",
+			passwordFile: "/etc/redpanda/password",
+			file:         "momorocks\r\nignored\n",
+			expected:     "momorocks",
+		},
+		{
+			name:     "it should prompt if no password is given",
+			prompt:   prompted,
+			expected: "prompted",
+		},
+		{
+			name:        "it should fail if the prompt fails",
+			prompt:      func() (string, error) { return "", errors.New("the passwords don't match") },
+			expectedErr: "the passwords don't match",
+		},
+		{
+			name:         "it should fail if both --password and --password-file are set",
+			password:     "momorocks",
+			passwordFile: "/etc/redpanda/password",
+			expectedErr:  "only one of --password and --password-file can be set",
+		},
+		{
+			name:         "it should fail if --password-file has an empty first line",
+			passwordFile: "/etc/redpanda/password",
+			file:         "\nmomorocks\n",
+			expectedErr:  "the first line of /etc/redpanda/password is empty",
+		},
+		{
+			name:         "it should fail if --password-file doesn't exist",
+			passwordFile: "/etc/redpanda/password",
+			expectedErr:  "open /etc/redpanda/password: file does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			if tt.file != "" {
+				err := afero.WriteFile(fs, tt.passwordFile, []byte(tt.file), 0600)
+				require.NoError(t, err)
+			}
+			prompt := tt.prompt
+			if prompt == nil {
+				prompt = func() (string, error) {
+					t.Fatal("unexpected prompt")
+					return "", nil
+				}
+			}
+			pass, err := readPassword(fs, tt.password, tt.passwordFile, prompt)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, pass)
+		})
+	}
+}
+
+func TestPrintUsers(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, printUsers(&b, out.FormatTable, []string{"jeff", "lola"}))
+	require.Equal(t, "USERNAME\njeff\nlola\n", b.String())
+
+	b.Reset()
+	require.NoError(t, printUsers(&b, out.FormatJSON, nil))
+	require.Equal(t, "[]\n", b.String())
+}
@@ -10,30 +10,81 @@
 package redpanda
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/ui"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
 )
 
 func NewCheckCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 	var (
-		configFile string
-		timeout    time.Duration
+		configFile    string
+		timeout       time.Duration
+		checkTimeouts []string
+		format        string
+		failOnWarn    bool
+		only          []string
+		skip          []string
 	)
 	command := &cobra.Command{
-		Use:          "check",
-		Short:        "Check if system meets redpanda requirements",
+		Use:   "check [check...]",
+		Short: "Check if system meets redpanda requirements",
+		Long: `Check if system meets redpanda requirements.
+
+All the checks are run, unless some are given by name, as arguments or with
+--only, in which case only those are run. Checks given with --skip aren't
+run. The names are the ones in the structured output:
+
+  ` + strings.Join(tuners.CheckerNames(), "\n  ") + `
+
+A check that doesn't complete within --check-timeout is aborted and reported
+as failed. The timeout of specific checks can be changed by giving their name:
+--check-timeout 1m,ntp=2m bounds every check to 1m, except the ntp one.
+
+By default, the results are printed as a table. Use --output json or
+--output yaml to print them in a structured format, where each check has a
+stable name that scripts can refer to, and required is set for the checks
+that must pass for redpanda to run. Failed checks that a tuner fixes have a
+remediation with the rpk redpanda tune command to run.
+
+The exit code is:
+
+  0  if all the required checks passed (and, with --fail-on-warn, all the
+     other checks too)
+  1  if a required check failed, or the checks couldn't run
+  2  if only checks that aren't required failed, and --fail-on-warn is set`,
 		SilenceUsage: true,
 		RunE: func(ccmd *cobra.Command, args []string) error {
-			return executeCheck(fs, mgr, configFile, timeout)
+			err := out.CheckFormat(format)
+			if err != nil {
+				return err
+			}
+			if format != out.FormatTable {
+				// The checks log their warnings, which would mix
+				// with the structured output in stdout.
+				log.SetOutput(os.Stderr)
+			}
+			include, err := selectChecks(append(args, only...), skip)
+			if err != nil {
+				return err
+			}
+			checkTimeout, err := parseCheckTimeouts(checkTimeouts)
+			if err != nil {
+				return err
+			}
+			return executeCheck(fs, mgr, configFile, timeout, format, failOnWarn, include, checkTimeout)
 		},
 	}
 	command.Flags().StringVar(
@@ -52,30 +103,208 @@ func NewCheckCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 			"fraction and a unit suffix, such as '300ms', '1.5s' or '2h45m'. "+
 			"Valid time units are 'ns', 'us' (or 'µs'), 'ms', 's', 'm', 'h'",
 	)
+	command.Flags().StringSliceVar(
+		&checkTimeouts,
+		"check-timeout",
+		[]string{defaultCheckTimeout.String()},
+		"The maximum time each check can take, and the maximum time of specific checks as <check>=<duration>. 0 disables the timeout",
+	)
+	command.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	command.Flags().BoolVar(
+		&failOnWarn,
+		"fail-on-warn",
+		false,
+		"Exit with 2 if any check that isn't required fails",
+	)
+	command.Flags().StringSliceVar(
+		&only,
+		"only",
+		nil,
+		"Comma-separated list of the checks to run, in addition to the ones given as arguments",
+	)
+	command.Flags().StringSliceVar(
+		&skip,
+		"skip",
+		nil,
+		"Comma-separated list of the checks not to run",
+	)
 	return command
 }
 
+// selectChecks returns whether a checker should run, given the names of the
+// checks to run, or none to run all of them, and the ones to skip.
+func selectChecks(only, skip []string) (func(tuners.CheckerID) bool, error) {
+	toIDs := func(names []string) (map[tuners.CheckerID]bool, error) {
+		ids := map[tuners.CheckerID]bool{}
+		for _, name := range names {
+			id, ok := tuners.CheckerIDByName(name)
+			if !ok {
+				return nil, fmt.Errorf(
+					"invalid check '%s', only %s are supported",
+					name,
+					strings.Join(tuners.CheckerNames(), ", "),
+				)
+			}
+			ids[id] = true
+		}
+		return ids, nil
+	}
+	onlyIDs, err := toIDs(only)
+	if err != nil {
+		return nil, err
+	}
+	skipIDs, err := toIDs(skip)
+	if err != nil {
+		return nil, err
+	}
+	if len(onlyIDs) > 0 {
+		selected := 0
+		for id := range onlyIDs {
+			if !skipIDs[id] {
+				selected++
+			}
+		}
+		if selected == 0 {
+			return nil, errors.New("all the given checks are skipped, there's nothing to check")
+		}
+	}
+	return func(id tuners.CheckerID) bool {
+		if skipIDs[id] {
+			return false
+		}
+		return len(onlyIDs) == 0 || onlyIDs[id]
+	}, nil
+}
+
+// defaultCheckTimeout is how long each check can take by default.
+const defaultCheckTimeout = 30 * time.Second
+
+// parseCheckTimeouts parses the --check-timeout values, which are either a
+// duration for every check, or a check's name and its duration, and returns
+// the timeout of each check.
+func parseCheckTimeouts(
+	values []string,
+) (func(tuners.CheckerID) time.Duration, error) {
+	all := defaultCheckTimeout
+	byID := map[tuners.CheckerID]time.Duration{}
+	for _, v := range values {
+		name, duration := "", v
+		if i := strings.IndexByte(v, '='); i >= 0 {
+			name, duration = v[:i], v[i+1:]
+		}
+		d, err := time.ParseDuration(duration)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf(
+				"invalid --check-timeout '%s', it must be a duration such as 30s,"+
+					" or a check and its duration such as ntp=1m",
+				v,
+			)
+		}
+		if name == "" {
+			all = d
+			continue
+		}
+		id, ok := tuners.CheckerIDByName(name)
+		if !ok {
+			return nil, fmt.Errorf(
+				"invalid check '%s' in --check-timeout, only %s are supported",
+				name,
+				strings.Join(tuners.CheckerNames(), ", "),
+			)
+		}
+		byID[id] = d
+	}
+	return func(id tuners.CheckerID) time.Duration {
+		if d, ok := byID[id]; ok {
+			return d
+		}
+		return all
+	}, nil
+}
+
+// checkResult is a check's result as it's printed in structured formats.
+type checkResult struct {
+	Name     string `json:"name" yaml:"name"`
+	Desc     string `json:"description" yaml:"description"`
+	Severity string `json:"severity" yaml:"severity"`
+	Required bool   `json:"required" yaml:"required"`
+	Current  string `json:"current" yaml:"current"`
+	Expected string `json:"expected" yaml:"expected"`
+	Passed   bool   `json:"passed" yaml:"passed"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+}
+
+func toCheckResults(results []tuners.CheckResult) []checkResult {
+	crs := make([]checkResult, 0, len(results))
+	for _, r := range results {
+		cr := checkResult{
+			Name:     r.CheckerId.String(),
+			Desc:     r.Desc,
+			Severity: strings.ToLower(r.Severity.String()),
+			Required: r.Severity == tuners.Fatal,
+			Current:  r.Current,
+			Expected: r.Required,
+			Passed:   r.IsOk,
+		}
+		if r.Err != nil {
+			cr.Error = r.Err.Error()
+		}
+		if tuner, ok := r.CheckerId.Tuner(); ok && !r.IsOk {
+			cr.Remediation = "rpk redpanda tune " + tuner
+		}
+		crs = append(crs, cr)
+	}
+	return crs
+}
+
 func appendToTable(t *tablewriter.Table, r tuners.CheckResult) {
+	current := r.Current
+	var timeoutErr *tuners.CheckTimeoutError
+	if errors.As(r.Err, &timeoutErr) {
+		current = "(" + timeoutErr.Error() + ")"
+	}
 	t.Append([]string{
 		r.Desc,
 		r.Required,
-		r.Current,
+		current,
 		fmt.Sprint(r.Severity),
 		fmt.Sprint(printResult(r.Severity, r.IsOk)),
 	})
 }
 
 func executeCheck(
-	fs afero.Fs, mgr config.Manager, configFile string, timeout time.Duration,
+	fs afero.Fs,
+	mgr config.Manager,
+	configFile string,
+	timeout time.Duration,
+	format string,
+	failOnWarn bool,
+	include func(tuners.CheckerID) bool,
+	checkTimeout func(tuners.CheckerID) time.Duration,
 ) error {
 	conf, err := mgr.FindOrGenerate(configFile)
 	if err != nil {
 		return err
 	}
-	results, err := tuners.Check(fs, conf, timeout)
+	results, err := tuners.CheckFiltered(fs, conf, timeout, include, checkTimeout)
 	if err != nil {
 		return err
 	}
+	if format != out.FormatTable {
+		err = out.PrintStructured(format, toCheckResults(results))
+		if err != nil {
+			return err
+		}
+		return checkFailures(results, failOnWarn)
+	}
 	table := ui.NewRpkTable(os.Stdout)
 	table.SetHeader([]string{
 		"Condition",
@@ -90,6 +319,55 @@ func executeCheck(
 	}
 	fmt.Printf("\nSystem check results\n")
 	table.Render()
+	if tuners := fixingTuners(results); len(tuners) > 0 {
+		fmt.Printf(
+			"\nTo fix the failed checks, run: rpk redpanda tune %s\n",
+			strings.Join(tuners, " "),
+		)
+	}
+	return checkFailures(results, failOnWarn)
+}
+
+// fixingTuners returns the sorted names of the tuners that fix the failed
+// checks.
+func fixingTuners(results []tuners.CheckResult) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, r := range results {
+		tuner, ok := r.CheckerId.Tuner()
+		if r.IsOk || !ok || seen[tuner] {
+			continue
+		}
+		seen[tuner] = true
+		names = append(names, tuner)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkFailures returns an error if a required check failed, or, if
+// failOnWarn is set, an error with exit code 2 if any other check failed.
+func checkFailures(results []tuners.CheckResult, failOnWarn bool) error {
+	var required, warnings int
+	for _, r := range results {
+		if r.IsOk {
+			continue
+		}
+		if r.Severity == tuners.Fatal {
+			required++
+		} else {
+			warnings++
+		}
+	}
+	switch {
+	case required > 0:
+		return fmt.Errorf("%d required check(s) failed", required)
+	case failOnWarn && warnings > 0:
+		return &out.ExitCodeError{
+			Code: 2,
+			Msg:  fmt.Sprintf("%d check(s) that aren't required failed", warnings),
+		}
+	}
 	return nil
 }
 
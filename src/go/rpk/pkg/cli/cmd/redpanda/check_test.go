@@ -0,0 +1,252 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
+)
+
+func TestCheckResultsJSON(t *testing.T) {
+	results := []tuners.CheckResult{{
+		CheckerId: tuners.ConfigFileChecker,
+		IsOk:      true,
+		Current:   "true",
+		Desc:      "Config file valid",
+		Severity:  tuners.Fatal,
+		Required:  "true",
+	}, {
+		CheckerId: tuners.Swappiness,
+		Err:       errors.New("open /proc/sys/vm/swappiness: no such file or directory"),
+		Desc:      "Swappiness",
+		Severity:  tuners.Warning,
+		Required:  "<= 1",
+	}}
+	bs, err := out.Structured(out.FormatJSON, toCheckResults(results))
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+  {
+    "name": "config_file",
+    "description": "Config file valid",
+    "severity": "fatal",
+    "required": true,
+    "current": "true",
+    "expected": "true",
+    "passed": true
+  },
+  {
+    "name": "swappiness",
+    "description": "Swappiness",
+    "severity": "warning",
+    "required": false,
+    "current": "",
+    "expected": "<= 1",
+    "passed": false,
+    "error": "open /proc/sys/vm/swappiness: no such file or directory",
+    "remediation": "rpk redpanda tune swappiness"
+  }
+]`, string(bs))
+}
+
+func TestFixingTuners(t *testing.T) {
+	results := []tuners.CheckResult{
+		{CheckerId: tuners.DiskIRQsSpreadChecker, IsOk: false},
+		{CheckerId: tuners.DiskIRQsAffinityChecker, IsOk: false},
+		{CheckerId: tuners.Swappiness, IsOk: false},
+		{CheckerId: tuners.ClockSource, IsOk: true},
+		{CheckerId: tuners.FreeMemChecker, IsOk: false},
+	}
+	require.Equal(t, []string{"disk_irq", "swappiness"}, fixingTuners(results))
+}
+
+func TestCheckFailures(t *testing.T) {
+	var (
+		requiredOk     = tuners.CheckResult{IsOk: true, Severity: tuners.Fatal}
+		requiredFailed = tuners.CheckResult{IsOk: false, Severity: tuners.Fatal}
+		warningOk      = tuners.CheckResult{IsOk: true, Severity: tuners.Warning}
+		warningFailed  = tuners.CheckResult{IsOk: false, Severity: tuners.Warning}
+	)
+	tests := []struct {
+		name       string
+		results    []tuners.CheckResult
+		failOnWarn bool
+		expCode    int
+		expErr     string
+	}{
+		{
+			name:    "it should succeed if all the checks pass",
+			results: []tuners.CheckResult{requiredOk, warningOk},
+			expCode: 0,
+		},
+		{
+			name:    "it should succeed if only checks that aren't required fail",
+			results: []tuners.CheckResult{requiredOk, warningFailed},
+			expCode: 0,
+		},
+		{
+			name:    "it should exit with 1 if a required check fails",
+			results: []tuners.CheckResult{requiredFailed, requiredFailed, warningOk},
+			expCode: 1,
+			expErr:  "2 required check(s) failed",
+		},
+		{
+			name:       "it should exit with 1 if a required check fails, even with --fail-on-warn",
+			results:    []tuners.CheckResult{requiredFailed, warningFailed},
+			failOnWarn: true,
+			expCode:    1,
+			expErr:     "1 required check(s) failed",
+		},
+		{
+			name:       "it should exit with 2 if a check that isn't required fails with --fail-on-warn",
+			results:    []tuners.CheckResult{requiredOk, warningFailed},
+			failOnWarn: true,
+			expCode:    2,
+			expErr:     "1 check(s) that aren't required failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFailures(tt.results, tt.failOnWarn)
+			require.Equal(t, tt.expCode, out.ExitCode(err))
+			if tt.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expErr)
+		})
+	}
+}
+
+func TestSelectChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		only     []string
+		skip     []string
+		included []tuners.CheckerID
+		excluded []tuners.CheckerID
+		expErr   bool
+	}{
+		{
+			name:     "it should include all the checks by default",
+			included: []tuners.CheckerID{tuners.Swappiness, tuners.NtpChecker},
+		},
+		{
+			name:     "it should include only the given checks",
+			only:     []string{"swappiness", "disk_irqs_affinity"},
+			included: []tuners.CheckerID{tuners.Swappiness, tuners.DiskIRQsAffinityChecker},
+			excluded: []tuners.CheckerID{tuners.NtpChecker, tuners.DiskIRQsAffinityStaticChecker},
+		},
+		{
+			name:     "it should exclude the skipped checks",
+			skip:     []string{"ntp"},
+			included: []tuners.CheckerID{tuners.Swappiness},
+			excluded: []tuners.CheckerID{tuners.NtpChecker},
+		},
+		{
+			name:     "it should exclude the skipped checks from the given ones",
+			only:     []string{"swappiness", "ntp"},
+			skip:     []string{"ntp"},
+			included: []tuners.CheckerID{tuners.Swappiness},
+			excluded: []tuners.CheckerID{tuners.NtpChecker, tuners.FstrimChecker},
+		},
+		{
+			name:   "it should fail if a check doesn't exist",
+			only:   []string{"swappiness", "nope"},
+			expErr: true,
+		},
+		{
+			name:   "it should fail if a skipped check doesn't exist",
+			skip:   []string{"nope"},
+			expErr: true,
+		},
+		{
+			name:   "it should fail if all the given checks are skipped",
+			only:   []string{"ntp"},
+			skip:   []string{"ntp"},
+			expErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			include, err := selectChecks(tt.only, tt.skip)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, id := range tt.included {
+				require.True(t, include(id), "%s should be included", id)
+			}
+			for _, id := range tt.excluded {
+				require.False(t, include(id), "%s should be excluded", id)
+			}
+		})
+	}
+}
+
+func TestParseCheckTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected map[tuners.CheckerID]time.Duration
+		expErr   bool
+	}{
+		{
+			name:   "it should default to the same timeout for every check",
+			values: nil,
+			expected: map[tuners.CheckerID]time.Duration{
+				tuners.NtpChecker: defaultCheckTimeout,
+				tuners.Swappiness: defaultCheckTimeout,
+			},
+		},
+		{
+			name:   "it should set the timeout of every check and of specific ones",
+			values: []string{"ntp=2m", "10s", "swappiness=0s"},
+			expected: map[tuners.CheckerID]time.Duration{
+				tuners.NtpChecker:    2 * time.Minute,
+				tuners.Swappiness:    0,
+				tuners.FstrimChecker: 10 * time.Second,
+			},
+		},
+		{
+			name:   "it should fail if a duration doesn't parse",
+			values: []string{"ntp=soon"},
+			expErr: true,
+		},
+		{
+			name:   "it should fail if a duration is negative",
+			values: []string{"-1s"},
+			expErr: true,
+		},
+		{
+			name:   "it should fail if a check doesn't exist",
+			values: []string{"nope=1s"},
+			expErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout, err := parseCheckTimeouts(tt.values)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for id, expected := range tt.expected {
+				require.Equal(t, expected, timeout(id), "timeout of %s", id)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
 )
 
 const configFileFlag = "config"
@@ -27,6 +28,13 @@ func NewConfigCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 		Short: "Edit configuration",
 	}
 	root.AddCommand(set(fs, mgr))
+	root.AddCommand(get(mgr))
+	root.AddCommand(diff(fs, mgr))
+	root.AddCommand(exportConfig(fs, mgr))
+	root.AddCommand(importConfig(fs, mgr))
+	root.AddCommand(validate(fs, mgr))
+	root.AddCommand(edit(fs, mgr))
+	root.AddCommand(restore(fs, mgr))
 	root.AddCommand(bootstrap(mgr))
 	root.AddCommand(initNode(mgr))
 
@@ -36,16 +44,27 @@ func NewConfigCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 func set(fs afero.Fs, mgr config.Manager) *cobra.Command {
 	var (
 		format     string
+		valueType  string
 		configPath string
 	)
 	c := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set configuration values, such as the node IDs or the list of seed servers",
-		Args:  cobra.ExactArgs(2),
-		RunE: func(_ *cobra.Command, args []string) error {
+		Long: `Set configuration values, such as the node IDs or the list of seed servers.
+
+Single values are written with the type inferred from the value: true and
+false are bools, numbers are ints or floats, and JSON lists and objects are
+lists and maps. Anything else is a string. Use --type to force a type, e.g.
+--type string to write 1234 as a string. Setting a key to null removes it from
+the config.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			key := args[0]
 			value := args[1]
+			if valueType != "" && cmd.Flags().Changed("format") && format != "single" {
+				return fmt.Errorf("--type can only be used with --format single, not %s", format)
+			}
 			if configPath == "" {
 				configPath, err = config.FindConfigFile(fs)
 				if err != nil {
@@ -56,13 +75,29 @@ func set(fs afero.Fs, mgr config.Manager) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			err = mgr.Set(key, value, format)
+			if valueType != "" {
+				var v interface{}
+				v, err = config.ParseValue(value, valueType)
+				if err != nil {
+					return err
+				}
+				err = mgr.SetValue(key, v)
+			} else {
+				err = mgr.Set(key, value, format)
+			}
 			if err != nil {
 				return err
 			}
 			return mgr.WriteLoaded()
 		},
 	}
+	c.Flags().StringVar(&valueType,
+		"type",
+		"",
+		"The type of a single value. Can be 'string', 'bool', 'int',"+
+			" 'float', 'null' or 'json', for JSON encoded lists and"+
+			" maps. If not set, the type is inferred from the value",
+	)
 	c.Flags().StringVar(&format,
 		"format",
 		"single",
@@ -80,6 +115,112 @@ func set(fs afero.Fs, mgr config.Manager) *cobra.Command {
 	return c
 }
 
+func get(mgr config.Manager) *cobra.Command {
+	var (
+		format     string
+		configPath string
+	)
+	c := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Get a configuration value",
+		Long: `Get a configuration value.
+
+The key is a dotted path into the config, where list elements are indexed by
+their position, e.g. redpanda.kafka_api.0.port. Single values are printed as
+they are and objects and lists are printed as yaml, unless --output is set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "", out.FormatJSON, out.FormatYAML:
+			default:
+				return fmt.Errorf("unsupported output format %q, must be one of %s or %s", format, out.FormatJSON, out.FormatYAML)
+			}
+			_, err := mgr.ReadOrFind(configPath)
+			if err != nil {
+				return err
+			}
+			v, err := mgr.GetValue(args[0])
+			if err != nil {
+				return err
+			}
+			if format == "" {
+				switch v.(type) {
+				case map[string]interface{}, []interface{}:
+					format = out.FormatYAML
+				default:
+					_, err = fmt.Fprintln(cmd.OutOrStdout(), v)
+					return err
+				}
+			}
+			bs, err := out.Structured(format, v)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(bs)
+			return err
+		},
+	}
+	c.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		"",
+		"Output format: json or yaml",
+	)
+	c.Flags().StringVar(
+		&configPath,
+		configFileFlag,
+		"",
+		"Redpanda config file, if not set the file will be searched"+
+			" for in the default location",
+	)
+	return c
+}
+
+func validate(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var configPath string
+	c := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file for mistakes",
+		Long: `Check the config file for mistakes.
+
+The config file is checked with the same checks that run before rpk writes it,
+such as the type of known values, the ranges of ports, and the values that
+other values require. Each problem is printed, and the command exits with an
+error if there are any.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var err error
+			if configPath == "" {
+				configPath, err = config.FindConfigFile(fs)
+				if err != nil {
+					return err
+				}
+			}
+			// Invalid config is reported through the returned error,
+			// which isn't a usage error.
+			cmd.SilenceUsage = true
+			ok, errs := mgr.Validate(configPath)
+			if ok {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is valid.\n", configPath)
+				return nil
+			}
+			for _, err := range errs {
+				fmt.Fprintln(cmd.OutOrStdout(), err)
+			}
+			return fmt.Errorf("%s has %d problem(s)", configPath, len(errs))
+		},
+	}
+	c.Flags().StringVar(
+		&configPath,
+		configFileFlag,
+		"",
+		"Redpanda config file, if not set the file will be searched"+
+			" for in the default location",
+	)
+	return c
+}
+
 func bootstrap(mgr config.Manager) *cobra.Command {
 	var (
 		ips        []string
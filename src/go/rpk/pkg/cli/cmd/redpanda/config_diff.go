@@ -0,0 +1,179 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/common"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func diff(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var (
+		configPath     string
+		hosts          []string
+		adminEnableTLS bool
+		adminCertFile  string
+		adminKeyFile   string
+		adminCAFile    string
+	)
+	c := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the cluster settings of the config file with the running cluster",
+		Long: `Compare the cluster settings of the config file with the running cluster.
+
+Only the settings of the redpanda section that the cluster manages are
+compared; node settings, such as the node ID or the listeners, are ignored.
+Keys whose value in the config file differ from the cluster's are printed
+as a diff, and the command exits with an error if there are any, so that it
+can be used to check for drift.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Drift is reported through the returned error, which
+			// isn't a usage error.
+			cmd.SilenceUsage = true
+			_, err := mgr.ReadOrFind(configPath)
+			if err != nil {
+				return err
+			}
+			local, err := mgr.GetValue("redpanda")
+			if err != nil {
+				return err
+			}
+			localMap, _ := local.(map[string]interface{})
+
+			configClosure := common.FindConfigFile(mgr, &configPath)
+			tlsConfig, err := common.BuildAdminApiTLSConfig(
+				fs,
+				&adminEnableTLS,
+				&adminCertFile,
+				&adminKeyFile,
+				&adminCAFile,
+				configClosure,
+			)()
+			if err != nil {
+				return err
+			}
+			cl, err := admin.NewAdminAPI(
+				common.DeduceAdminApiAddrs(configClosure, &hosts),
+				tlsConfig,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to initialize admin client: %v", err)
+			}
+			cluster, err := cl.ClusterConfig(context.Background())
+			if err != nil {
+				return fmt.Errorf("unable to request the cluster config: %v", err)
+			}
+
+			diffs := diffClusterConfig(localMap, cluster)
+			if len(diffs) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "The cluster settings of the config file match the cluster.")
+				return nil
+			}
+			printConfigDiffs(cmd.OutOrStdout(), diffs)
+			return fmt.Errorf("%d cluster settings of the config file differ from the cluster", len(diffs))
+		},
+	}
+	c.Flags().StringVar(
+		&configPath,
+		configFileFlag,
+		"",
+		"Redpanda config file, if not set the file will be searched"+
+			" for in the default location",
+	)
+	c.Flags().StringSliceVar(
+		&hosts,
+		"hosts",
+		[]string{},
+		"A comma-separated list of Admin API addresses (<IP>:<port>)",
+	)
+	common.AddAdminAPITLSFlags(
+		c,
+		&adminEnableTLS,
+		&adminCertFile,
+		&adminKeyFile,
+		&adminCAFile,
+	)
+	return c
+}
+
+// configDiff is a cluster setting whose value in the config file differs from
+// the cluster's.
+type configDiff struct {
+	key     string
+	local   interface{}
+	cluster interface{}
+}
+
+// diffClusterConfig returns the keys of local that the cluster manages and
+// whose values differ from the cluster's, sorted by key. Keys the cluster
+// doesn't know of are node settings, which are not compared.
+func diffClusterConfig(
+	local, cluster map[string]interface{},
+) []configDiff {
+	var diffs []configDiff
+	for key, lv := range local {
+		cv, ok := cluster[key]
+		if !ok {
+			continue
+		}
+		// The config file is yaml and the cluster config is JSON, so
+		// the values are normalized through JSON to compare them.
+		if !reflect.DeepEqual(normalizeJSON(lv), normalizeJSON(cv)) {
+			diffs = append(diffs, configDiff{key, lv, cv})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].key < diffs[j].key
+	})
+	return diffs
+}
+
+func normalizeJSON(v interface{}) interface{} {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n interface{}
+	if err := json.Unmarshal(bs, &n); err != nil {
+		return v
+	}
+	return n
+}
+
+func printConfigDiffs(w io.Writer, diffs []configDiff) {
+	red := color.New(color.FgRed).SprintfFunc()
+	green := color.New(color.FgGreen).SprintfFunc()
+	fmt.Fprintln(w, red("--- config file"))
+	fmt.Fprintln(w, green("+++ cluster"))
+	for _, d := range diffs {
+		fmt.Fprintln(w, red("-%s: %s", d.key, diffValue(d.local)))
+		fmt.Fprintln(w, green("+%s: %s", d.key, diffValue(d.cluster)))
+	}
+}
+
+func diffValue(v interface{}) string {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bs)
+}
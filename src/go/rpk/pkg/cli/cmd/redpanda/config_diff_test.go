@@ -0,0 +1,97 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func TestDiffCmd(t *testing.T) {
+	tests := []struct {
+		name      string
+		local     map[string]interface{}
+		cluster   string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name: "it should report no drift if the cluster settings match",
+			local: map[string]interface{}{
+				"redpanda.enable_sasl":      true,
+				"redpanda.log_segment_size": 1024,
+				"redpanda.superusers":       []interface{}{"admin"},
+			},
+			cluster:  `{"enable_sasl": true, "log_segment_size": 1024, "superusers": ["admin"], "kafka_qdc_enable": false}`,
+			expected: "The cluster settings of the config file match the cluster.\n",
+		},
+		{
+			name: "it should print the cluster settings that differ",
+			local: map[string]interface{}{
+				"redpanda.enable_sasl":      true,
+				"redpanda.log_segment_size": 1024,
+				"redpanda.superusers":       []interface{}{"admin"},
+			},
+			cluster: `{"enable_sasl": false, "log_segment_size": 1024, "superusers": ["admin", "root"]}`,
+			expected: "--- config file\n" +
+				"+++ cluster\n" +
+				"-enable_sasl: true\n" +
+				"+enable_sasl: false\n" +
+				`-superusers: ["admin"]` + "\n" +
+				`+superusers: ["admin","root"]` + "\n",
+			expectErr: true,
+		},
+		{
+			name:     "it should ignore node settings and settings missing from the file",
+			cluster:  `{"auto_create_topics_enabled": true}`,
+			expected: "The cluster settings of the config file match the cluster.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/v1/cluster_config", r.URL.Path)
+				w.Write([]byte(tt.cluster))
+			}))
+			defer ts.Close()
+
+			fs := afero.NewMemMapFs()
+			mgr := config.NewManager(fs)
+			conf := config.Default()
+			require.NoError(t, mgr.Write(conf))
+			_, err := mgr.Read(conf.ConfigFile)
+			require.NoError(t, err)
+			for k, v := range tt.local {
+				require.NoError(t, mgr.SetValue(k, v))
+			}
+			require.NoError(t, mgr.WriteLoaded())
+
+			c := cmd.NewConfigCommand(fs, config.NewManager(fs))
+			var out bytes.Buffer
+			c.SetOut(&out)
+			c.SetArgs([]string{"diff", "--config", conf.ConfigFile, "--hosts", ts.URL})
+			err = c.Execute()
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Exactly(t, tt.expected, out.String())
+		})
+	}
+}
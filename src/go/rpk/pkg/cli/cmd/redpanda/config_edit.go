@@ -0,0 +1,180 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+const defaultEditor = "vi"
+
+func edit(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var configPath string
+	c := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the config file with $EDITOR",
+		Long: `Edit the config file with $EDITOR.
+
+The config file is opened in $EDITOR, or vi if it isn't set. When the editor
+exits, the config is checked with the same checks that run before rpk writes
+it. If it is invalid, the editor is opened again with the problems at the top
+of the file. Nothing is written if the editor exits with an error or if the
+config is left unchanged. The config file is backed up before it is replaced.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cmd.SilenceUsage = true
+			var err error
+			if configPath == "" {
+				configPath, err = config.FindConfigFile(fs)
+				if err != nil {
+					return err
+				}
+			}
+			original, err := afero.ReadFile(fs, configPath)
+			if err != nil {
+				return err
+			}
+			edited, err := editUntilValid(original, runEditor)
+			if err != nil {
+				return err
+			}
+			if edited == nil {
+				fmt.Fprintln(cmd.OutOrStdout(), "Edit cancelled, no changes made.")
+				return nil
+			}
+			err = writeEdited(fs, mgr, configPath, edited)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s edited.\n", configPath)
+			return nil
+		},
+	}
+	c.Flags().StringVar(
+		&configPath,
+		configFileFlag,
+		"",
+		"Redpanda config file, if not set the file will be searched"+
+			" for in the default location",
+	)
+	return c
+}
+
+// writeEdited loads the edited config through the manager and writes it to
+// path, so that it's checked and the current file is backed up as with any
+// other config write.
+func writeEdited(fs afero.Fs, mgr config.Manager, path string, edited []byte) error {
+	tmp, err := afero.TempFile(fs, "", "redpanda-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer fs.Remove(tmp.Name())
+	_, err = tmp.Write(edited)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	conf, err := mgr.Read(tmp.Name())
+	if err != nil {
+		return err
+	}
+	conf.ConfigFile = path
+	return mgr.Write(conf)
+}
+
+// editUntilValid has the config edited with editFile until it's valid, and
+// returns the valid config, or nil if it was left unchanged.
+func editUntilValid(original []byte, editFile func(string) error) ([]byte, error) {
+	tmp, err := ioutil.TempFile("", "redpanda-*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	err = tmp.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		current = original
+		header  []byte
+	)
+	for {
+		err = ioutil.WriteFile(tmp.Name(), append(header, current...), 0600)
+		if err != nil {
+			return nil, err
+		}
+		err = editFile(tmp.Name())
+		if err != nil {
+			return nil, fmt.Errorf("editor failed, no changes were written: %v", err)
+		}
+		edited, err := ioutil.ReadFile(tmp.Name())
+		if err != nil {
+			return nil, err
+		}
+		edited = bytes.TrimPrefix(edited, header)
+		// Leaving the file unchanged, even after it was reported to
+		// be invalid, cancels the edit.
+		if bytes.Equal(edited, current) || bytes.Equal(edited, original) {
+			return nil, nil
+		}
+		ok, errs := config.CheckRaw(edited)
+		if ok {
+			return edited, nil
+		}
+		current = edited
+		header = editErrorsHeader(errs)
+	}
+}
+
+// editErrorsHeader returns the comment that is put at the top of the config
+// while it is edited, explaining why it is invalid.
+func editErrorsHeader(errs []error) []byte {
+	var b strings.Builder
+	b.WriteString("# Please fix the following problems, or leave the file unchanged to cancel.\n")
+	for _, err := range errs {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+	}
+	b.WriteString("#\n")
+	return []byte(b.String())
+}
+
+// runEditor opens the file with $EDITOR, which may have arguments, such as
+// "code --wait".
+func runEditor(file string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{defaultEditor}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], file)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("%s exited with status %d", editor[0], exitErr.ExitCode())
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func TestEditCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		editor      string
+		expectedOut string
+		expectedErr string
+		expectedID  int
+		expBackup   bool
+	}{
+		{
+			name:        "it should write the edited config",
+			editor:      `sed -i 's/node_id: 0/node_id: 5/' "$1"`,
+			expectedOut: "/etc/redpanda/redpanda.yaml edited.\n",
+			expectedID:  5,
+			expBackup:   true,
+		},
+		{
+			name: "it should reopen the editor until the config is valid",
+			editor: `if grep -q '^# redpanda.node_id must be an integer' "$1"; then
+  sed -i 's/node_id: abc/node_id: 7/' "$1"
+else
+  sed -i 's/node_id: 0/node_id: abc/' "$1"
+fi`,
+			expectedOut: "/etc/redpanda/redpanda.yaml edited.\n",
+			expectedID:  7,
+			expBackup:   true,
+		},
+		{
+			name:        "it should cancel the edit if the config is unchanged",
+			editor:      "true",
+			expectedOut: "Edit cancelled, no changes made.\n",
+		},
+		{
+			name: "it should cancel the edit if an invalid config is left unchanged",
+			editor: `grep -q '^# Please fix' "$1" ||
+  sed -i 's/node_id: 0/node_id: abc/' "$1"`,
+			expectedOut: "Edit cancelled, no changes made.\n",
+		},
+		{
+			name:        "it should not write anything if the editor fails",
+			editor:      `sed -i 's/node_id: 0/node_id: 5/' "$1"; exit 3`,
+			expectedErr: "editor failed, no changes were written: sh exited with status 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "rpk-edit")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			script := filepath.Join(dir, "editor.sh")
+			require.NoError(t, ioutil.WriteFile(script, []byte(tt.editor), 0700))
+			prev := os.Getenv("EDITOR")
+			os.Setenv("EDITOR", "sh "+script)
+			defer os.Setenv("EDITOR", prev)
+
+			fs := afero.NewMemMapFs()
+			mgr := config.NewManager(fs)
+			conf := config.Default()
+			require.NoError(t, mgr.Write(conf))
+
+			c := cmd.NewConfigCommand(fs, mgr)
+			var out bytes.Buffer
+			c.SetOut(&out)
+			c.SetErr(&bytes.Buffer{})
+			c.SetArgs([]string{"edit", "--config", conf.ConfigFile})
+			err = c.Execute()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Exactly(t, tt.expectedOut, out.String())
+			edited, err := config.NewManager(fs).Read(conf.ConfigFile)
+			require.NoError(t, err)
+			require.Exactly(t, tt.expectedID, edited.Redpanda.Id)
+			backups, err := mgr.Backups(conf.ConfigFile)
+			require.NoError(t, err)
+			require.Equal(t, tt.expBackup, len(backups) > 0, "backups: %v", backups)
+		})
+	}
+}
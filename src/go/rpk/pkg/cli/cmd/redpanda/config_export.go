@@ -0,0 +1,181 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/icza/dyno"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	"gopkg.in/yaml.v2"
+)
+
+// importRequiredKeys are the keys a config file must set to be imported. The
+// rest of the config falls back to the defaults.
+var importRequiredKeys = []string{
+	"redpanda.data_directory",
+	"redpanda.node_id",
+	"redpanda.rpc_server",
+}
+
+func exportConfig(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var (
+		format     string
+		configPath string
+	)
+	c := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export the effective configuration",
+		Long: `Export the effective configuration.
+
+The config file is printed with the defaults of every value it doesn't set, or
+it is written to the given file. The format is yaml, unless --output is set or
+the file ends in .json. The exported config can be applied again with
+'rpk redpanda config import'.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" && len(args) == 1 && strings.EqualFold(filepath.Ext(args[0]), ".json") {
+				format = out.FormatJSON
+			}
+			switch format {
+			case "":
+				format = out.FormatYAML
+			case out.FormatJSON, out.FormatYAML:
+			default:
+				return fmt.Errorf("unsupported output format %q, must be one of %s or %s", format, out.FormatJSON, out.FormatYAML)
+			}
+			conf, err := mgr.ReadOrFind(configPath)
+			if err != nil {
+				return err
+			}
+			// The config is converted to a map through yaml so that
+			// the json output has the same keys as the config file.
+			raw, err := yaml.Marshal(conf)
+			if err != nil {
+				return err
+			}
+			var m interface{}
+			if err = yaml.Unmarshal(raw, &m); err != nil {
+				return err
+			}
+			bs, err := out.Structured(format, dyno.ConvertMapI2MapS(m))
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				_, err = cmd.OutOrStdout().Write(bs)
+				return err
+			}
+			return afero.WriteFile(fs, args[0], bs, 0644)
+		},
+	}
+	c.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		"",
+		"Output format: json or yaml",
+	)
+	c.Flags().StringVar(
+		&configPath,
+		configFileFlag,
+		"",
+		"Redpanda config file, if not set the file will be searched"+
+			" for in the default location",
+	)
+	return c
+}
+
+func importConfig(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var (
+		force      bool
+		configPath string
+	)
+	c := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import a configuration, such as one exported with 'rpk redpanda config export'",
+		Long: `Import a configuration, such as one exported with 'rpk redpanda config export'.
+
+The file may be yaml or json, and must set at least the redpanda data
+directory, node ID and RPC server. It is validated and written to --config,
+or to the default config file location. An existing config file is only
+replaced if --force is set.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			raw, err := afero.ReadFile(fs, args[0])
+			if err != nil {
+				return err
+			}
+			// yaml is a superset of json, so this reads both.
+			var m map[string]interface{}
+			if err = yaml.Unmarshal(raw, &m); err != nil {
+				return fmt.Errorf("unable to decode %s: %v", args[0], err)
+			}
+			for _, key := range importRequiredKeys {
+				if !hasKey(m, strings.Split(key, ".")) {
+					return fmt.Errorf("unable to import %s: %s is missing", args[0], key)
+				}
+			}
+			conf := &config.Config{}
+			if err = yaml.Unmarshal(raw, conf); err != nil {
+				return fmt.Errorf("unable to decode %s: %v", args[0], err)
+			}
+
+			if configPath == "" {
+				configPath, err = config.FindConfigFile(fs)
+				if err != nil {
+					configPath = config.Default().ConfigFile
+				}
+			}
+			exists, err := afero.Exists(fs, configPath)
+			if err != nil {
+				return err
+			}
+			if exists && !force {
+				return fmt.Errorf("%s already exists, use --force to replace it", configPath)
+			}
+			conf.ConfigFile = configPath
+			return mgr.Write(conf)
+		},
+	}
+	c.Flags().BoolVar(
+		&force,
+		"force",
+		false,
+		"Replace the config file if it already exists",
+	)
+	c.Flags().StringVar(
+		&configPath,
+		configFileFlag,
+		"",
+		"Redpanda config file to write, if not set the file will be"+
+			" searched for in the default location",
+	)
+	return c
+}
+
+func hasKey(m map[string]interface{}, path []string) bool {
+	v, ok := m[path[0]]
+	if !ok || len(path) == 1 {
+		return ok
+	}
+	switch child := v.(type) {
+	case map[string]interface{}:
+		return hasKey(child, path[1:])
+	case map[interface{}]interface{}:
+		return hasKey(dyno.ConvertMapI2MapS(child).(map[string]interface{}), path[1:])
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func writeExportConfig(t *testing.T, fs afero.Fs) *config.Config {
+	mgr := config.NewManager(fs)
+	conf := config.Default()
+	conf.Redpanda.Id = 3
+	conf.Redpanda.Superusers = []string{"admin"}
+	conf.Redpanda.Other = map[string]interface{}{"custom_setting": "value"}
+	require.NoError(t, mgr.Write(conf))
+	return conf
+}
+
+func readConfig(t *testing.T, fs afero.Fs, path string) *config.Config {
+	conf, err := config.NewManager(fs).Read(path)
+	require.NoError(t, err)
+	return conf
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, file := range []string{"/tmp/export.yaml", "/tmp/export.json"} {
+		t.Run(file, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			conf := writeExportConfig(t, fs)
+
+			c := cmd.NewConfigCommand(fs, config.NewManager(fs))
+			c.SetArgs([]string{"export", file, "--config", conf.ConfigFile})
+			require.NoError(t, c.Execute())
+
+			imported := "/etc/other/redpanda.yaml"
+			c = cmd.NewConfigCommand(fs, config.NewManager(fs))
+			c.SetArgs([]string{"import", file, "--config", imported})
+			require.NoError(t, c.Execute())
+
+			expected := readConfig(t, fs, conf.ConfigFile)
+			expected.ConfigFile = imported
+			require.Equal(t, expected, readConfig(t, fs, imported))
+		})
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	conf := writeExportConfig(t, fs)
+
+	c := cmd.NewConfigCommand(fs, config.NewManager(fs))
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetArgs([]string{"export", "-o", "json", "--config", conf.ConfigFile})
+	require.NoError(t, c.Execute())
+
+	var exported map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	redpanda, ok := exported["redpanda"].(map[string]interface{})
+	require.True(t, ok, "redpanda isn't an object")
+	require.Equal(t, conf.Redpanda.Directory, redpanda["data_directory"])
+	require.Equal(t, float64(3), redpanda["node_id"])
+	require.Equal(t, "value", redpanda["custom_setting"])
+}
+
+func TestImport(t *testing.T) {
+	valid := `
+redpanda:
+  data_directory: /var/lib/redpanda/data
+  node_id: 1
+  rpc_server:
+    address: 0.0.0.0
+    port: 33145
+`
+	tests := []struct {
+		name        string
+		file        string
+		existing    bool
+		args        []string
+		expectedErr string
+	}{
+		{
+			name: "it should import a config",
+			file: valid,
+		},
+		{
+			name:        "it should refuse to replace an existing config",
+			file:        valid,
+			existing:    true,
+			expectedErr: "/etc/redpanda/redpanda.yaml already exists, use --force to replace it",
+		},
+		{
+			name:     "it should replace an existing config with --force",
+			file:     valid,
+			existing: true,
+			args:     []string{"--force"},
+		},
+		{
+			name:        "it should fail if a required key is missing",
+			file:        "redpanda:\n  node_id: 1\n  rpc_server: {address: 0.0.0.0, port: 1}\n",
+			expectedErr: "unable to import /tmp/import.yaml: redpanda.data_directory is missing",
+		},
+		{
+			name:        "it should fail if the config is invalid",
+			file:        "redpanda:\n  data_directory: /data\n  node_id: -1\n  rpc_server: {address: 0.0.0.0, port: 1}\n",
+			expectedErr: "redpanda.node_id can't be a negative integer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			path := config.Default().ConfigFile
+			if tt.existing {
+				writeExportConfig(t, fs)
+			}
+			require.NoError(t, afero.WriteFile(fs, "/tmp/import.yaml", []byte(tt.file), 0644))
+
+			c := cmd.NewConfigCommand(fs, config.NewManager(fs))
+			c.SetArgs(append([]string{"import", "/tmp/import.yaml", "--config", path}, tt.args...))
+			err := c.Execute()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			conf := readConfig(t, fs, path)
+			require.Exactly(t, 1, conf.Redpanda.Id)
+			require.Exactly(t, "/var/lib/redpanda/data", conf.Redpanda.Directory)
+		})
+	}
+}
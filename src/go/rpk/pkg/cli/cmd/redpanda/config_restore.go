@@ -0,0 +1,82 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func restore(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var (
+		list       bool
+		configPath string
+	)
+	c := &cobra.Command{
+		Use:   "restore [backup]",
+		Short: "Restore the config file from a backup",
+		Long: `Restore the config file from a backup.
+
+rpk backs up the config file before replacing it, next to the config file, and
+keeps the last rpk.config_backups backups (5 by default). This restores the
+latest backup, or the given one, which may be the backup's name or path. The
+current config file is backed up before it is restored, so a restore can be
+undone too. Use --list to list the backups, oldest first.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if configPath == "" {
+				configPath, err = config.FindConfigFile(fs)
+				if err != nil {
+					return err
+				}
+			}
+			// Load the config so that rpk.config_backups applies to
+			// the backup of the current file, if it can be read.
+			_, err = mgr.Read(configPath)
+			if err != nil {
+				log.Debugf("Unable to read %s: %v", configPath, err)
+			}
+			if list {
+				backups, err := mgr.Backups(configPath)
+				if err != nil {
+					return err
+				}
+				for _, b := range backups {
+					fmt.Fprintln(cmd.OutOrStdout(), b)
+				}
+				return nil
+			}
+			var backup string
+			if len(args) == 1 {
+				backup = args[0]
+			}
+			restored, err := mgr.Restore(configPath, backup)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Restored %s from %s.\n", configPath, restored)
+			return nil
+		},
+	}
+	c.Flags().BoolVar(&list, "list", false, "List the backups instead of restoring one")
+	c.Flags().StringVar(
+		&configPath,
+		configFileFlag,
+		"",
+		"Redpanda config file, if not set the file will be searched"+
+			" for in the default location",
+	)
+	return c
+}
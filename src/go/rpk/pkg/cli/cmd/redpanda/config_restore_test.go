@@ -0,0 +1,55 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func TestRestoreCmd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	conf := config.Default()
+	require.NoError(t, config.NewManager(fs).Write(conf))
+
+	run := func(args ...string) string {
+		c := cmd.NewConfigCommand(fs, config.NewManager(fs))
+		var out bytes.Buffer
+		c.SetOut(&out)
+		c.SetArgs(append(args, "--config", conf.ConfigFile))
+		require.NoError(t, c.Execute())
+		return out.String()
+	}
+	nodeID := func() int {
+		got, err := config.NewManager(fs).Read(conf.ConfigFile)
+		require.NoError(t, err)
+		return got.Redpanda.Id
+	}
+
+	run("set", "redpanda.node_id", "1")
+	run("set", "redpanda.node_id", "2")
+	backups := strings.Fields(run("restore", "--list"))
+	require.Len(t, backups, 2)
+
+	out := run("restore")
+	require.Exactly(t, fmt.Sprintf("Restored %s from %s.\n", conf.ConfigFile, backups[1]), out)
+	require.Exactly(t, 1, nodeID())
+
+	run("restore", backups[0])
+	require.Exactly(t, 0, nodeID())
+	require.Len(t, strings.Fields(run("restore", "--list")), 4)
+}
@@ -10,6 +10,7 @@
 package redpanda_test
 
 import (
+	"bytes"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -19,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"gopkg.in/yaml.v2"
 )
 
 func TestSetCmd(t *testing.T) {
@@ -54,6 +56,59 @@ func TestSetCmd(t *testing.T) {
 			value:    "true",
 			expected: true,
 		},
+		{
+			name:     "it should infer list fields from JSON",
+			key:      "redpanda.list_field",
+			value:    `["a", 1]`,
+			expected: []interface{}{"a", 1},
+		},
+		{
+			name:     "it should set a value of a forced type",
+			key:      "redpanda.data_directory",
+			value:    "1234",
+			args:     []string{"--type", "string"},
+			expected: "1234",
+		},
+		{
+			name:     "it should set a forced bool",
+			key:      "redpanda.developer_mode",
+			value:    "false",
+			args:     []string{"--type", "bool"},
+			expected: false,
+		},
+		{
+			name:      "it should fail if the value isn't of the forced type",
+			key:       "redpanda.node_id",
+			value:     "one",
+			args:      []string{"--type", "int"},
+			expectErr: true,
+		},
+		{
+			name:      "it should fail if the forced type isn't supported",
+			key:       "redpanda.node_id",
+			value:     "1",
+			args:      []string{"--type", "duration"},
+			expectErr: true,
+		},
+		{
+			name:      "it should fail if the value is invalid for the key",
+			key:       "redpanda.node_id",
+			value:     "abc",
+			expectErr: true,
+		},
+		{
+			name:      "it should fail if a port is out of range",
+			key:       "redpanda.rpc_server.port",
+			value:     "70000",
+			expectErr: true,
+		},
+		{
+			name:      "it should fail if a type is forced with a non-single format",
+			key:       "redpanda.node_id",
+			value:     "1",
+			args:      []string{"--type", "int", "--format", "json"},
+			expectErr: true,
+		},
 		{
 			name:  "it should partially set map fields (yaml)",
 			key:   "rpk",
@@ -170,6 +225,169 @@ func TestSetCmd(t *testing.T) {
 	}
 }
 
+func TestSetCmdWritesNativeTypes(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := config.NewManager(fs)
+	conf := config.Default()
+	require.NoError(t, mgr.Write(conf))
+
+	sets := [][]string{
+		{"redpanda.developer_mode", "true"},
+		{"redpanda.rpc_server.port", "33146"},
+		{"redpanda.float_field", "0.5"},
+		{"redpanda.null_field", "set"},
+		{"redpanda.null_field", "null"},
+		{"redpanda.list_field", `[1, "two"]`},
+		{"redpanda.map_field", `{"a": true}`, "--type", "json"},
+		{"redpanda.string_field", "false", "--type", "string"},
+	}
+	for _, args := range sets {
+		c := cmd.NewConfigCommand(fs, mgr)
+		c.SetArgs(append([]string{"set"}, args...))
+		require.NoError(t, c.Execute())
+	}
+
+	raw, err := afero.ReadFile(fs, conf.ConfigFile)
+	require.NoError(t, err)
+	var written struct {
+		Redpanda map[string]interface{} `yaml:"redpanda"`
+	}
+	require.NoError(t, yaml.Unmarshal(raw, &written))
+
+	require.Exactly(t, true, written.Redpanda["developer_mode"])
+	require.Exactly(t, 0.5, written.Redpanda["float_field"])
+	require.Exactly(t, "false", written.Redpanda["string_field"])
+	require.Exactly(t, []interface{}{1, "two"}, written.Redpanda["list_field"])
+	require.Exactly(
+		t,
+		map[interface{}]interface{}{"a": true},
+		written.Redpanda["map_field"],
+	)
+	_, ok := written.Redpanda["null_field"]
+	require.False(t, ok, "null_field wasn't removed")
+	rpc, ok := written.Redpanda["rpc_server"].(map[interface{}]interface{})
+	require.True(t, ok, "rpc_server isn't a map")
+	require.Exactly(t, 33146, rpc["port"])
+}
+
+func TestGetCmd(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		args      []string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "it should print single values",
+			key:      "redpanda.rpc_server.port",
+			expected: "33145\n",
+		},
+		{
+			name:     "it should print list elements",
+			key:      "redpanda.kafka_api.0.address",
+			expected: "0.0.0.0\n",
+		},
+		{
+			name:     "it should print objects as yaml by default",
+			key:      "redpanda.rpc_server",
+			expected: "address: 0.0.0.0\nport: 33145\n",
+		},
+		{
+			name:     "it should print values as json",
+			key:      "redpanda.kafka_api",
+			args:     []string{"-o", "json"},
+			expected: "[\n  {\n    \"address\": \"0.0.0.0\",\n    \"port\": 9092\n  }\n]\n",
+		},
+		{
+			name:     "it should print values as yaml",
+			key:      "redpanda.developer_mode",
+			args:     []string{"--output", "yaml"},
+			expected: "true\n",
+		},
+		{
+			name:      "it should fail if the key doesn't exist",
+			key:       "redpanda.rpc_server.nope",
+			expectErr: true,
+		},
+		{
+			name:      "it should fail if the list index is out of range",
+			key:       "redpanda.kafka_api.1",
+			expectErr: true,
+		},
+		{
+			name:      "it should fail if the output format isn't supported",
+			key:       "redpanda.node_id",
+			args:      []string{"-o", "table"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			mgr := config.NewManager(fs)
+			conf := config.Default()
+			err := mgr.Write(conf)
+			require.NoError(t, err)
+
+			c := cmd.NewConfigCommand(fs, mgr)
+			var out bytes.Buffer
+			c.SetOut(&out)
+			c.SetArgs(append([]string{"get", tt.key, "--config", conf.ConfigFile}, tt.args...))
+			err = c.Execute()
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Exactly(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestValidateCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		expectedOut string
+		expectedErr string
+	}{
+		{
+			name:        "it should report a valid config",
+			file:        "redpanda:\n  node_id: 1\n",
+			expectedOut: "/etc/redpanda/redpanda.yaml is valid.\n",
+		},
+		{
+			name: "it should print each problem",
+			file: "redpanda:\n  node_id: one\n  enable_rack_awareness: true\n",
+			expectedOut: `redpanda.node_id must be an integer, got "one"` + "\n" +
+				"if redpanda.enable_rack_awareness is set to true, redpanda.rack can't be empty\n",
+			expectedErr: "/etc/redpanda/redpanda.yaml has 2 problem(s)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			path := "/etc/redpanda/redpanda.yaml"
+			require.NoError(t, afero.WriteFile(fs, path, []byte(tt.file), 0644))
+
+			c := cmd.NewConfigCommand(fs, config.NewManager(fs))
+			var out bytes.Buffer
+			c.SetOut(&out)
+			c.SetErr(&bytes.Buffer{})
+			c.SetArgs([]string{"validate", "--config", path})
+			err := c.Execute()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Exactly(t, tt.expectedOut, out.String())
+		})
+	}
+}
+
 func TestBootstrap(t *testing.T) {
 	tests := []struct {
 		name        string
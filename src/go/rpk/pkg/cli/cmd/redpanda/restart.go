@@ -0,0 +1,253 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+)
+
+func NewRestartCommand(
+	fs afero.Fs, mgr config.Manager, launcher rp.Launcher,
+) *cobra.Command {
+	var (
+		configFile     string
+		installDirFlag string
+		timeout        time.Duration
+		drain          bool
+		drainTimeout   time.Duration
+		waitReady      bool
+		readyTimeout   time.Duration
+		logFile        string
+	)
+	command := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart redpanda.",
+		Long: `Restart a local redpanda process.
+
+Redpanda is stopped as with 'rpk redpanda stop', and started again with the
+same arguments and environment it was running with, including the variables
+set with 'rpk redpanda start --env'. Its output isn't redirected to the files
+given to --stdout-file and --stderr-file again. If it isn't running, it's
+started with the arguments that 'rpk redpanda start' would use with the
+config file. The system checks and tuners aren't run.
+
+With --drain, the node is put into maintenance mode before it's stopped.
+With --wait-for-ready, redpanda is started in the background and rpk waits
+until it's ready, taking it out of maintenance mode if it was drained. Used
+together, they restart a node of a cluster without disrupting clients, one
+node at a time. Without --wait-for-ready, redpanda runs in the foreground and
+a drained node stays in maintenance mode until it's disabled with
+'rpk redpanda admin brokers maintenance disable'.`,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(ccmd *cobra.Command, _ []string) error {
+			if logFile != "" && !waitReady {
+				return errors.New("--log-file is only supported with --wait-for-ready")
+			}
+			conf, err := mgr.ReadOrFind(configFile)
+			if err != nil {
+				return err
+			}
+			installDir, err := cli.GetOrFindInstallDir(fs, installDirFlag)
+			if err != nil {
+				return err
+			}
+			pid, running, err := runningPID(fs, conf)
+			if err != nil {
+				return err
+			}
+			rpArgs, err := restartArgs(fs, conf, pid, running)
+			if err != nil {
+				return err
+			}
+			if running {
+				if drain {
+					drainBeforeStop(fs, conf, drainTimeout)
+				}
+				err = stopProcess(pid, timeout)
+				// A process that had to be killed is still
+				// restarted.
+				if err != nil && out.ExitCode(err) != 2 {
+					return err
+				}
+			}
+
+			log.Info("Starting redpanda...")
+			if !waitReady {
+				return launcher.Start(installDir, rpArgs)
+			}
+			if logFile == "" {
+				logFile = rp.GetLogPath(conf.Redpanda.Directory)
+			}
+			err = startAndWaitForReady(
+				fs,
+				launcher,
+				installDir,
+				rpArgs,
+				conf,
+				logFile,
+				readyTimeout,
+			)
+			if err != nil || !drain {
+				return err
+			}
+			return undrain(fs, conf)
+		},
+	}
+	command.Flags().StringVar(
+		&configFile,
+		"config",
+		"",
+		"Redpanda config file, if not set the file will be searched for"+
+			" in the default locations",
+	)
+	command.Flags().StringVar(
+		&installDirFlag,
+		"install-dir",
+		"",
+		"Directory where redpanda has been installed",
+	)
+	command.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		10*time.Second,
+		"The maximum amount of time to wait for redpanda to stop"+
+			" after SIGTERM is sent, before killing it",
+	)
+	command.Flags().BoolVar(
+		&drain,
+		"drain",
+		false,
+		"Put the node into maintenance mode to drain its partitions'"+
+			" leadership before stopping it",
+	)
+	command.Flags().DurationVar(
+		&drainTimeout,
+		"drain-timeout",
+		time.Minute,
+		"The maximum amount of time to wait for the node to be drained"+
+			" with --drain, after which it's stopped anyway",
+	)
+	command.Flags().BoolVar(
+		&waitReady,
+		"wait-for-ready",
+		false,
+		"Start redpanda in the background and exit once its admin API"+
+			" reports that it's ready",
+	)
+	command.Flags().DurationVar(
+		&readyTimeout,
+		"ready-timeout",
+		60*time.Second,
+		"The maximum time to wait for redpanda to be ready with --wait-for-ready",
+	)
+	command.Flags().StringVar(
+		&logFile,
+		"log-file",
+		"",
+		"The file redpanda logs to with --wait-for-ready (default"+
+			" redpanda.log in the data directory)",
+	)
+	return command
+}
+
+// restartArgs returns the arguments and environment of the running redpanda
+// process, or if it isn't running or its arguments can't be read, the
+// arguments 'rpk redpanda start' would use without flags.
+func restartArgs(
+	fs afero.Fs, conf *config.Config, pid int, running bool,
+) (*rp.RedpandaArgs, error) {
+	if running {
+		args, err := processArgs(fs, pid)
+		if err == nil {
+			args.Env, err = processEnv(fs, pid)
+			if err != nil {
+				log.Warnf(
+					"Unable to read the environment of redpanda (PID %d), using rpk's: %v",
+					pid,
+					err,
+				)
+			}
+			return args, nil
+		}
+		log.Warnf(
+			"Unable to read the arguments of redpanda (PID %d), using the config's: %v",
+			pid,
+			err,
+		)
+	}
+	noFlags := pflag.NewFlagSet("restart", pflag.ContinueOnError)
+	return buildRedpandaFlags(fs, conf, nil, seastarFlags{}, noFlags, false)
+}
+
+// processArgs returns the arguments that the redpanda process was started
+// with by the launcher, from its /proc/<pid>/cmdline.
+func processArgs(fs afero.Fs, pid int) (*rp.RedpandaArgs, error) {
+	cmdline, err := afero.ReadFile(fs, fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return nil, err
+	}
+	args := strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+	// The launcher starts redpanda with
+	//   redpanda --redpanda-cfg <file> [flags...]
+	if len(args) < 3 || args[1] != "--redpanda-cfg" {
+		return nil, fmt.Errorf("unexpected command line %q", strings.Join(args, " "))
+	}
+	return &rp.RedpandaArgs{
+		ConfigFilePath: args[2],
+		SeastarFlags:   map[string]string{},
+		ExtraArgs:      args[3:],
+	}, nil
+}
+
+// processEnv returns the environment of the redpanda process, from its
+// /proc/<pid>/environ, without LD_LIBRARY_PATH, which the launcher never
+// passes on to redpanda.
+func processEnv(fs afero.Fs, pid int) ([]string, error) {
+	environ, err := afero.ReadFile(fs, fmt.Sprintf("/proc/%d/environ", pid))
+	if err != nil {
+		return nil, err
+	}
+	var env []string
+	for _, ev := range strings.Split(string(environ), "\x00") {
+		if ev == "" || strings.HasPrefix(ev, "LD_LIBRARY_PATH=") {
+			continue
+		}
+		env = append(env, ev)
+	}
+	return env, nil
+}
+
+// undrain takes the local node out of maintenance mode.
+func undrain(fs afero.Fs, conf *config.Config) error {
+	cl, err := newLocalAdminAPI(fs, conf)
+	if err != nil {
+		return err
+	}
+	err = cl.DisableMaintenanceMode(context.Background(), conf.Redpanda.Id)
+	if err != nil {
+		return fmt.Errorf("unable to take node %d out of maintenance mode: %v", conf.Redpanda.Id, err)
+	}
+	log.Infof("Node %d is out of maintenance mode.", conf.Redpanda.Id)
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+)
+
+func TestProcessArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmdline  string
+		expected *rp.RedpandaArgs
+		expErr   bool
+	}{
+		{
+			name:    "it should return the args the launcher started redpanda with",
+			cmdline: "redpanda\x00--redpanda-cfg\x00/etc/redpanda/redpanda.yaml\x00--smp=2\x00--overprovisioned\x00",
+			expected: &rp.RedpandaArgs{
+				ConfigFilePath: "/etc/redpanda/redpanda.yaml",
+				SeastarFlags:   map[string]string{},
+				ExtraArgs:      []string{"--smp=2", "--overprovisioned"},
+			},
+		},
+		{
+			name:    "it should return no extra args if the config was the only one",
+			cmdline: "redpanda\x00--redpanda-cfg\x00/etc/redpanda/redpanda.yaml\x00",
+			expected: &rp.RedpandaArgs{
+				ConfigFilePath: "/etc/redpanda/redpanda.yaml",
+				SeastarFlags:   map[string]string{},
+				ExtraArgs:      []string{},
+			},
+		},
+		{
+			name:    "it should fail if redpanda wasn't started by the launcher",
+			cmdline: "redpanda\x00--smp=2\x00",
+			expErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, "/proc/42/cmdline", []byte(tt.cmdline), 0444)
+			require.NoError(t, err)
+
+			args, err := processArgs(fs, 42)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestRestartArgsFromConfig(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	conf := config.Default()
+	smp := 3
+	conf.Rpk.SMP = &smp
+	conf.Rpk.AdditionalStartFlags = []string{"--abort-on-seastar-bad-alloc"}
+
+	// The process isn't running, or its arguments can't be read.
+	for _, running := range []bool{false, true} {
+		args, err := restartArgs(fs, conf, 42, running)
+		require.NoError(t, err)
+		require.Equal(t, conf.ConfigFile, args.ConfigFilePath)
+		require.Equal(t, "3", args.SeastarFlags["smp"])
+		require.Contains(t, args.SeastarFlags, "abort-on-seastar-bad-alloc")
+		require.Empty(t, args.ExtraArgs)
+	}
+}
+
+func TestProcessEnv(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	environ := "HOME=/root\x00LD_LIBRARY_PATH=/opt/redpanda/lib\x00GODEBUG=x509ignoreCN=0\x00"
+	err := afero.WriteFile(fs, "/proc/42/environ", []byte(environ), 0444)
+	require.NoError(t, err)
+	err = afero.WriteFile(fs, "/proc/42/cmdline", []byte("redpanda\x00--redpanda-cfg\x00/etc/redpanda/redpanda.yaml\x00"), 0444)
+	require.NoError(t, err)
+
+	args, err := restartArgs(fs, config.Default(), 42, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"HOME=/root", "GODEBUG=x509ignoreCN=0"}, args.Env)
+
+	// The arguments are still used if the environment can't be read.
+	err = fs.Remove("/proc/42/environ")
+	require.NoError(t, err)
+	args, err = restartArgs(fs, config.Default(), 42, true)
+	require.NoError(t, err)
+	require.Equal(t, "/etc/redpanda/redpanda.yaml", args.ConfigFilePath)
+	require.Empty(t, args.Env)
+}
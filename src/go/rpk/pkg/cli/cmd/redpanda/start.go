@@ -130,6 +130,14 @@ func NewStartCommand(
 		installDirFlag  string
 		timeout         time.Duration
 		wellKnownIo     string
+		waitReady       bool
+		readyTimeout    time.Duration
+		logFile         string
+		envVars         []string
+		envFile         string
+		output          rp.OutputFiles
+		systemdMode     bool
+		mode            string
 	)
 	sFlags := seastarFlags{}
 
@@ -159,8 +167,30 @@ func NewStartCommand(
 					return err
 				}
 			}
+			if mode != "" {
+				mode, err = applyMode(mode, conf, ccmd.Flags())
+				if err != nil {
+					return err
+				}
+			}
 
 			updateConfigWithFlags(conf, ccmd.Flags())
+			if logFile != "" && !waitReady {
+				return fmt.Errorf("--log-file is only supported with --wait-for-ready")
+			}
+			if systemdMode && waitReady {
+				return fmt.Errorf("--systemd and --wait-for-ready can't be used together, --systemd already waits for redpanda to be ready")
+			}
+			if output.Append && output.Stdout == "" && output.Stderr == "" {
+				return fmt.Errorf("--append-output requires --stdout-file or --stderr-file")
+			}
+			if waitReady && (output.Stdout != "" || output.Stderr != "") {
+				return fmt.Errorf("--stdout-file and --stderr-file aren't supported with --wait-for-ready, use --log-file instead")
+			}
+			rpEnv, err := redpandaEnv(fs, envFile, envVars)
+			if err != nil {
+				return err
+			}
 
 			env := api.EnvironmentPayload{}
 			if len(seeds) == 0 {
@@ -318,6 +348,20 @@ func NewStartCommand(
 			if advRPCApi != nil {
 				conf.Redpanda.AdvertisedRPCAPI = advRPCApi
 			}
+			if len(seedServers) != 0 {
+				self := conf.Redpanda.RPCServer
+				if conf.Redpanda.AdvertisedRPCAPI != nil {
+					self = *conf.Redpanda.AdvertisedRPCAPI
+				}
+				err = newSeedsChecker(prestartCfg.checkEnabled).check(
+					seedServers,
+					self,
+				)
+				if err != nil {
+					sendEnv(fs, mgr, env, conf, !prestartCfg.checkEnabled, err)
+					return err
+				}
+			}
 			installDirectory, err := cli.GetOrFindInstallDir(fs, installDirFlag)
 			if err != nil {
 				sendEnv(fs, mgr, env, conf, !prestartCfg.checkEnabled, err)
@@ -335,6 +379,16 @@ func NewStartCommand(
 				sendEnv(fs, mgr, env, conf, !prestartCfg.checkEnabled, err)
 				return err
 			}
+			err = checkResources(fs, rpArgs.SeastarFlags)
+			if err != nil {
+				sendEnv(fs, mgr, env, conf, !prestartCfg.checkEnabled, err)
+				return err
+			}
+			rpArgs.Env = rpEnv
+			if mode != "" {
+				printModeSettings(mode, conf, rpArgs)
+			}
+			rpArgs.Output = output
 			checkPayloads, tunerPayloads, err := prestart(
 				fs,
 				rpArgs,
@@ -359,6 +413,36 @@ func NewStartCommand(
 			rpArgs.ExtraArgs = args
 			log.Info(common.FeedbackMsg)
 			log.Info("Starting redpanda...")
+			if systemdMode {
+				// systemd enforces TimeoutStartSec itself, so a redpanda
+				// that is still recovering isn't stopped early unless a
+				// timeout was asked for.
+				if !ccmd.Flags().Changed("ready-timeout") {
+					readyTimeout = 0
+				}
+				return startWithSystemd(
+					fs,
+					launcher,
+					installDirectory,
+					rpArgs,
+					conf,
+					readyTimeout,
+				)
+			}
+			if waitReady {
+				if logFile == "" {
+					logFile = rp.GetLogPath(conf.Redpanda.Directory)
+				}
+				return startAndWaitForReady(
+					fs,
+					launcher,
+					installDirectory,
+					rpArgs,
+					conf,
+					logFile,
+					readyTimeout,
+				)
+			}
 			return launcher.Start(installDirectory, rpArgs)
 		},
 	}
@@ -382,7 +466,10 @@ func NewStartCommand(
 		"s",
 		[]string{},
 		"A comma-separated list of seed node addresses"+
-			" (<host>[:<port>]) to connect to",
+			" (<host>[:<port>]) to connect to. Repeated seeds are ignored,"+
+			" and seeds that are this node's own RPC address are rejected."+
+			" Unless --check=false is passed, seed hosts that don't"+
+			" resolve are warned about",
 	)
 	command.Flags().StringSliceVar(
 		&kafkaAddr,
@@ -427,8 +514,9 @@ func NewStartCommand(
 		"The advertised RPC address (<host>:<port>)",
 	)
 	command.Flags().StringVar(&sFlags.memory,
-		memoryFlag, "", "Amount of memory for redpanda to use, "+
-			"if not specified redpanda will use all available memory")
+		memoryFlag, "", "Amount of memory for redpanda to use, such as 4G,"+
+			" if not specified redpanda will use all available memory. It"+
+			" can't be more than the memory available on this host")
 	command.Flags().BoolVar(&sFlags.lockMemory,
 		lockMemoryFlag, false, "If set, will prevent redpanda from swapping")
 	command.Flags().StringVar(&sFlags.cpuSet, cpuSetFlag, "",
@@ -442,10 +530,12 @@ func NewStartCommand(
 	command.Flags().BoolVar(&prestartCfg.checkEnabled, "check", true,
 		"When set to false will disable system checking before starting redpanda")
 	command.Flags().IntVar(&sFlags.smp, smpFlag, 0, "Restrict redpanda to"+
-		" the given number of CPUs. This option does not mandate a"+
-		" specific placement of CPUs. See --cpuset if you need to do so.")
+		" the given number of CPUs, overriding rpk.smp. It can't be more"+
+		" than the CPUs available on this host. This option does not"+
+		" mandate a specific placement of CPUs. See --cpuset if you need"+
+		" to do so.")
 	command.Flags().StringVar(&sFlags.reserveMemory, reserveMemoryFlag, "",
-		"Memory reserved for the OS (if --memory isn't specified)")
+		"Memory reserved for the OS (if --memory isn't specified), such as 1G")
 	command.Flags().StringVar(&sFlags.hugepages, hugepagesFlag, "",
 		"Path to accessible hugetlbfs mount (typically /dev/hugepages/something)")
 	command.Flags().BoolVar(&sFlags.threadAffinity, threadAffinityFlag, true,
@@ -481,8 +571,93 @@ func NewStartCommand(
 			"fraction and a unit suffix, such as '300ms', '1.5s' or '2h45m'. "+
 			"Valid time units are 'ns', 'us' (or 'µs'), 'ms', 's', 'm', 'h'",
 	)
+	command.Flags().BoolVar(
+		&waitReady,
+		"wait-for-ready",
+		false,
+		"Start redpanda in the background and exit once its admin API"+
+			" reports that it's ready, or with an error and the last lines"+
+			" of its log if it isn't ready within --ready-timeout",
+	)
+	command.Flags().DurationVar(
+		&readyTimeout,
+		"ready-timeout",
+		60*time.Second,
+		"The maximum time to wait for redpanda to be ready with"+
+			" --wait-for-ready, or with --systemd if it's set explicitly;"+
+			" otherwise --systemd leaves it to the unit's TimeoutStartSec",
+	)
+	command.Flags().StringVar(
+		&mode,
+		"mode",
+		"",
+		"Set the mode before starting redpanda, as rpk redpanda mode"+
+			" does: dev, for a single-node setup on a laptop, also runs"+
+			" redpanda on 1 CPU without reserving memory for the OS"+
+			" (overridden by --smp and --reserve-memory); or prod",
+	)
+	command.Flags().BoolVar(
+		&systemdMode,
+		"systemd",
+		false,
+		"Run as the main process of a Type=notify systemd service: start"+
+			" redpanda as a child, send READY=1 to $NOTIFY_SOCKET once its"+
+			" admin API reports that it's ready, ping the watchdog if it's"+
+			" enabled, and send STOPPING=1 when it's stopping. SIGTERM and"+
+			" SIGINT are forwarded to redpanda",
+	)
+	command.Flags().StringVar(
+		&logFile,
+		"log-file",
+		"",
+		"The file redpanda logs to with --wait-for-ready (default"+
+			" redpanda.log in the data directory)",
+	)
+	command.Flags().StringArrayVar(
+		&envVars,
+		"env",
+		[]string{},
+		"An environment variable to set for redpanda, in KEY=VALUE format."+
+			" May be repeated. Takes precedence over --env-file and over"+
+			" the environment inherited from rpk",
+	)
+	command.Flags().StringVar(
+		&envFile,
+		"env-file",
+		"",
+		"A file with KEY=VALUE environment variables to set for redpanda,"+
+			" one per line. Empty lines and lines starting with '#' are"+
+			" ignored. Takes precedence over the environment inherited"+
+			" from rpk",
+	)
+	command.Flags().StringVar(
+		&output.Stdout,
+		"stdout-file",
+		"",
+		"Redirect redpanda's stdout to this file. rpk fails before"+
+			" starting redpanda if the file can't be opened",
+	)
+	command.Flags().StringVar(
+		&output.Stderr,
+		"stderr-file",
+		"",
+		"Redirect redpanda's stderr to this file, which may be the same"+
+			" as --stdout-file. rpk fails before starting redpanda if the"+
+			" file can't be opened",
+	)
+	command.Flags().BoolVar(
+		&output.Append,
+		"append-output",
+		false,
+		"Append to --stdout-file and --stderr-file instead of truncating them",
+	)
+	// Of the Seastar flags, only the resource ones are shown.
 	for flag := range flagsMap(sFlags) {
-		command.Flag(flag).Hidden = true
+		switch flag {
+		case smpFlag, memoryFlag, reserveMemoryFlag:
+		default:
+			command.Flag(flag).Hidden = true
+		}
 	}
 	return command
 }
@@ -852,9 +1027,11 @@ func parseFlags(flags []string) map[string]string {
 	return parsed
 }
 
+// parseSeeds parses the seeds, ignoring the repeated ones.
 func parseSeeds(seeds []string) ([]config.SeedServer, error) {
 	seedServers := []config.SeedServer{}
 	defaultPort := config.Default().Redpanda.RPCServer.Port
+	parsed := map[string]bool{}
 	for _, s := range seeds {
 		addr, err := parseAddress(s, defaultPort)
 		if err != nil {
@@ -870,6 +1047,19 @@ func parseSeeds(seeds []string) ([]config.SeedServer, error) {
 				s,
 			)
 		}
+		if addr.Port < 1 || addr.Port > 65535 {
+			return seedServers, fmt.Errorf(
+				"Couldn't parse seed '%s': port %d is out of range (1-65535)",
+				s,
+				addr.Port,
+			)
+		}
+		key := fmt.Sprintf("%s:%d", strings.ToLower(addr.Address), addr.Port)
+		if parsed[key] {
+			log.Warnf("Ignoring repeated seed '%s'", s)
+			continue
+		}
+		parsed[key] = true
 		seedServers = append(
 			seedServers,
 			config.SeedServer{Host: *addr},
@@ -0,0 +1,76 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+var envKeyPattern = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// redpandaEnv returns the environment variables to set for redpanda, from
+// the env file, if any, followed by the ones passed with --env, so that the
+// latter take precedence when they're merged into the inherited environment.
+func redpandaEnv(fs afero.Fs, envFile string, envVars []string) ([]string, error) {
+	var env []string
+	if envFile != "" {
+		fromFile, err := readEnvFile(fs, envFile)
+		if err != nil {
+			return nil, err
+		}
+		env = fromFile
+	}
+	for _, ev := range envVars {
+		err := checkEnvVar(ev)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --env %q: %v", ev, err)
+		}
+		env = append(env, ev)
+	}
+	return env, nil
+}
+
+// readEnvFile reads the KEY=VALUE lines of an env file. Empty lines and
+// lines starting with '#' are ignored, and values are taken as they are,
+// without unquoting them.
+func readEnvFile(fs afero.Fs, path string) ([]string, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the env file: %v", err)
+	}
+	var env []string
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		err = checkEnvVar(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, i+1, err)
+		}
+		env = append(env, line)
+	}
+	return env, nil
+}
+
+func checkEnvVar(ev string) error {
+	parts := strings.SplitN(ev, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected KEY=VALUE")
+	}
+	if !envKeyPattern.MatchString(parts[0]) {
+		return fmt.Errorf("invalid variable name '%s'", parts[0])
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedpandaEnv(t *testing.T) {
+	const envFile = "/etc/redpanda/redpanda.env"
+	tests := []struct {
+		name           string
+		fileContents   string
+		envFile        string
+		envVars        []string
+		expected       []string
+		expectedErrMsg string
+	}{
+		{
+			name: "it should return nothing if nothing is set",
+		},
+		{
+			name:     "it should return the --env vars",
+			envVars:  []string{"MALLOC_CONF=abort:true", "EMPTY=", "A=b=c"},
+			expected: []string{"MALLOC_CONF=abort:true", "EMPTY=", "A=b=c"},
+		},
+		{
+			name: "it should put the --env vars after the env file's",
+			fileContents: `# Cloud credentials
+AWS_ACCESS_KEY_ID=key
+
+  AWS_SECRET_ACCESS_KEY=secret
+`,
+			envFile:  envFile,
+			envVars:  []string{"AWS_ACCESS_KEY_ID=other"},
+			expected: []string{"AWS_ACCESS_KEY_ID=key", "AWS_SECRET_ACCESS_KEY=secret", "AWS_ACCESS_KEY_ID=other"},
+		},
+		{
+			name:           "it should fail if the env file doesn't exist",
+			envFile:        envFile,
+			expectedErrMsg: "unable to read the env file: open /etc/redpanda/redpanda.env: file does not exist",
+		},
+		{
+			name:           "it should fail if a line in the env file is invalid",
+			fileContents:   "A=1\nexport B=2\n",
+			envFile:        envFile,
+			expectedErrMsg: "/etc/redpanda/redpanda.env:2: invalid variable name 'export B'",
+		},
+		{
+			name:           "it should fail if an --env var has no value",
+			envVars:        []string{"A"},
+			expectedErrMsg: `invalid --env "A": expected KEY=VALUE`,
+		},
+		{
+			name:           "it should fail if an --env var has no name",
+			envVars:        []string{"=1"},
+			expectedErrMsg: `invalid --env "=1": invalid variable name ''`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			if tt.fileContents != "" {
+				err := afero.WriteFile(fs, envFile, []byte(tt.fileContents), 0600)
+				require.NoError(t, err)
+			}
+			env, err := redpandaEnv(fs, tt.envFile, tt.envVars)
+			if tt.expectedErrMsg != "" {
+				require.EqualError(t, err, tt.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Exactly(t, tt.expected, env)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+)
+
+const (
+	// devSMP is the number of CPUs redpanda uses in dev mode.
+	devSMP = 1
+	// devReserveMemory is the memory left for the OS in dev mode, so that
+	// redpanda doesn't take most of a laptop's memory.
+	devReserveMemory = "0M"
+)
+
+// applyMode sets the mode in the config, as rpk redpanda mode does. The dev
+// mode also runs redpanda on a single CPU without reserving memory for the
+// OS, unless --smp or --reserve-memory are passed. Those are only set for
+// this launch, as flags, so they aren't written to the config. It returns the
+// normalized mode.
+func applyMode(
+	mode string, conf *config.Config, flags *pflag.FlagSet,
+) (string, error) {
+	m, err := config.NormalizeMode(mode)
+	if err != nil {
+		return "", err
+	}
+	_, err = config.SetMode(m, conf)
+	if err != nil {
+		return "", err
+	}
+	if m != config.ModeDev {
+		return m, nil
+	}
+	if !flags.Changed(smpFlag) {
+		err = flags.Set(smpFlag, strconv.Itoa(devSMP))
+		if err != nil {
+			return "", err
+		}
+	}
+	if !flags.Changed(reserveMemoryFlag) {
+		err = flags.Set(reserveMemoryFlag, devReserveMemory)
+		if err != nil {
+			return "", err
+		}
+	}
+	return m, nil
+}
+
+// printModeSettings logs the settings that redpanda runs with in the mode.
+func printModeSettings(mode string, conf *config.Config, rpArgs *rp.RedpandaArgs) {
+	or := func(flag, def string) string {
+		if v, ok := rpArgs.SeastarFlags[flag]; ok {
+			return v
+		}
+		return def
+	}
+	log.Infof(
+		"Starting in %s mode with:\n"+
+			"  redpanda.developer_mode: %t\n"+
+			"  rpk.overprovisioned: %t\n"+
+			"  --%s: %s\n"+
+			"  --%s: %s",
+		mode,
+		conf.Redpanda.DeveloperMode,
+		conf.Rpk.Overprovisioned,
+		smpFlag, or(smpFlag, "all CPUs"),
+		reserveMemoryFlag, or(reserveMemoryFlag, "redpanda's default"),
+	)
+}
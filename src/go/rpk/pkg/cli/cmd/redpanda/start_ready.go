@@ -0,0 +1,113 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+)
+
+// readyLogLines is how many of the last lines of the redpanda log are shown
+// when it doesn't become ready.
+const readyLogLines = 20
+
+// startAndWaitForReady starts redpanda in the background and waits until its
+// admin API reports that it's ready.
+func startAndWaitForReady(
+	fs afero.Fs,
+	launcher rp.Launcher,
+	installDir string,
+	rpArgs *rp.RedpandaArgs,
+	conf *config.Config,
+	logFile string,
+	timeout time.Duration,
+) error {
+	cl, err := newLocalAdminAPI(fs, conf)
+	if err != nil {
+		return err
+	}
+	proc, err := launcher.StartInBackground(installDir, rpArgs, logFile)
+	if err != nil {
+		return err
+	}
+	return waitForReady(fs, cl, proc, logFile, timeout)
+}
+
+// newLocalAdminAPI returns a client for the admin API of the local node, which
+// ignores rpk.admin_api.addresses, since they may be of other nodes.
+func newLocalAdminAPI(fs afero.Fs, conf *config.Config) (*admin.AdminAPI, error) {
+	local := *conf
+	local.Rpk.AdminApi.Addresses = nil
+	return admin.NewAdminAPIFromConfig(fs, &local)
+}
+
+// waitForReady waits until the redpanda process is ready, it exits or the
+// timeout elapses. If it doesn't become ready, the error has the last lines
+// of its log. A process that is still starting when the timeout elapses is
+// left running.
+func waitForReady(
+	fs afero.Fs,
+	cl admin.AdminClient,
+	proc *rp.BackgroundProcess,
+	logFile string,
+	timeout time.Duration,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ready := make(chan error, 1)
+	go func() { ready <- cl.WaitForReady(ctx, 0) }()
+
+	select {
+	case err := <-ready:
+		if err == nil {
+			log.Infof("Redpanda is ready (pid %d), logging to %s", proc.Pid, logFile)
+			return nil
+		}
+		return fmt.Errorf(
+			"redpanda (pid %d) is still running but isn't ready: %v%s",
+			proc.Pid, err, tailLog(fs, logFile, readyLogLines),
+		)
+	case err := <-proc.Exited:
+		msg := "redpanda exited before it was ready"
+		if err != nil {
+			msg += ": " + err.Error()
+		}
+		return fmt.Errorf("%s%s", msg, tailLog(fs, logFile, readyLogLines))
+	}
+}
+
+// tailLog returns the last n lines of the log file, with a header so that it
+// can be appended to an error, or an empty string if it can't be read.
+func tailLog(fs afero.Fs, logFile string, n int) string {
+	content, err := afero.ReadFile(fs, logFile)
+	if err != nil {
+		log.Debugf("Unable to read %s: %v", logFile, err)
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return ""
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return fmt.Sprintf(
+		"\nlast %d lines of %s:\n%s",
+		len(lines), logFile, strings.Join(lines, "\n"),
+	)
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin/mocks"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+)
+
+func TestWaitForReady(t *testing.T) {
+	const logFile = "/var/lib/redpanda/data/redpanda.log"
+	var logLines []string
+	for i := 0; i < 30; i++ {
+		logLines = append(logLines, fmt.Sprintf("line %d", i))
+	}
+	log := strings.Join(logLines, "\n") + "\n"
+
+	tests := []struct {
+		name     string
+		ready    func(time.Duration) error
+		exitErr  error
+		exited   bool
+		expErr   []string
+		expNoErr bool
+	}{
+		{
+			name:     "it should return once redpanda is ready",
+			ready:    func(time.Duration) error { return nil },
+			expNoErr: true,
+		},
+		{
+			name: "it should fail with the log tail if redpanda isn't ready in time",
+			ready: func(time.Duration) error {
+				return fmt.Errorf("node did not become ready: %w", context.DeadlineExceeded)
+			},
+			expErr: []string{
+				"redpanda (pid 42) is still running but isn't ready",
+				"last 20 lines of " + logFile,
+				"line 10\n",
+				"line 29",
+			},
+		},
+		{
+			name:    "it should fail with the log tail if redpanda exits",
+			exitErr: errors.New("exit status 1"),
+			exited:  true,
+			expErr: []string{
+				"redpanda exited before it was ready: exit status 1",
+				"line 29",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, logFile, []byte(log), 0644))
+
+			blocked := make(chan struct{})
+			defer close(blocked)
+			ready := tt.ready
+			if ready == nil {
+				ready = func(time.Duration) error {
+					<-blocked
+					return nil
+				}
+			}
+			exited := make(chan error, 1)
+			if tt.exited {
+				exited <- tt.exitErr
+			}
+			proc := &rp.BackgroundProcess{Pid: 42, Exited: exited}
+
+			err := waitForReady(
+				fs,
+				mocks.MockAdminAPI{MockWaitForReady: ready},
+				proc,
+				logFile,
+				time.Second,
+			)
+			if tt.expNoErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, exp := range tt.expErr {
+				require.Contains(t, err.Error(), exp)
+			}
+			require.NotContains(t, err.Error(), "line 9\n")
+		})
+	}
+}
+
+func TestTailLog(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.Equal(t, "", tailLog(fs, "/missing.log", 2))
+
+	require.NoError(t, afero.WriteFile(fs, "/empty.log", nil, 0644))
+	require.Equal(t, "", tailLog(fs, "/empty.log", 2))
+
+	require.NoError(t, afero.WriteFile(fs, "/redpanda.log", []byte("a\nb\nc\n"), 0644))
+	require.Equal(t, "\nlast 2 lines of /redpanda.log:\nb\nc", tailLog(fs, "/redpanda.log", 2))
+	require.Equal(t, "\nlast 3 lines of /redpanda.log:\na\nb\nc", tailLog(fs, "/redpanda.log", 5))
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/system"
+)
+
+// checkResources checks the --smp, --memory and --reserve-memory that
+// redpanda is started with against the CPUs and memory available on this
+// host. If they can't be read, only the values themselves are checked.
+func checkResources(fs afero.Fs, seastarFlags map[string]string) error {
+	cpus, err := system.AvailableCPUs(fs)
+	if err != nil {
+		log.Debugf("Unable to read the number of CPUs: %v", err)
+	}
+	mem, err := system.AvailableMemBytes(fs)
+	if err != nil {
+		log.Debugf("Unable to read the available memory: %v", err)
+	}
+	return checkResourceFlags(seastarFlags, cpus, mem)
+}
+
+// checkResourceFlags fails for resource flags that redpanda can't start
+// with, given the CPUs and memory available, which are skipped if they're 0.
+func checkResourceFlags(
+	seastarFlags map[string]string, cpus, mem uint64,
+) error {
+	if v, ok := seastarFlags[smpFlag]; ok {
+		smp, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || smp == 0 {
+			return fmt.Errorf("invalid --%s '%s', it must be a positive integer", smpFlag, v)
+		}
+		if cpus != 0 && smp > cpus {
+			return fmt.Errorf(
+				"--%s %d is more than the %d CPUs available on this host",
+				smpFlag,
+				smp,
+				cpus,
+			)
+		}
+	}
+	memory, hasMemory, err := memoryFlagBytes(seastarFlags, memoryFlag)
+	if err != nil {
+		return err
+	}
+	reserve, hasReserve, err := memoryFlagBytes(seastarFlags, reserveMemoryFlag)
+	if err != nil {
+		return err
+	}
+	if mem == 0 {
+		return nil
+	}
+	if hasMemory && memory > mem {
+		return fmt.Errorf(
+			"--%s %s is more than the %s of memory available on this host",
+			memoryFlag,
+			seastarFlags[memoryFlag],
+			units.BytesSize(float64(mem)),
+		)
+	}
+	if hasReserve && reserve >= mem {
+		return fmt.Errorf(
+			"--%s %s leaves no memory for redpanda, this host has %s",
+			reserveMemoryFlag,
+			seastarFlags[reserveMemoryFlag],
+			units.BytesSize(float64(mem)),
+		)
+	}
+	if hasMemory && hasReserve && memory > mem-reserve {
+		log.Warnf(
+			"--%s %s is more than the %s of memory left on this host after"+
+				" --%s %s, the OS may run out of memory",
+			memoryFlag,
+			seastarFlags[memoryFlag],
+			units.BytesSize(float64(mem-reserve)),
+			reserveMemoryFlag,
+			seastarFlags[reserveMemoryFlag],
+		)
+	}
+	return nil
+}
+
+// memoryFlagBytes parses the value of a memory flag, such as 4G or 512M.
+func memoryFlagBytes(
+	seastarFlags map[string]string, flag string,
+) (uint64, bool, error) {
+	v, ok := seastarFlags[flag]
+	if !ok {
+		return 0, false, nil
+	}
+	bytes, err := units.RAMInBytes(v)
+	if err != nil || bytes < 0 {
+		return 0, false, fmt.Errorf(
+			"invalid --%s '%s', it must be an amount of memory such as 4G or 512M",
+			flag,
+			v,
+		)
+	}
+	return uint64(bytes), true, nil
+}
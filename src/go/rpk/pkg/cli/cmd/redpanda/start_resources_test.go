@@ -0,0 +1,92 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"testing"
+
+	"github.com/docker/go-units"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckResourceFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		flags          map[string]string
+		cpus           uint64
+		mem            uint64
+		expectedErrMsg string
+	}{
+		{
+			name:  "it should pass if the resources fit the host",
+			flags: map[string]string{"smp": "4", "memory": "12G", "reserve-memory": "2G"},
+			cpus:  4,
+			mem:   16 * units.GiB,
+		},
+		{
+			name:  "it should pass if no resources are set",
+			flags: map[string]string{"overprovisioned": "true"},
+			cpus:  1,
+			mem:   units.GiB,
+		},
+		{
+			name:  "it should pass if the host's resources are unknown",
+			flags: map[string]string{"smp": "64", "memory": "1T"},
+		},
+		{
+			name:  "it should only warn if --memory doesn't leave --reserve-memory",
+			flags: map[string]string{"memory": "15G", "reserve-memory": "2G"},
+			mem:   16 * units.GiB,
+		},
+		{
+			name:           "it should fail if --smp is more than the CPUs",
+			flags:          map[string]string{"smp": "5"},
+			cpus:           4,
+			expectedErrMsg: "--smp 5 is more than the 4 CPUs available on this host",
+		},
+		{
+			name:           "it should fail if --smp is 0",
+			flags:          map[string]string{"smp": "0"},
+			expectedErrMsg: "invalid --smp '0', it must be a positive integer",
+		},
+		{
+			name:           "it should fail if --smp isn't a number",
+			flags:          map[string]string{"smp": "all"},
+			expectedErrMsg: "invalid --smp 'all', it must be a positive integer",
+		},
+		{
+			name:           "it should fail if --memory is more than the memory",
+			flags:          map[string]string{"memory": "17G"},
+			mem:            16 * units.GiB,
+			expectedErrMsg: "--memory 17G is more than the 16GiB of memory available on this host",
+		},
+		{
+			name:           "it should fail if --reserve-memory is all the memory",
+			flags:          map[string]string{"reserve-memory": "16G"},
+			mem:            16 * units.GiB,
+			expectedErrMsg: "--reserve-memory 16G leaves no memory for redpanda, this host has 16GiB",
+		},
+		{
+			name:           "it should fail if --memory is invalid",
+			flags:          map[string]string{"memory": "lots"},
+			expectedErrMsg: "invalid --memory 'lots', it must be an amount of memory such as 4G or 512M",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkResourceFlags(tt.flags, tt.cpus, tt.mem)
+			if tt.expectedErrMsg != "" {
+				require.EqualError(t, err, tt.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+// seedsChecker checks the seeds passed to start before redpanda is started
+// with them.
+type seedsChecker struct {
+	// resolve enables resolving the seeds' hosts, so that seeds resolving
+	// to this node are found. Hosts that don't resolve are only warned
+	// about, since they may not be in DNS until their nodes are up.
+	resolve    bool
+	lookupHost func(host string) ([]string, error)
+	localAddrs func() ([]net.Addr, error)
+}
+
+// lookupSeedHost resolves the seeds' hosts for 'start'. Tests replace it so
+// that they don't depend on DNS.
+var lookupSeedHost = net.LookupHost
+
+func newSeedsChecker(resolve bool) *seedsChecker {
+	return &seedsChecker{
+		resolve:    resolve,
+		lookupHost: lookupSeedHost,
+		localAddrs: net.InterfaceAddrs,
+	}
+}
+
+// check fails for the first seed that is this node's own RPC address. self
+// is the RPC address this node advertises, and if it's the unspecified
+// address, the addresses of the local interfaces are used instead.
+func (c *seedsChecker) check(
+	seeds []config.SeedServer, self config.SocketAddress,
+) error {
+	selfIPs, err := c.selfIPs(self)
+	if err != nil {
+		return err
+	}
+	for _, s := range seeds {
+		seed := fmt.Sprintf("%s:%d", s.Host.Address, s.Host.Port)
+		if s.Host.Port != self.Port {
+			if c.resolve {
+				_, err := c.lookupIPs(s.Host.Address)
+				if err != nil {
+					log.Warnf("Couldn't resolve seed '%s': %v", seed, err)
+				}
+			}
+			continue
+		}
+		isSelf := strings.EqualFold(s.Host.Address, self.Address)
+		if !isSelf && c.resolve {
+			ips, err := c.lookupIPs(s.Host.Address)
+			if err != nil {
+				log.Warnf("Couldn't resolve seed '%s': %v", seed, err)
+			}
+			isSelf = intersect(ips, selfIPs)
+		}
+		if isSelf {
+			return fmt.Errorf(
+				"Seed '%s' is this node's own RPC address. A node can't be"+
+					" its own seed: pass the addresses of the other nodes"+
+					" with --seeds, or leave it empty on the node that"+
+					" starts the cluster",
+				seed,
+			)
+		}
+	}
+	return nil
+}
+
+// selfIPs returns the IPs this node is reachable at for the seeds.
+func (c *seedsChecker) selfIPs(self config.SocketAddress) ([]string, error) {
+	ip := net.ParseIP(self.Address)
+	if ip != nil && !ip.IsUnspecified() {
+		return []string{ip.String()}, nil
+	}
+	if !c.resolve {
+		return nil, nil
+	}
+	if ip == nil {
+		// If this node's address doesn't resolve, the seeds can only be
+		// compared to it by name.
+		ips, err := c.lookupIPs(self.Address)
+		if err != nil {
+			log.Debugf("Couldn't resolve this node's RPC address '%s': %v", self.Address, err)
+		}
+		return ips, nil
+	}
+	addrs, err := c.localAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't list the local addresses: %v", err)
+	}
+	var ips []string
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok {
+			ips = append(ips, n.IP.String())
+		}
+	}
+	return ips, nil
+}
+
+// lookupIPs returns the IPs that host resolves to, normalized so that they
+// can be compared.
+func (c *seedsChecker) lookupIPs(host string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}, nil
+	}
+	addrs, err := c.lookupHost(host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		if ip := net.ParseIP(a); ip != nil {
+			ips = append(ips, ip.String())
+		}
+	}
+	return ips, nil
+}
+
+func intersect(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
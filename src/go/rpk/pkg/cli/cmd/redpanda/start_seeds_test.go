@@ -0,0 +1,109 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+)
+
+func TestSeedsChecker(t *testing.T) {
+	hosts := map[string][]string{
+		"node-0":    {"10.0.0.1"},
+		"node-1":    {"10.0.0.2"},
+		"localhost": {"127.0.0.1", "::1"},
+	}
+	lookupHost := func(host string) ([]string, error) {
+		if addrs, ok := hosts[host]; ok {
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	localAddrs := func() ([]net.Addr, error) {
+		return []net.Addr{
+			&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+			&net.IPNet{IP: net.ParseIP("10.0.0.2"), Mask: net.CIDRMask(24, 32)},
+		}, nil
+	}
+	seed := func(host string, port int) config.SeedServer {
+		return config.SeedServer{Host: config.SocketAddress{Address: host, Port: port}}
+	}
+
+	tests := []struct {
+		name           string
+		resolve        bool
+		seeds          []config.SeedServer
+		self           config.SocketAddress
+		expectedErrMsg string
+	}{
+		{
+			name:    "it should pass if the seeds are other nodes",
+			resolve: true,
+			seeds:   []config.SeedServer{seed("node-0", 33145), seed("10.0.0.3", 33145)},
+			self:    config.SocketAddress{Address: "node-1", Port: 33145},
+		},
+		{
+			name:    "it should pass if a seed is this node's address with another port",
+			resolve: true,
+			seeds:   []config.SeedServer{seed("node-1", 33146)},
+			self:    config.SocketAddress{Address: "node-1", Port: 33145},
+		},
+		{
+			name:    "it should pass if a seed doesn't resolve",
+			resolve: true,
+			seeds:   []config.SeedServer{seed("node-0", 33145), seed("node-9", 33146), seed("node-8", 33145)},
+			self:    config.SocketAddress{Address: "node-1", Port: 33145},
+		},
+		{
+			name:  "it should not resolve the seeds if resolving is disabled",
+			seeds: []config.SeedServer{seed("node-9", 33145)},
+			self:  config.SocketAddress{Address: "node-1", Port: 33145},
+		},
+		{
+			name:           "it should fail if a seed is this node's advertised address",
+			seeds:          []config.SeedServer{seed("node-0", 33145), seed("NODE-1", 33145)},
+			self:           config.SocketAddress{Address: "node-1", Port: 33145},
+			expectedErrMsg: "Seed 'NODE-1:33145' is this node's own RPC address. A node can't be its own seed: pass the addresses of the other nodes with --seeds, or leave it empty on the node that starts the cluster",
+		},
+		{
+			name:           "it should fail if a seed resolves to this node's advertised address",
+			resolve:        true,
+			seeds:          []config.SeedServer{seed("10.0.0.2", 33145)},
+			self:           config.SocketAddress{Address: "node-1", Port: 33145},
+			expectedErrMsg: "Seed '10.0.0.2:33145' is this node's own RPC address. A node can't be its own seed: pass the addresses of the other nodes with --seeds, or leave it empty on the node that starts the cluster",
+		},
+		{
+			name:           "it should fail if a seed is a local address and this node listens on all of them",
+			resolve:        true,
+			seeds:          []config.SeedServer{seed("node-0", 33145), seed("localhost", 33145)},
+			self:           config.SocketAddress{Address: "0.0.0.0", Port: 33145},
+			expectedErrMsg: "Seed 'localhost:33145' is this node's own RPC address. A node can't be its own seed: pass the addresses of the other nodes with --seeds, or leave it empty on the node that starts the cluster",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &seedsChecker{
+				resolve:    tt.resolve,
+				lookupHost: lookupHost,
+				localAddrs: localAddrs,
+			}
+			err := c.check(tt.seeds, tt.self)
+			if tt.expectedErrMsg != "" {
+				require.EqualError(t, err, tt.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
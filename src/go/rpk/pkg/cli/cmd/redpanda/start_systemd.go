@@ -0,0 +1,168 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/systemd"
+)
+
+// systemdStopTimeout is how long redpanda is given to stop after SIGTERM if
+// it doesn't become ready, before it's killed.
+const systemdStopTimeout = 10 * time.Second
+
+// startWithSystemd starts redpanda as a child of rpk, which stays running as
+// the main process of a Type=notify systemd service. It notifies systemd
+// when redpanda is ready and when it's stopping, pings the watchdog if it's
+// enabled, and forwards SIGTERM and SIGINT to redpanda.
+func startWithSystemd(
+	fs afero.Fs,
+	launcher rp.Launcher,
+	installDir string,
+	rpArgs *rp.RedpandaArgs,
+	conf *config.Config,
+	readyTimeout time.Duration,
+) error {
+	n, err := systemd.NotifierFromEnv()
+	if err != nil {
+		return err
+	}
+	watchdog, err := systemd.WatchdogInterval()
+	if err != nil {
+		return err
+	}
+	cl, err := newLocalAdminAPI(fs, conf)
+	if err != nil {
+		return err
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	proc, err := launcher.StartInBackground(installDir, rpArgs, "")
+	if err != nil {
+		return err
+	}
+	return superviseForSystemd(cl, n, proc, signals, readyTimeout, watchdog)
+}
+
+// superviseForSystemd notifies systemd of redpanda's state until it exits.
+// If readyTimeout is positive and redpanda doesn't become ready within it,
+// it's stopped; otherwise systemd's own start timeout applies. Every half of
+// the watchdog interval redpanda's admin API is probed, and the watchdog is
+// only pinged if it answers within a quarter of the interval. The watchdog
+// isn't pinged at all if the interval is 0.
+func superviseForSystemd(
+	cl admin.AdminClient,
+	n *systemd.Notifier,
+	proc *rp.BackgroundProcess,
+	signals <-chan os.Signal,
+	readyTimeout time.Duration,
+	watchdog time.Duration,
+) error {
+	notify := func(states ...string) {
+		err := n.Notify(states...)
+		if err != nil {
+			log.Warnf("Unable to notify systemd: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if readyTimeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), readyTimeout)
+	}
+	defer cancel()
+	ready := make(chan error, 1)
+	go func() { ready <- cl.WaitForReady(ctx, 0) }()
+
+	var ping <-chan time.Time
+	if watchdog > 0 {
+		ticker := time.NewTicker(watchdog / 2)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+	// probed receives the result of the last liveness probe. Only one probe
+	// runs at a time, so a hung admin API doesn't pile them up.
+	probed := make(chan error, 1)
+	probing := false
+
+	notify(fmt.Sprintf("STATUS=Waiting for redpanda (pid %d) to be ready", proc.Pid))
+	stopping := false
+	for {
+		select {
+		case err := <-ready:
+			ready = nil
+			if err == nil {
+				log.Infof("Redpanda is ready (pid %d)", proc.Pid)
+				notify(systemd.Ready, fmt.Sprintf("STATUS=Redpanda is ready (pid %d)", proc.Pid))
+				continue
+			}
+			notify(systemd.Stopping, "STATUS=Redpanda didn't become ready")
+			stopErr := stopProcess(proc.Pid, systemdStopTimeout)
+			if stopErr != nil {
+				log.Warnf("Unable to stop redpanda (pid %d): %v", proc.Pid, stopErr)
+			}
+			return fmt.Errorf("redpanda (pid %d) didn't become ready: %v", proc.Pid, err)
+
+		case <-ping:
+			if probing {
+				continue
+			}
+			probing = true
+			go func() {
+				pctx, pcancel := context.WithTimeout(context.Background(), watchdog/4)
+				defer pcancel()
+				_, err := cl.Ready(pctx)
+				probed <- err
+			}()
+
+		case err := <-probed:
+			probing = false
+			if err != nil {
+				log.Debugf("Not pinging the watchdog, redpanda (pid %d) didn't answer: %v", proc.Pid, err)
+				continue
+			}
+			notify(systemd.Watchdog)
+
+		case sig := <-signals:
+			if !stopping {
+				stopping = true
+				ready = nil
+				notify(systemd.Stopping, "STATUS=Stopping redpanda")
+			}
+			log.Infof("Received %s, forwarding it to redpanda (pid %d)", sig, proc.Pid)
+			err := syscall.Kill(proc.Pid, sig.(syscall.Signal))
+			if err != nil {
+				log.Warnf("Unable to signal redpanda (pid %d): %v", proc.Pid, err)
+			}
+
+		case err := <-proc.Exited:
+			if !stopping {
+				notify(systemd.Stopping)
+			}
+			if err != nil {
+				return fmt.Errorf("redpanda (pid %d) exited: %v", proc.Pid, err)
+			}
+			log.Infof("Redpanda (pid %d) exited", proc.Pid)
+			return nil
+		}
+	}
+}
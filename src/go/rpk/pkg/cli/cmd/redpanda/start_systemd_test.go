@@ -0,0 +1,167 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin/mocks"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/systemd"
+)
+
+// listenNotify listens on a notification socket and returns a notifier for
+// it, and a function that returns the notifications received so far.
+func listenNotify(t *testing.T) (*systemd.Notifier, func() []string) {
+	dir, err := ioutil.TempDir("", "rpk-systemd-*")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram(
+		"unixgram",
+		&net.UnixAddr{Name: socket, Net: "unixgram"},
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	var (
+		mu   sync.Mutex
+		msgs []string
+	)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			msgs = append(msgs, string(buf[:n]))
+			mu.Unlock()
+		}
+	}()
+	received := func() []string {
+		// Give the last notifications time to be read.
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, msgs...)
+	}
+	return systemd.NewNotifier(socket), received
+}
+
+func startProcess(t *testing.T, script string) *rp.BackgroundProcess {
+	cmd := exec.Command("bash", "-c", script)
+	require.NoError(t, cmd.Start())
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	t.Cleanup(func() { cmd.Process.Kill() })
+	// Give bash time to set up its traps.
+	time.Sleep(100 * time.Millisecond)
+	return &rp.BackgroundProcess{Pid: cmd.Process.Pid, Exited: exited}
+}
+
+func TestSuperviseForSystemd(t *testing.T) {
+	t.Run("it should notify readiness and stop on SIGTERM", func(t *testing.T) {
+		n, received := listenNotify(t)
+		proc := startProcess(t, `trap "exit 0" TERM; while :; do sleep 0.05; done`)
+		signals := make(chan os.Signal, 1)
+		ready := make(chan struct{})
+		cl := mocks.MockAdminAPI{MockWaitForReady: func(time.Duration) error {
+			defer close(ready)
+			return nil
+		}}
+		go func() {
+			<-ready
+			time.Sleep(50 * time.Millisecond)
+			signals <- syscall.SIGTERM
+		}()
+
+		err := superviseForSystemd(cl, n, proc, signals, time.Second, 0)
+		require.NoError(t, err)
+		msgs := received()
+		require.Len(t, msgs, 3)
+		require.Contains(t, msgs[0], "STATUS=Waiting for redpanda")
+		require.Contains(t, msgs[1], systemd.Ready)
+		require.Contains(t, msgs[2], systemd.Stopping)
+	})
+
+	t.Run("it should stop redpanda if it isn't ready", func(t *testing.T) {
+		n, received := listenNotify(t)
+		proc := startProcess(t, `while :; do sleep 0.05; done`)
+		cl := mocks.MockAdminAPI{MockWaitForReady: func(time.Duration) error {
+			return errors.New("node did not become ready")
+		}}
+
+		err := superviseForSystemd(cl, n, proc, nil, time.Second, 0)
+		require.EqualError(
+			t,
+			err,
+			"redpanda (pid "+strconv.Itoa(proc.Pid)+") didn't become ready: node did not become ready",
+		)
+		select {
+		case <-proc.Exited:
+		case <-time.After(time.Second):
+			t.Fatal("redpanda wasn't stopped")
+		}
+		msgs := received()
+		require.Len(t, msgs, 2)
+		require.Contains(t, msgs[1], systemd.Stopping)
+		require.NotContains(t, msgs[1], systemd.Ready)
+	})
+
+	t.Run("it should ping the watchdog until redpanda exits", func(t *testing.T) {
+		n, received := listenNotify(t)
+		proc := startProcess(t, `sleep 0.3; exit 3`)
+		blocked := make(chan struct{})
+		defer close(blocked)
+		cl := mocks.MockAdminAPI{
+			MockWaitForReady: func(time.Duration) error {
+				<-blocked
+				return nil
+			},
+			MockReady: func() (bool, error) { return true, nil },
+		}
+
+		err := superviseForSystemd(cl, n, proc, nil, time.Minute, 40*time.Millisecond)
+		require.EqualError(t, err, "redpanda (pid "+strconv.Itoa(proc.Pid)+") exited: exit status 3")
+		msgs := received()
+		require.Greater(t, len(msgs), 2)
+		require.Equal(t, systemd.Watchdog, msgs[1])
+		require.Equal(t, systemd.Stopping, msgs[len(msgs)-1])
+	})
+
+	t.Run("it shouldn't ping the watchdog if the admin API doesn't answer", func(t *testing.T) {
+		n, received := listenNotify(t)
+		proc := startProcess(t, `sleep 0.3; exit 0`)
+		cl := mocks.MockAdminAPI{
+			MockReady: func() (bool, error) {
+				return false, errors.New("connection refused")
+			},
+		}
+
+		err := superviseForSystemd(cl, n, proc, nil, time.Minute, 40*time.Millisecond)
+		require.NoError(t, err)
+		for _, msg := range received() {
+			require.NotEqual(t, systemd.Watchdog, msg)
+		}
+	})
+}
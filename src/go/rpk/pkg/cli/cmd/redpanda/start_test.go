@@ -11,6 +11,7 @@ package redpanda
 
 import (
 	"bytes"
+	"net"
 	"os"
 	"testing"
 
@@ -32,6 +33,13 @@ func (l *noopLauncher) Start(_ string, rpArgs *rp.RedpandaArgs) error {
 	return nil
 }
 
+func (l *noopLauncher) StartInBackground(
+	_ string, rpArgs *rp.RedpandaArgs, _ string,
+) (*rp.BackgroundProcess, error) {
+	l.rpArgs = rpArgs
+	return &rp.BackgroundProcess{Exited: make(chan error)}, nil
+}
+
 func TestMergeFlags(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -127,6 +135,19 @@ func TestParseSeeds(t *testing.T) {
 			arg:            []string{" :1234"},
 			expectedErrMsg: "Couldn't parse seed ' :1234': parse \"// :1234\": invalid character \" \" in host name",
 		},
+		{
+			name: "it should ignore repeated seeds",
+			arg:  []string{"Host-1:1234", "host-1:1234", "host-1", "host-1:33145"},
+			expected: []config.SeedServer{
+				{Host: config.SocketAddress{Address: "Host-1", Port: 1234}},
+				{Host: config.SocketAddress{Address: "host-1", Port: 33145}},
+			},
+		},
+		{
+			name:           "it should fail if the port is out of range",
+			arg:            []string{"host-1:1234", "host-2:65536"},
+			expectedErrMsg: "Couldn't parse seed 'host-2:65536': port 65536 is out of range (1-65535)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1390,8 +1411,84 @@ func TestStartCommand(t *testing.T) {
 			}
 			require.Equal(st, expected, rpArgs.ExtraArgs)
 		},
+	}, {
+		name: "it should set up a single-node dev setup with --mode dev",
+		args: []string{
+			"--install-dir", "/var/lib/redpanda",
+			"--mode", "dev",
+		},
+		before: func(fs afero.Fs) error {
+			conf := config.Default()
+			conf.Redpanda.DeveloperMode = false
+			conf.Rpk.TuneNetwork = true
+			return config.NewManager(fs).Write(conf)
+		},
+		postCheck: func(
+			fs afero.Fs,
+			rpArgs *rp.RedpandaArgs,
+			st *testing.T,
+		) {
+			conf, err := config.NewManager(fs).Read(config.Default().ConfigFile)
+			require.NoError(st, err)
+			require.True(st, conf.Redpanda.DeveloperMode)
+			require.True(st, conf.Rpk.Overprovisioned)
+			require.False(st, conf.Rpk.TuneNetwork)
+			require.Nil(st, conf.Rpk.SMP)
+			require.Equal(st, "1", rpArgs.SeastarFlags["smp"])
+			require.Equal(st, "0M", rpArgs.SeastarFlags["reserve-memory"])
+			require.Equal(st, "true", rpArgs.SeastarFlags["overprovisioned"])
+		},
+	}, {
+		name: "it should keep the flags passed with --mode dev",
+		args: []string{
+			"--install-dir", "/var/lib/redpanda",
+			"--mode", "development",
+			"--smp", "2",
+			"--reserve-memory", "1G",
+		},
+		postCheck: func(
+			_ afero.Fs,
+			rpArgs *rp.RedpandaArgs,
+			st *testing.T,
+		) {
+			require.Equal(st, "2", rpArgs.SeastarFlags["smp"])
+			require.Equal(st, "1G", rpArgs.SeastarFlags["reserve-memory"])
+		},
+	}, {
+		name: "it should fail if --mode is invalid",
+		args: []string{
+			"--install-dir", "/var/lib/redpanda",
+			"--mode", "laptop",
+		},
+		expectedErrMsg: "'laptop' is not a supported mode. Available modes: dev, development, prod, production",
+	}, {
+		name: "it should fail if --smp is more than the CPUs available",
+		args: []string{
+			"--install-dir", "/var/lib/redpanda",
+			"--smp", "8",
+		},
+		before: func(fs afero.Fs) error {
+			return afero.WriteFile(fs, "/sys/devices/system/cpu/online", []byte("0-3\n"), 0644)
+		},
+		expectedErrMsg: "--smp 8 is more than the 4 CPUs available on this host",
+	}, {
+		name: "it should fail if --memory is more than the memory available",
+		args: []string{
+			"--install-dir", "/var/lib/redpanda",
+			"--memory", "4G",
+		},
+		before: func(fs afero.Fs) error {
+			return afero.WriteFile(fs, "/proc/meminfo", []byte("MemTotal: 2097152 kB\n"), 0644)
+		},
+		expectedErrMsg: "--memory 4G is more than the 2GiB of memory available on this host",
 	}}
 
+	// The seeds' hosts aren't looked up in DNS, and as with hosts that
+	// don't resolve, they're only warned about.
+	defer func(lookup func(string) ([]string, error)) { lookupSeedHost = lookup }(lookupSeedHost)
+	lookupSeedHost = func(host string) ([]string, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(st *testing.T) {
 			if tt.after != nil {
@@ -0,0 +1,209 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	vos "github.com/vectorizedio/redpanda/src/go/rpk/pkg/os"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+// nodeStatus is the status of the local redpanda process and, if its admin
+// API can be reached, of its node.
+type nodeStatus struct {
+	Running       bool  `json:"running" yaml:"running"`
+	PID           int   `json:"pid,omitempty" yaml:"pid,omitempty"`
+	UptimeSeconds int64 `json:"uptime_seconds,omitempty" yaml:"uptime_seconds,omitempty"`
+
+	Ready          bool   `json:"ready" yaml:"ready"`
+	NodeID         *int   `json:"node_id,omitempty" yaml:"node_id,omitempty"`
+	Membership     string `json:"membership_status,omitempty" yaml:"membership_status,omitempty"`
+	Alive          *bool  `json:"is_alive,omitempty" yaml:"is_alive,omitempty"`
+	Maintenance    bool   `json:"maintenance" yaml:"maintenance"`
+	ClusterHealthy *bool  `json:"cluster_healthy,omitempty" yaml:"cluster_healthy,omitempty"`
+	NodesDown      []int  `json:"nodes_down,omitempty" yaml:"nodes_down,omitempty"`
+	AdminError     string `json:"admin_error,omitempty" yaml:"admin_error,omitempty"`
+}
+
+func NewStatusCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var (
+		configFile string
+		format     string
+		timeout    time.Duration
+	)
+	command := &cobra.Command{
+		Use:   "status",
+		Short: "Print whether redpanda is running locally, and the status of its node",
+		Long: `Print whether redpanda is running locally, and the status of its node.
+
+Whether the local redpanda process is running is checked with its PID file.
+If it is, its PID and uptime are printed and, if its admin API can be
+reached, whether the node is ready, its ID, membership status and liveness,
+whether it's in maintenance mode, and the health of the cluster.
+
+Use --output json or --output yaml to print the status in a structured
+format. The exit code is 1 if redpanda isn't running.`,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			err := out.CheckFormat(format)
+			if err != nil {
+				return err
+			}
+			if format != out.FormatTable {
+				log.SetOutput(os.Stderr)
+			}
+			conf, err := mgr.ReadOrFind(configFile)
+			if err != nil {
+				return err
+			}
+			pid, running, err := runningPID(fs, conf)
+			if err != nil {
+				return err
+			}
+			st := processStatus(fs, pid, running)
+			if running {
+				cl, err := newLocalAdminAPI(fs, conf)
+				if err != nil {
+					st.AdminError = err.Error()
+				} else {
+					ctx, cancel := context.WithTimeout(context.Background(), timeout)
+					defer cancel()
+					adminStatus(ctx, cl, &st)
+				}
+			}
+			if format != out.FormatTable {
+				err = out.PrintStructured(format, st)
+			} else {
+				printStatus(st)
+			}
+			if err != nil {
+				return err
+			}
+			if !st.Running {
+				return &out.ExitCodeError{Code: 1, Msg: "redpanda isn't running"}
+			}
+			return nil
+		},
+	}
+	command.Flags().StringVar(
+		&configFile,
+		"config",
+		"",
+		"Redpanda config file, if not set the file will be searched for"+
+			" in the default locations",
+	)
+	command.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	command.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		5*time.Second,
+		"The maximum amount of time to wait for the admin API to respond",
+	)
+	return command
+}
+
+// processStatus returns the status of the local redpanda process.
+func processStatus(fs afero.Fs, pid int, running bool) nodeStatus {
+	st := nodeStatus{Running: running}
+	if !running {
+		return st
+	}
+	st.PID = pid
+	uptime, err := vos.ProcessUptime(fs, pid)
+	if err != nil {
+		log.Debugf("Unable to get the uptime of redpanda (PID %d): %v", pid, err)
+	} else {
+		st.UptimeSeconds = int64(uptime / time.Second)
+	}
+	return st
+}
+
+// adminStatus fills in the status of the node from its admin API. If the API
+// can't be reached, the error is saved in the status.
+func adminStatus(ctx context.Context, cl admin.AdminClient, st *nodeStatus) {
+	ready, err := cl.Ready(ctx)
+	if err != nil {
+		st.AdminError = err.Error()
+		return
+	}
+	st.Ready = ready
+	id, err := cl.NodeID(ctx)
+	if err != nil {
+		st.AdminError = err.Error()
+		return
+	}
+	st.NodeID = &id
+	b, err := cl.Broker(ctx, id)
+	if err != nil {
+		st.AdminError = err.Error()
+		return
+	}
+	st.Membership = string(b.MembershipStatus)
+	st.Alive = b.IsAlive
+	st.Maintenance = b.Maintenance != nil && b.Maintenance.Draining
+	h, err := cl.ClusterHealth(ctx)
+	if err != nil {
+		st.AdminError = err.Error()
+		return
+	}
+	st.ClusterHealthy = &h.IsHealthy
+	st.NodesDown = h.NodesDown
+}
+
+func printStatus(st nodeStatus) {
+	tw := out.NewTabWriter()
+	defer tw.Flush()
+	tw.Print("RUNNING", st.Running)
+	if !st.Running {
+		return
+	}
+	tw.Print("PID", st.PID)
+	tw.Print("UPTIME", time.Duration(st.UptimeSeconds)*time.Second)
+	if st.AdminError != "" {
+		tw.Print("ADMIN API", "unreachable: "+st.AdminError)
+	}
+	tw.Print("READY", st.Ready)
+	if st.NodeID != nil {
+		tw.Print("NODE ID", *st.NodeID)
+	}
+	if st.Membership != "" {
+		tw.Print("MEMBERSHIP", st.Membership)
+	}
+	if st.Alive != nil {
+		tw.Print("ALIVE", *st.Alive)
+	}
+	if st.NodeID != nil {
+		tw.Print("MAINTENANCE", st.Maintenance)
+	}
+	if st.ClusterHealthy != nil {
+		health := strconv.FormatBool(*st.ClusterHealthy)
+		if len(st.NodesDown) > 0 {
+			health += fmt.Sprintf(" (nodes down: %v)", st.NodesDown)
+		}
+		tw.Print("CLUSTER HEALTHY", health)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin/mocks"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestProcessStatus(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	stat := "4321 (redpanda) S 1 1 1 0 -1 4194560 115854 29631806 115 956 443 1316 612807 129163 20 0 1 0 4550 175927296 3830"
+	require.NoError(t, afero.WriteFile(fs, "/proc/4321/stat", []byte(stat), 0444))
+	require.NoError(t, afero.WriteFile(fs, "/proc/uptime", []byte("100.50 350.12"), 0444))
+
+	require.Equal(t, nodeStatus{}, processStatus(fs, 0, false))
+	require.Equal(
+		t,
+		nodeStatus{Running: true, PID: 4321, UptimeSeconds: 55},
+		processStatus(fs, 4321, true),
+	)
+}
+
+func TestAdminStatus(t *testing.T) {
+	alive := true
+	healthy := false
+	tests := []struct {
+		name     string
+		cl       admin.AdminClient
+		expected nodeStatus
+	}{
+		{
+			name: "it should return the status of the node and cluster",
+			cl: mocks.MockAdminAPI{
+				MockReady:  func() (bool, error) { return true, nil },
+				MockNodeID: func() (int, error) { return 2, nil },
+				MockBroker: func(node int) (admin.Broker, error) {
+					require.Equal(t, 2, node)
+					return admin.Broker{
+						NodeID:           2,
+						MembershipStatus: admin.MembershipActive,
+						IsAlive:          &alive,
+						Maintenance:      &admin.MaintenanceStatus{Draining: true},
+					}, nil
+				},
+				MockClusterHealth: func() (admin.ClusterHealth, error) {
+					return admin.ClusterHealth{IsHealthy: false, NodesDown: []int{1}}, nil
+				},
+			},
+			expected: nodeStatus{
+				Ready:          true,
+				NodeID:         intPtr(2),
+				Membership:     "active",
+				Alive:          &alive,
+				Maintenance:    true,
+				ClusterHealthy: &healthy,
+				NodesDown:      []int{1},
+			},
+		},
+		{
+			name: "it should save the error if the admin API can't be reached",
+			cl: mocks.MockAdminAPI{
+				MockReady: func() (bool, error) {
+					return false, errors.New("connection refused")
+				},
+			},
+			expected: nodeStatus{AdminError: "connection refused"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var st nodeStatus
+			adminStatus(context.Background(), tt.cl, &st)
+			require.Equal(t, tt.expected, st)
+		})
+	}
+}
+
+func TestStatusJSON(t *testing.T) {
+	st := nodeStatus{Running: true, PID: 10, UptimeSeconds: 3, NodeID: intPtr(0)}
+	bs, err := out.Structured(out.FormatJSON, st)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "running": true,
+  "pid": 10,
+  "uptime_seconds": 3,
+  "ready": false,
+  "node_id": 0,
+  "maintenance": false
+}`, string(bs))
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -10,7 +10,9 @@
 package redpanda
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"syscall"
 	"time"
@@ -18,26 +20,45 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/os"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/utils"
 )
 
+// drainPoll is how often the maintenance status is polled while draining.
+const drainPoll = time.Second
+
 func NewStopCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 	var (
-		configFile string
-		timeout    time.Duration
+		configFile   string
+		timeout      time.Duration
+		drain        bool
+		drainTimeout time.Duration
 	)
 	command := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop redpanda.",
 		Long: `Stop a local redpanda process. 'rpk stop'
-first sends SIGINT, and waits for the specified timeout. Then, if redpanda
-hasn't stopped, it sends SIGTERM. Lastly, it sends SIGKILL if it's still
-running.`,
+first sends SIGTERM, so that redpanda shuts down gracefully, and waits for
+the specified timeout. Then, if redpanda hasn't stopped, it's killed with
+SIGKILL.
+
+With --drain, the node is put into maintenance mode before it's stopped, and
+rpk waits for up to --drain-timeout for leadership of its partitions to be
+transferred to other nodes. The node stays in maintenance mode after it's
+restarted, until it's disabled with
+'rpk redpanda admin brokers maintenance disable'.
+
+The exit code is:
+
+  0  if redpanda stopped gracefully, or wasn't running
+  1  if redpanda couldn't be stopped
+  2  if redpanda didn't stop within the timeout and was killed`,
 		SilenceUsage: true,
 		RunE: func(ccmd *cobra.Command, args []string) error {
-			return executeStop(fs, mgr, configFile, timeout)
+			return executeStop(fs, mgr, configFile, timeout, drain, drainTimeout)
 		},
 	}
 	command.Flags().StringVar(
@@ -50,24 +71,56 @@ running.`,
 	command.Flags().DurationVar(
 		&timeout,
 		"timeout",
-		5*time.Second,
-		"The maximum amount of time to wait for redpanda to stop,"+
-			"after each signal is sent. The value passed is a"+
-			"sequence of decimal numbers, each with optional"+
-			" fraction and a unit suffix, such as '300ms', '1.5s'"+
-			" or '2h45m'. Valid time units are 'ns', 'us' (or"+
+		10*time.Second,
+		"The maximum amount of time to wait for redpanda to stop"+
+			" after SIGTERM is sent, before killing it. The value"+
+			" passed is a sequence of decimal numbers, each with"+
+			" optional fraction and a unit suffix, such as '300ms',"+
+			" '1.5s' or '2h45m'. Valid time units are 'ns', 'us' (or"+
 			" 'µs'), 'ms', 's', 'm', 'h'",
 	)
+	command.Flags().BoolVar(
+		&drain,
+		"drain",
+		false,
+		"Put the node into maintenance mode to drain its partitions'"+
+			" leadership before stopping it",
+	)
+	command.Flags().DurationVar(
+		&drainTimeout,
+		"drain-timeout",
+		time.Minute,
+		"The maximum amount of time to wait for the node to be drained"+
+			" with --drain, after which it's stopped anyway",
+	)
 	return command
 }
 
 func executeStop(
-	fs afero.Fs, mgr config.Manager, configFile string, timeout time.Duration,
+	fs afero.Fs,
+	mgr config.Manager,
+	configFile string,
+	timeout time.Duration,
+	drain bool,
+	drainTimeout time.Duration,
 ) error {
 	conf, err := mgr.ReadOrFind(configFile)
 	if err != nil {
 		return err
 	}
+	pid, running, err := runningPID(fs, conf)
+	if err != nil || !running {
+		return err
+	}
+	if drain {
+		drainBeforeStop(fs, conf, drainTimeout)
+	}
+	return stopProcess(pid, timeout)
+}
+
+// runningPID returns the PID of the local redpanda process, and whether it's
+// running.
+func runningPID(fs afero.Fs, conf *config.Config) (int, bool, error) {
 	pidFile := conf.PIDFile()
 	isLocked, err := os.CheckLocked(pidFile)
 	if err != nil {
@@ -80,55 +133,123 @@ func executeStop(
 			"'%s' isn't locked, which means redpanda isn't running. Nothing to do.",
 			pidFile,
 		)
-		return nil
+		return 0, false, nil
 	}
 	pidStr, err := utils.ReadEnsureSingleLine(fs, pidFile)
 	if err != nil {
-		return err
+		return 0, false, err
 	}
 	pid, err := strconv.Atoi(pidStr)
 	if err != nil {
-		return err
+		return 0, false, err
 	}
-	return signalAndWait(fs, pid, timeout)
+	return pid, true, nil
 }
 
-func signalAndWait(fs afero.Fs, pid int, timeout time.Duration) error {
-	var f func(int, []syscall.Signal) error
-	f = func(pid int, signals []syscall.Signal) error {
-		if len(signals) == 0 {
-			return errors.New("process couldn't be terminated.")
-		}
-		signal := signals[0]
-		pending := signals[1:]
-		log.Debugf(
-			"Sending %s to redpanda (PID %d).\n",
-			signal,
-			pid,
-		)
-		err := syscall.Kill(pid, signal)
-		if err != nil {
-			return err
-		}
-		stopPolling := make(chan bool)
-		stoppedRunning := make(chan bool)
-		go poll(pid, stopPolling, stoppedRunning)
+// drainBeforeStop drains the local node, warning if it can't be drained,
+// since it's stopped anyway.
+func drainBeforeStop(fs afero.Fs, conf *config.Config, timeout time.Duration) {
+	cl, err := newLocalAdminAPI(fs, conf)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err = drainNode(ctx, cl, conf.Redpanda.Id, drainPoll)
+	}
+	if err != nil {
+		log.Warnf("Stopping redpanda without draining it: %v", err)
+	}
+}
 
-		timedOut := false
-		select {
-		case <-time.After(timeout):
-			stopPolling <- true
-			timedOut = true
-		case <-stoppedRunning:
+// drainNode puts the node into maintenance mode and waits until leadership
+// of all of its partitions has been transferred, or ctx is done.
+func drainNode(
+	ctx context.Context, cl admin.AdminClient, node int, poll time.Duration,
+) error {
+	log.Infof("Putting node %d into maintenance mode...", node)
+	err := cl.EnableMaintenanceMode(ctx, node)
+	if err != nil {
+		return fmt.Errorf("unable to enable maintenance mode: %v", err)
+	}
+	for {
+		s, err := cl.MaintenanceStatus(ctx, node)
+		switch {
+		case err != nil:
+			return fmt.Errorf("unable to get the maintenance status: %v", err)
+		case s.Finished && s.Errors:
+			return fmt.Errorf("leadership of %d partitions couldn't be transferred", s.Failed)
+		case s.Finished:
+			log.Infof("Node %d is drained.", node)
+			return nil
 		}
-		close(stopPolling)
-		close(stoppedRunning)
-		if timedOut {
-			return f(pid, pending)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"node %d wasn't drained in time, %d partitions are still being transferred",
+				node, s.Transferring,
+			)
+		case <-time.After(poll):
 		}
+	}
+}
+
+// stopProcess sends SIGTERM to the process and waits for it to stop for up
+// to timeout, after which it's killed with SIGKILL. If it had to be killed,
+// the error has exit code 2.
+func stopProcess(pid int, timeout time.Duration) error {
+	stopped, err := signalAndWait(pid, syscall.SIGTERM, timeout)
+	if err != nil {
+		return err
+	}
+	if stopped {
+		log.Infof("Redpanda (PID %d) stopped gracefully.", pid)
 		return nil
 	}
-	return f(pid, []syscall.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL})
+	log.Warnf(
+		"Redpanda (PID %d) didn't stop within %s of SIGTERM, killing it.",
+		pid,
+		timeout,
+	)
+	stopped, err = signalAndWait(pid, syscall.SIGKILL, timeout)
+	if err != nil {
+		return err
+	}
+	if !stopped {
+		return errors.New("process couldn't be terminated.")
+	}
+	return &out.ExitCodeError{
+		Code: 2,
+		Msg:  fmt.Sprintf("redpanda (PID %d) was killed with SIGKILL", pid),
+	}
+}
+
+// signalAndWait sends the signal to the process and returns whether it
+// stopped running within the timeout.
+func signalAndWait(
+	pid int, signal syscall.Signal, timeout time.Duration,
+) (bool, error) {
+	log.Debugf(
+		"Sending %s to redpanda (PID %d).\n",
+		signal,
+		pid,
+	)
+	err := syscall.Kill(pid, signal)
+	if err != nil {
+		return false, err
+	}
+	stopPolling := make(chan bool)
+	stoppedRunning := make(chan bool)
+	go poll(pid, stopPolling, stoppedRunning)
+
+	timedOut := false
+	select {
+	case <-time.After(timeout):
+		stopPolling <- true
+		timedOut = true
+	case <-stoppedRunning:
+	}
+	close(stopPolling)
+	close(stoppedRunning)
+	return !timedOut, nil
 }
 
 func poll(pid int, stop <-chan bool, stoppedRunning chan<- bool) {
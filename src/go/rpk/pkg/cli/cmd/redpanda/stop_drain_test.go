@@ -0,0 +1,132 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/api/admin/mocks"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+)
+
+func TestDrainNode(t *testing.T) {
+	tests := []struct {
+		name       string
+		enableErr  error
+		statuses   []admin.MaintenanceStatus
+		expErr     string
+		expPolls   int
+		ctxTimeout time.Duration
+	}{
+		{
+			name: "it should wait until the node is drained",
+			statuses: []admin.MaintenanceStatus{
+				{},
+				{Draining: true, Transferring: 2},
+				{Draining: true, Finished: true},
+			},
+			expPolls: 3,
+		},
+		{
+			name:      "it should fail if maintenance mode can't be enabled",
+			enableErr: errors.New("no controller"),
+			expErr:    "unable to enable maintenance mode: no controller",
+		},
+		{
+			name: "it should fail if some leadership transfers failed",
+			statuses: []admin.MaintenanceStatus{
+				{Draining: true, Finished: true, Errors: true, Failed: 3},
+			},
+			expErr:   "leadership of 3 partitions couldn't be transferred",
+			expPolls: 1,
+		},
+		{
+			name: "it should fail if the node isn't drained in time",
+			statuses: []admin.MaintenanceStatus{
+				{Draining: true, Transferring: 5},
+			},
+			ctxTimeout: 20 * time.Millisecond,
+			expErr:     "node 1 wasn't drained in time, 5 partitions are still being transferred",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			cl := mocks.MockAdminAPI{
+				MockEnableMaintenanceMode: func(node int) error {
+					require.Equal(t, 1, node)
+					return tt.enableErr
+				},
+				MockMaintenanceStatus: func(node int) (admin.MaintenanceStatus, error) {
+					s := tt.statuses[len(tt.statuses)-1]
+					if polls < len(tt.statuses) {
+						s = tt.statuses[polls]
+					}
+					polls++
+					return s, nil
+				},
+			}
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+			err := drainNode(ctx, cl, 1, time.Millisecond)
+			if tt.expErr != "" {
+				require.EqualError(t, err, tt.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.expPolls > 0 {
+				require.Equal(t, tt.expPolls, polls)
+			}
+		})
+	}
+}
+
+func TestStopProcess(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		expCode int
+	}{
+		{
+			name:    "it should stop the process gracefully with SIGTERM",
+			command: []string{"sleep", "100"},
+			expCode: 0,
+		},
+		{
+			name:    "it should kill the process with SIGKILL if it ignores SIGTERM",
+			command: []string{"bash", "-c", `trap "" TERM; while :; do sleep 1; done`},
+			expCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(tt.command[0], tt.command[1:]...)
+			require.NoError(t, cmd.Start())
+			// Reap the process once it's stopped, so that it isn't
+			// left as a zombie.
+			go cmd.Wait()
+			// Give bash time to set up the trap.
+			time.Sleep(100 * time.Millisecond)
+
+			err := stopProcess(cmd.Process.Pid, 200*time.Millisecond)
+			require.Equal(t, tt.expCode, out.ExitCode(err))
+		})
+	}
+}
@@ -34,19 +34,14 @@ func TestStopCommand(t *testing.T) {
 		args           []string
 	}{
 		{
-			name: "it should stop redpanda on SIGINT",
+			name: "it should stop redpanda on SIGTERM",
 			args: []string{"--timeout", "100ms"},
 		},
 		{
-			name:           "it should stop redpanda on SIGTERM if SIGINT was ignored",
+			name:           "it should stop redpanda on SIGTERM if SIGINT is ignored",
 			ignoredSignals: []string{"INT"},
 			args:           []string{"--timeout", "100ms"},
 		},
-		{
-			name:           "it should stop redpanda on SIGKILL if SIGINT and SIGTERM were ignored",
-			ignoredSignals: []string{"TERM", "INT"},
-			args:           []string{"--timeout", "100ms"},
-		},
 	}
 
 	for _, tt := range tests {
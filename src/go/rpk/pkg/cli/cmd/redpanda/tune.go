@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,16 +28,22 @@ import (
 	tunecmd "github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/redpanda/tune"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/ui"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/factory"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/hwloc"
 )
 
 type result struct {
-	name      string
-	applied   bool
-	enabled   bool
-	supported bool
-	errMsg    string
+	name           string
+	applied        bool
+	enabled        bool
+	supported      bool
+	errMsg         string
+	rebootRequired bool
+	changes        []executors.PlannedChange
 }
 
 func NewTuneCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
@@ -47,6 +54,10 @@ func NewTuneCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 		cpuSet            string
 		timeout           time.Duration
 		interactive       bool
+		dryRun            bool
+		format            string
+		reportFile        string
+		reportFormat      string
 	)
 	baseMsg := "Sets the OS parameters to tune system performance." +
 		" Available tuners: all, " +
@@ -77,6 +88,28 @@ func NewTuneCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
 			if !tunerParamsEmpty(&tunerParams) && configFile != "" {
 				return errors.New("Use either tuner params or redpanda config file")
 			}
+			err := out.CheckFormat(format)
+			if err != nil {
+				return err
+			}
+			if dryRun && outTuneScriptFile != "" {
+				return errors.New("--dry-run and --output-script can't be used together")
+			}
+			if !dryRun && format != out.FormatTable {
+				return errors.New("--output is only supported with --dry-run")
+			}
+			if reportFile != "" && (dryRun || outTuneScriptFile != "") {
+				return errors.New("--report can't be used with --dry-run or --output-script")
+			}
+			err = checkReportFormat(reportFormat)
+			if err != nil {
+				return err
+			}
+			if format != out.FormatTable {
+				// The tuners log what they do, which would mix with
+				// the structured output in stdout.
+				log.SetOutput(os.Stderr)
+			}
 			var tuners []string
 			if args[0] == "all" {
 				tuners = factory.AvailableTuners()
@@ -107,15 +140,25 @@ Would you like to continue with the default configuration?`,
 				}
 				conf = config.Default()
 			}
-			var tunerFactory factory.TunersFactory
+			if dryRun {
+				executor := executors.NewDryRunExecutor()
+				tunerFactory := factory.NewDryRunTunersFactory(
+					fs, *conf, executor, timeout)
+				return dryRunTune(conf, tuners, tunerFactory, executor, &tunerParams, format)
+			}
+			var (
+				tunerFactory factory.TunersFactory
+				recorder     *executors.RecordingExecutor
+			)
 			if outTuneScriptFile != "" {
 				tunerFactory = factory.NewScriptRenderingTunersFactory(
 					fs, *conf, outTuneScriptFile, timeout)
 			} else {
-				tunerFactory = factory.NewDirectExecutorTunersFactory(
-					fs, *conf, timeout)
+				recorder = executors.NewRecordingExecutor()
+				tunerFactory = factory.NewRecordingTunersFactory(
+					fs, *conf, recorder, timeout)
 			}
-			return tune(fs, conf, tuners, tunerFactory, &tunerParams)
+			return tune(fs, conf, tuners, tunerFactory, recorder, &tunerParams, reportFile, reportFormat)
 		},
 	}
 	command.Flags().StringVarP(&tunerParams.Mode,
@@ -165,7 +208,36 @@ Would you like to continue with the default configuration?`,
 		"Ask for confirmation on every step (e.g. tuner execution,"+
 			" configuration generation)",
 	)
-	command.AddCommand(tunecmd.NewHelpCommand())
+	command.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"Print what the tuners would change, without changing anything",
+	)
+	command.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format of --dry-run: table, json, or yaml",
+	)
+	command.Flags().StringVar(
+		&reportFile,
+		"report",
+		"",
+		"Write a report of what each tuner changed to this file, including the tuners that failed",
+	)
+	command.Flags().StringVar(
+		&reportFormat,
+		"report-format",
+		out.FormatJSON,
+		"Format of the --report: json, yaml, or text",
+	)
+	command.AddCommand(
+		tunecmd.NewHelpCommand(),
+		tunecmd.NewListCommand(fs, mgr),
+		tunecmd.NewRevertCommand(fs, mgr),
+	)
 	return command
 }
 
@@ -204,12 +276,24 @@ func tune(
 	conf *config.Config,
 	tunerNames []string,
 	tunersFactory factory.TunersFactory,
+	recorder *executors.RecordingExecutor,
 	params *factory.TunerParams,
+	reportFile, reportFormat string,
 ) error {
 	params, err := factory.MergeTunerParamsConfig(params, conf)
 	if err != nil {
 		return err
 	}
+	// The changes are only recorded if the tuners are run directly, rather
+	// than rendered to a script.
+	statePath := rp.GetTunerStatePath(filepath.Dir(conf.ConfigFile))
+	var state tuners.TunerState
+	if recorder != nil {
+		state, err = tuners.ReadTunerState(fs, statePath)
+		if err != nil {
+			return err
+		}
+	}
 	rebootRequired := false
 
 	results := []result{}
@@ -222,18 +306,36 @@ func tune(
 		supported, reason := tuner.CheckIfSupported()
 		if !enabled || !supported {
 			includeErr = includeErr || !supported
-			results = append(results, result{tunerName, false, enabled, supported, reason})
+			results = append(results, result{
+				name:      tunerName,
+				enabled:   enabled,
+				supported: supported,
+				errMsg:    reason,
+			})
 			continue
 		}
 		log.Debugf("Tuner parameters %+v", params)
 		res := tuner.Tune()
+		var changes []executors.PlannedChange
+		if recorder != nil {
+			state.Record(tunerName, recorder.States())
+			changes = recorder.Changes()
+		}
 		includeErr = includeErr || res.IsFailed()
 		rebootRequired = rebootRequired || res.IsRebootRequired()
 		errMsg := ""
 		if res.IsFailed() {
 			errMsg = res.Error().Error()
 		}
-		results = append(results, result{tunerName, !res.IsFailed(), enabled, supported, errMsg})
+		results = append(results, result{
+			name:           tunerName,
+			applied:        !res.IsFailed(),
+			enabled:        enabled,
+			supported:      supported,
+			errMsg:         errMsg,
+			rebootRequired: res.IsRebootRequired(),
+			changes:        changes,
+		})
 	}
 
 	if allDisabled {
@@ -244,6 +346,22 @@ func tune(
 		)
 	}
 
+	var stateErr error
+	if recorder != nil {
+		stateErr = tuners.WriteTunerState(fs, statePath, state)
+	}
+	// The report documents the changes that were made even if they can't
+	// be reverted because the state couldn't be saved.
+	if reportFile != "" {
+		err = writeTuneReport(fs, reportFile, reportFormat, newTuneReport(results, time.Now()))
+		if err != nil {
+			return fmt.Errorf("unable to write the tune report to %s: %v", reportFile, err)
+		}
+	}
+	if stateErr != nil {
+		return fmt.Errorf("unable to save the tuner state to %s: %v", statePath, stateErr)
+	}
+
 	printTuneResult(results, includeErr)
 
 	if rebootRequired {
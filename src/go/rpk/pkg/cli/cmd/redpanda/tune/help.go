@@ -167,7 +167,9 @@ If there isn't any mode given script will use a default mode:
 
 const swappinessTunerHelp = `
 Tunes the kernel to keep process data in-memory for as long as possible, instead
-of swapping it out to disk.
+of swapping it out to disk, by setting vm.swappiness to the value of
+rpk.swappiness in the config, or to 1 if it isn't set. If no swap is enabled
+there is nothing to tune, and the tuner does nothing.
 `
 
 const fstrimTunerHelp = `
@@ -192,10 +194,13 @@ simultaneous IO requests as possible, increasing throughput.
 `
 
 const transparentHugepagesTunerHelp = `
-Enables Transparent Hugepages. This allows the kernel to index larger pages
-(2MB, as opposed to the standard 4KB) in the CPU's TLB (if it supports it, which
-is the case for most current CPUs). This results in fewer cache misses, which
-means less time is spent searching and loading pages.
+Sets the Transparent Hugepages enabled mode to rpk.transparent_hugepages_enabled
+('madvise' by default) and its defrag mode to rpk.transparent_hugepages_defrag
+('never' by default). With 'madvise', the kernel only uses larger pages (2MB, as
+opposed to the standard 4KB) for the memory regions that ask for them, which
+results in fewer TLB misses without the latency spikes that having the kernel
+compact memory for every process can cause. Kernels that don't have the defrag
+setting only get the enabled mode set.
 `
 
 const clocksourceTunerHelp = `
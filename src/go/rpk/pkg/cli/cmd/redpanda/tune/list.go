@@ -0,0 +1,124 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tune
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/factory"
+)
+
+// tunerInfo describes a tuner and whether it would run on this host.
+type tunerInfo struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Enabled     bool   `json:"enabled" yaml:"enabled"`
+	Supported   bool   `json:"supported" yaml:"supported"`
+	Reason      string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+func NewListCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var (
+		configFile string
+		format     string
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the available tuners and whether they apply to this host",
+		Long: `List the available tuners and whether they apply to this host.
+
+A tuner is enabled if it is turned on in the rpk section of the config, and
+supported if this host allows it to run. For unsupported tuners, the reason
+column explains why. Use --output json or --output yaml to print the list in a
+structured format.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(*cobra.Command, []string) {
+			err := out.CheckFormat(format)
+			out.MaybeDieErr(err)
+
+			conf, err := mgr.FindOrGenerate(configFile)
+			out.MaybeDie(err, "unable to load configuration: %v", err)
+
+			infos, err := listTuners(
+				conf,
+				factory.NewDirectExecutorTunersFactory(fs, *conf, 10*time.Second),
+			)
+			out.MaybeDie(err, "unable to list the tuners: %v", err)
+			if format != out.FormatTable {
+				out.MaybeDieErr(out.PrintStructured(format, infos))
+				return
+			}
+			t := out.NewTable("tuner", "enabled", "supported", "description", "reason")
+			defer t.Flush()
+			for _, i := range infos {
+				t.PrintStrings(
+					i.Name,
+					strconv.FormatBool(i.Enabled),
+					strconv.FormatBool(i.Supported),
+					i.Description,
+					i.Reason,
+				)
+			}
+		},
+	}
+	cmd.Flags().StringVar(
+		&configFile,
+		"config",
+		"",
+		"Redpanda config file, if not set the file will be searched for"+
+			" in the default locations",
+	)
+	cmd.Flags().StringVarP(
+		&format,
+		"output",
+		"o",
+		out.FormatTable,
+		"Output format: table, json, or yaml",
+	)
+	return cmd
+}
+
+// listTuners returns the available tuners sorted by name, checking with the
+// tuners created by tf whether they are supported on this host.
+func listTuners(
+	conf *config.Config, tf factory.TunersFactory,
+) ([]tunerInfo, error) {
+	names := factory.AvailableTuners()
+	sort.Strings(names)
+	params, err := factory.MergeTunerParamsConfig(
+		&factory.TunerParams{CpuMask: "all"},
+		conf,
+	)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]tunerInfo, 0, len(names))
+	for _, name := range names {
+		supported, reason := tf.CreateTuner(name, params).CheckIfSupported()
+		if supported {
+			reason = ""
+		}
+		infos = append(infos, tunerInfo{
+			Name:        name,
+			Description: factory.TunerDescription(name),
+			Enabled:     factory.IsTunerEnabled(name, conf.Rpk),
+			Supported:   supported,
+			Reason:      reason,
+		})
+	}
+	return infos, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tune
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/factory"
+)
+
+type fakeTuner struct {
+	supported bool
+	reason    string
+}
+
+func (t fakeTuner) CheckIfSupported() (bool, string) {
+	return t.supported, t.reason
+}
+
+func (fakeTuner) Tune() tuners.TuneResult {
+	return tuners.NewTuneResult(false)
+}
+
+type fakeFactory map[string]fakeTuner
+
+func (f fakeFactory) CreateTuner(
+	name string, _ *factory.TunerParams,
+) tuners.Tunable {
+	if t, ok := f[name]; ok {
+		return t
+	}
+	return fakeTuner{supported: true}
+}
+
+func TestListTuners(t *testing.T) {
+	conf := config.Default()
+	conf.Rpk.TuneSwappiness = true
+	tf := fakeFactory{
+		"disk_write_cache": {reason: "Disk write cache tuner is only supported in GCP"},
+		"swappiness":       {supported: true, reason: "ignored"},
+	}
+
+	infos, err := listTuners(conf, tf)
+	require.NoError(t, err)
+	require.Len(t, infos, len(factory.AvailableTuners()))
+	for i := 1; i < len(infos); i++ {
+		require.Less(t, infos[i-1].Name, infos[i].Name)
+	}
+	for _, info := range infos {
+		require.NotEmpty(t, info.Description, info.Name)
+		switch info.Name {
+		case "disk_write_cache":
+			require.False(t, info.Supported)
+			require.Equal(t, "Disk write cache tuner is only supported in GCP", info.Reason)
+		case "swappiness":
+			require.True(t, info.Enabled)
+			require.True(t, info.Supported)
+			require.Empty(t, info.Reason)
+		default:
+			require.False(t, info.Enabled, info.Name)
+			require.True(t, info.Supported, info.Name)
+		}
+	}
+}
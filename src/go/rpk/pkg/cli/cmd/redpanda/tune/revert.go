@@ -0,0 +1,149 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tune
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	rp "github.com/vectorizedio/redpanda/src/go/rpk/pkg/redpanda"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/factory"
+)
+
+type revertResult struct {
+	tuner    string
+	reverted bool
+	errMsg   string
+}
+
+func NewRevertCommand(fs afero.Fs, mgr config.Manager) *cobra.Command {
+	var configFile string
+	cmd := &cobra.Command{
+		Use:   "revert [tuner...]",
+		Short: "Restore the settings that the tuners changed",
+		Long: `Restore the settings that the tuners changed.
+
+When rpk redpanda tune runs the tuners, it saves the values of the files,
+sysctl keys and NIC features that they change, from before the first time they
+changed them, in tuner-state.json next to the config file. This command
+restores those values for the given tuners, or for all the tuners with saved
+values if none are given.
+
+Changes that can't be restored by writing back a value, such as installed
+systemd units and regenerated boot loader configs, aren't reverted. Tuners
+that have no saved values, because they didn't run or were already reverted,
+are reported as such.`,
+		Args: func(_ *cobra.Command, args []string) error {
+			for _, tuner := range args {
+				if !factory.IsTunerAvailable(tuner) {
+					return fmt.Errorf("invalid tuner '%s', only %s are supported",
+						tuner, factory.AvailableTuners())
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			conf, err := mgr.FindOrGenerate(configFile)
+			if err != nil {
+				return err
+			}
+			path := rp.GetTunerStatePath(filepath.Dir(conf.ConfigFile))
+			state, err := tuners.ReadTunerState(fs, path)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 && len(state) == 0 {
+				return fmt.Errorf("there are no saved tuner changes in %s, nothing to revert", path)
+			}
+			results := revert(fs, state, args)
+			err = tuners.WriteTunerState(fs, path, state)
+			if err != nil {
+				return fmt.Errorf("unable to save the tuner state to %s: %v", path, err)
+			}
+
+			failed := 0
+			t := out.NewTable("tuner", "reverted", "error")
+			defer t.Flush()
+			for _, res := range results {
+				if !res.reverted {
+					failed++
+				}
+				t.PrintStrings(res.tuner, strconv.FormatBool(res.reverted), res.errMsg)
+			}
+			if failed > 0 {
+				return fmt.Errorf("unable to revert %d tuner(s)", failed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&configFile,
+		"config",
+		"",
+		"Redpanda config file, if not set the file will be searched for"+
+			" in the default locations",
+	)
+	return cmd
+}
+
+// revert restores the saved state of the tuners, or of all the tuners in the
+// state if there are none, removing the tuners it reverted from the state.
+// The settings of each tuner are restored in the reverse order to which they
+// were changed.
+func revert(fs afero.Fs, state tuners.TunerState, tunerNames []string) []revertResult {
+	if len(tunerNames) == 0 {
+		for tuner := range state {
+			tunerNames = append(tunerNames, tuner)
+		}
+	}
+	sort.Strings(tunerNames)
+	var results []revertResult
+	for _, tuner := range tunerNames {
+		states, ok := state[tuner]
+		if !ok {
+			results = append(results, revertResult{
+				tuner:  tuner,
+				errMsg: "no saved state, the tuner didn't run or was already reverted",
+			})
+			continue
+		}
+		res := revertResult{tuner: tuner, reverted: true}
+		for i := len(states) - 1; i >= 0; i-- {
+			err := restore(fs, states[i])
+			if err != nil {
+				res.reverted = false
+				res.errMsg = fmt.Sprintf("unable to restore %s: %v", states[i].Setting, err)
+				break
+			}
+		}
+		if res.reverted {
+			delete(state, tuner)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func restore(fs afero.Fs, state commands.State) error {
+	cmd, err := commands.NewRestoreCmd(fs, state)
+	if err != nil {
+		return err
+	}
+	return cmd.Execute()
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tune
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+)
+
+func TestRevert(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/a", []byte("tuned"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/b", []byte("tuned"), 0644))
+	state := tuners.TunerState{
+		// The same file was changed twice, so restoring in reverse order
+		// leaves it with the first value.
+		"cpu": {
+			{Kind: commands.FileState, Setting: "/a", Value: "first"},
+			{Kind: commands.FileState, Setting: "/a", Value: "second"},
+		},
+		"coredump": {
+			{Kind: commands.FileState, Setting: "/b", Missing: true},
+		},
+		"net": {
+			{Kind: "unknown", Setting: "eth0"},
+		},
+	}
+
+	results := revert(fs, state, []string{"swappiness", "cpu", "coredump", "net"})
+	require.Equal(t, []revertResult{
+		{tuner: "coredump", reverted: true},
+		{tuner: "cpu", reverted: true},
+		{tuner: "net", errMsg: `unable to restore eth0: unknown state kind "unknown"`},
+		{tuner: "swappiness", errMsg: "no saved state, the tuner didn't run or was already reverted"},
+	}, results)
+
+	content, err := afero.ReadFile(fs, "/a")
+	require.NoError(t, err)
+	require.Equal(t, "first", string(content))
+	exists, err := afero.Exists(fs, "/b")
+	require.NoError(t, err)
+	require.False(t, exists)
+	// Only the tuner that couldn't be reverted is left.
+	require.Equal(t, tuners.TunerState{"net": {{Kind: "unknown", Setting: "eth0"}}}, state)
+}
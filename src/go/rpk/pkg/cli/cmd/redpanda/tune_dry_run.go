@@ -0,0 +1,144 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/factory"
+)
+
+// dryRunResult is what a tuner would change if it was run.
+type dryRunResult struct {
+	Tuner          string                    `json:"tuner" yaml:"tuner"`
+	Enabled        bool                      `json:"enabled" yaml:"enabled"`
+	Supported      bool                      `json:"supported" yaml:"supported"`
+	Reason         string                    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	RebootRequired bool                      `json:"reboot_required" yaml:"reboot_required"`
+	Changes        []executors.PlannedChange `json:"changes" yaml:"changes"`
+	Error          string                    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func dryRunTune(
+	conf *config.Config,
+	tunerNames []string,
+	tunersFactory factory.TunersFactory,
+	executor *executors.DryRunExecutor,
+	params *factory.TunerParams,
+	format string,
+) error {
+	results, err := planTune(conf, tunerNames, tunersFactory, executor, params)
+	if err != nil {
+		return err
+	}
+	if format != out.FormatTable {
+		return out.PrintStructured(format, results)
+	}
+	printDryRunResult(results)
+	return nil
+}
+
+// planTune runs the tuners, which must have been created with a dry run
+// executor, and returns what each of them would change, sorted by tuner.
+// The tuners that aren't enabled or aren't supported wouldn't run, so they
+// are reported with no changes.
+func planTune(
+	conf *config.Config,
+	tunerNames []string,
+	tunersFactory factory.TunersFactory,
+	executor *executors.DryRunExecutor,
+	params *factory.TunerParams,
+) ([]dryRunResult, error) {
+	params, err := factory.MergeTunerParamsConfig(params, conf)
+	if err != nil {
+		return nil, err
+	}
+	results := []dryRunResult{}
+	for _, tunerName := range tunerNames {
+		res := dryRunResult{
+			Tuner:   tunerName,
+			Enabled: factory.IsTunerEnabled(tunerName, conf.Rpk),
+			Changes: []executors.PlannedChange{},
+		}
+		tuner := tunersFactory.CreateTuner(tunerName, params)
+		res.Supported, res.Reason = tuner.CheckIfSupported()
+		if res.Supported {
+			res.Reason = ""
+		}
+		if res.Enabled && res.Supported {
+			tuneRes := tuner.Tune()
+			res.RebootRequired = tuneRes.IsRebootRequired()
+			if tuneRes.IsFailed() {
+				res.Error = tuneRes.Error().Error()
+			}
+			if changes := executor.Changes(); changes != nil {
+				res.Changes = changes
+			}
+		}
+		results = append(results, res)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Tuner < results[j].Tuner
+	})
+	return results, nil
+}
+
+func printDryRunResult(results []dryRunResult) {
+	t := out.NewTable("tuner", "setting", "current", "value", "reboot required")
+	rebootRequired := false
+	for _, res := range results {
+		rebootRequired = rebootRequired || res.RebootRequired
+		reboot := strconv.FormatBool(res.RebootRequired)
+		switch {
+		case !res.Enabled:
+			t.PrintStrings(res.Tuner, "(disabled, would not run)", "", "", reboot)
+		case !res.Supported:
+			t.PrintStrings(res.Tuner, "(unsupported: "+res.Reason+")", "", "", reboot)
+		case res.Error != "":
+			t.PrintStrings(res.Tuner, "(failed: "+res.Error+")", "", "", reboot)
+		case len(res.Changes) == 0:
+			t.PrintStrings(res.Tuner, "(no changes)", "", "", reboot)
+		}
+		for _, c := range res.Changes {
+			setting := c.Setting
+			if setting == "" {
+				setting = c.Command
+			}
+			t.PrintStrings(
+				res.Tuner,
+				oneLine(setting),
+				oneLine(c.Current),
+				oneLine(c.Value),
+				reboot,
+			)
+		}
+	}
+	t.Flush()
+	if rebootRequired {
+		red := color.New(color.FgRed).SprintFunc()
+		log.Infof(
+			"%s: Applying these changes requires a reboot",
+			red("IMPORTANT"),
+		)
+	}
+}
+
+// oneLine joins the lines of s, so that file contents and rendered commands
+// fit in a table cell.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\\\n", "")), " ")
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/factory"
+)
+
+// writingTuner writes value to path through its executor, like the tuners
+// that are created by the factory do.
+type writingTuner struct {
+	fs       afero.Fs
+	executor executors.Executor
+	path     string
+	value    string
+	reboot   bool
+}
+
+func (*writingTuner) CheckIfSupported() (bool, string) {
+	return true, ""
+}
+
+func (t *writingTuner) Tune() tuners.TuneResult {
+	err := t.executor.Execute(commands.NewWriteFileCmd(t.fs, t.path, t.value))
+	if err != nil {
+		return tuners.NewTuneError(err)
+	}
+	return tuners.NewTuneResult(t.reboot)
+}
+
+type writingTunersFactory map[string]*writingTuner
+
+func (f writingTunersFactory) CreateTuner(
+	name string, _ *factory.TunerParams,
+) tuners.Tunable {
+	return f[name]
+}
+
+func TestPlanTune(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/proc/sys/vm/swappiness", []byte("60\n"), 0644))
+	executor := executors.NewDryRunExecutor()
+	tf := writingTunersFactory{
+		"swappiness": {fs, executor, "/proc/sys/vm/swappiness", "1", false},
+		"cpu":        {fs, executor, "/etc/default/grub", "GRUB_CMDLINE_LINUX=x", true},
+		"coredump":   {fs, executor, "/etc/coredump", "x", false},
+	}
+	conf := config.Default()
+	conf.Rpk.TuneSwappiness = true
+	conf.Rpk.TuneCpu = true
+
+	results, err := planTune(
+		conf,
+		[]string{"swappiness", "cpu", "coredump"},
+		tf,
+		executor,
+		&factory.TunerParams{Nics: []string{"eth0"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []dryRunResult{{
+		Tuner:     "coredump",
+		Supported: true,
+		Changes:   []executors.PlannedChange{},
+	}, {
+		Tuner:          "cpu",
+		Enabled:        true,
+		Supported:      true,
+		RebootRequired: true,
+		Changes: []executors.PlannedChange{{
+			Command: "echo 'GRUB_CMDLINE_LINUX=x' > /etc/default/grub\nchmod 644 /etc/default/grub",
+			Setting: "/etc/default/grub",
+			Value:   "GRUB_CMDLINE_LINUX=x",
+		}},
+	}, {
+		Tuner:     "swappiness",
+		Enabled:   true,
+		Supported: true,
+		Changes: []executors.PlannedChange{{
+			Command: "echo '1' > /proc/sys/vm/swappiness",
+			Setting: "/proc/sys/vm/swappiness",
+			Current: "60",
+			Value:   "1",
+		}},
+	}}, results)
+
+	// Nothing is written.
+	content, err := afero.ReadFile(fs, "/proc/sys/vm/swappiness")
+	require.NoError(t, err)
+	require.Equal(t, "60\n", string(content))
+	exists, err := afero.Exists(fs, "/etc/default/grub")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestTuneDryRunFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		expErr string
+	}{
+		{
+			name:   "it should fail if --output is used without --dry-run",
+			args:   []string{"swappiness", "-o", "json"},
+			expErr: "--output is only supported with --dry-run",
+		},
+		{
+			name:   "it should fail if --dry-run is used with --output-script",
+			args:   []string{"swappiness", "--dry-run", "--output-script", "tune.sh"},
+			expErr: "--dry-run and --output-script can't be used together",
+		},
+		{
+			name:   "it should fail if the output format is unknown",
+			args:   []string{"swappiness", "--dry-run", "-o", "xml"},
+			expErr: `unsupported output format "xml", must be one of table, json, or yaml`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			cmd := NewTuneCommand(fs, config.NewManager(fs))
+			cmd.SetArgs(tt.args)
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			err := cmd.Execute()
+			require.EqualError(t, err, tt.expErr)
+		})
+	}
+}
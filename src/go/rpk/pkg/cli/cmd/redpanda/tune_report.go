@@ -0,0 +1,152 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
+)
+
+// reportFormatText is the --report-format of the human readable summary.
+const reportFormatText = "text"
+
+// The status of each tuner in a tune report.
+const (
+	tunerApplied     = "applied"
+	tunerFailed      = "failed"
+	tunerDisabled    = "disabled"
+	tunerUnsupported = "unsupported"
+)
+
+// tuneReport is what a tune run changed, which --report writes to a file.
+type tuneReport struct {
+	Host           string            `json:"host" yaml:"host"`
+	Time           time.Time         `json:"time" yaml:"time"`
+	RebootRequired bool              `json:"reboot_required" yaml:"reboot_required"`
+	Tuners         []tuneReportEntry `json:"tuners" yaml:"tuners"`
+}
+
+// tuneReportEntry is the outcome of a tuner. Each change's Current is the
+// value before the tuner ran, and its Value the one the tuner set.
+type tuneReportEntry struct {
+	Tuner          string                    `json:"tuner" yaml:"tuner"`
+	Status         string                    `json:"status" yaml:"status"`
+	Reason         string                    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	RebootRequired bool                      `json:"reboot_required" yaml:"reboot_required"`
+	Changes        []executors.PlannedChange `json:"changes" yaml:"changes"`
+	Error          string                    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func checkReportFormat(format string) error {
+	switch format {
+	case out.FormatJSON, out.FormatYAML, reportFormatText:
+		return nil
+	}
+	return fmt.Errorf(
+		"unsupported --report-format '%s', it must be %s, %s or %s",
+		format,
+		out.FormatJSON,
+		out.FormatYAML,
+		reportFormatText,
+	)
+}
+
+func newTuneReport(results []result, now time.Time) tuneReport {
+	host, _ := os.Hostname()
+	r := tuneReport{Host: host, Time: now.UTC(), Tuners: []tuneReportEntry{}}
+	for _, res := range results {
+		e := tuneReportEntry{
+			Tuner:          res.name,
+			RebootRequired: res.rebootRequired,
+			Changes:        res.changes,
+		}
+		if e.Changes == nil {
+			e.Changes = []executors.PlannedChange{}
+		}
+		switch {
+		case !res.enabled:
+			e.Status = tunerDisabled
+		case !res.supported:
+			e.Status = tunerUnsupported
+			e.Reason = res.errMsg
+		case !res.applied:
+			e.Status = tunerFailed
+			e.Error = res.errMsg
+		default:
+			e.Status = tunerApplied
+		}
+		r.RebootRequired = r.RebootRequired || e.RebootRequired
+		r.Tuners = append(r.Tuners, e)
+	}
+	sort.Slice(r.Tuners, func(i, j int) bool {
+		return r.Tuners[i].Tuner < r.Tuners[j].Tuner
+	})
+	return r
+}
+
+// writeTuneReport writes the report to path, in one of the --report-format
+// formats.
+func writeTuneReport(
+	fs afero.Fs, path, format string, r tuneReport,
+) error {
+	var (
+		bs  []byte
+		err error
+	)
+	if format == reportFormatText {
+		bs = tuneReportText(r)
+	} else {
+		bs, err = out.Structured(format, r)
+		if err != nil {
+			return err
+		}
+	}
+	return afero.WriteFile(fs, path, bs, 0644)
+}
+
+func tuneReportText(r tuneReport) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Tune report for %s at %s\n", r.Host, r.Time.Format(time.RFC3339))
+	for _, e := range r.Tuners {
+		fmt.Fprintf(&b, "\n%s: %s", e.Tuner, e.Status)
+		switch {
+		case e.Reason != "":
+			fmt.Fprintf(&b, ": %s", e.Reason)
+		case e.Error != "":
+			fmt.Fprintf(&b, ": %s", e.Error)
+		}
+		if e.RebootRequired {
+			fmt.Fprint(&b, " (reboot required)")
+		}
+		fmt.Fprintln(&b)
+		for _, c := range e.Changes {
+			setting := c.Setting
+			if setting == "" {
+				setting = c.Command
+			}
+			fmt.Fprintf(&b, "  %s\n", oneLine(setting))
+			if c.Current != "" || c.Value != "" {
+				fmt.Fprintf(&b, "    previous: %s\n", oneLine(c.Current))
+				fmt.Fprintf(&b, "    new:      %s\n", oneLine(c.Value))
+			}
+		}
+	}
+	if r.RebootRequired {
+		fmt.Fprintln(&b, "\nThe system must be rebooted for some changes to take effect.")
+	}
+	return b.Bytes()
+}
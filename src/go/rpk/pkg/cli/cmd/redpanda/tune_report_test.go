@@ -0,0 +1,75 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package redpanda
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
+)
+
+func TestTuneReportText(t *testing.T) {
+	results := []result{
+		{
+			name:      "swappiness",
+			applied:   true,
+			enabled:   true,
+			supported: true,
+			changes: []executors.PlannedChange{{
+				Command: "echo '1' > /proc/sys/vm/swappiness",
+				Setting: "/proc/sys/vm/swappiness",
+				Current: "60",
+				Value:   "1",
+			}},
+		},
+		{
+			name:           "cpu",
+			enabled:        true,
+			supported:      true,
+			errMsg:         "unable to set the governor",
+			rebootRequired: true,
+		},
+		{name: "clocksource", enabled: true, errMsg: "no TSC"},
+		{name: "aio_events", supported: true},
+	}
+	r := newTuneReport(results, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	r.Host = "node-1"
+	require.True(t, r.RebootRequired)
+
+	expected := `Tune report for node-1 at 2021-06-01T12:00:00Z
+
+aio_events: disabled
+
+clocksource: unsupported: no TSC
+
+cpu: failed: unable to set the governor (reboot required)
+
+swappiness: applied
+  /proc/sys/vm/swappiness
+    previous: 60
+    new:      1
+
+The system must be rebooted for some changes to take effect.
+`
+	require.Equal(t, expected, string(tuneReportText(r)))
+}
+
+func TestCheckReportFormat(t *testing.T) {
+	for _, f := range []string{"json", "yaml", "text"} {
+		require.NoError(t, checkReportFormat(f))
+	}
+	require.EqualError(
+		t,
+		checkReportFormat("table"),
+		"unsupported --report-format 'table', it must be json, yaml or text",
+	)
+}
@@ -11,13 +11,17 @@ package redpanda
 
 import (
 	"bytes"
+	"encoding/json"
+	"io/ioutil"
 	"log"
 	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/utils"
 )
 
@@ -92,3 +96,100 @@ func TestInteractive(t *testing.T) {
 		})
 	}
 }
+
+func TestTuneThenRevert(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := config.NewManager(fs)
+	conf := config.Default()
+	conf.Rpk.TuneSwappiness = true
+	require.NoError(t, mgr.Write(conf))
+	_, err := utils.WriteBytes(fs, []byte("60\n"), "/proc/sys/vm/swappiness")
+	require.NoError(t, err)
+	_, err = utils.WriteBytes(
+		fs,
+		[]byte("Filename\tType\tSize\tUsed\tPriority\n/swapfile\tfile\t2097148\t0\t-2\n"),
+		"/proc/swaps",
+	)
+	require.NoError(t, err)
+	logrus.SetOutput(ioutil.Discard)
+
+	cmd := NewTuneCommand(fs, mgr)
+	cmd.SetArgs([]string{"swappiness"})
+	require.NoError(t, cmd.Execute())
+	content, err := afero.ReadFile(fs, "/proc/sys/vm/swappiness")
+	require.NoError(t, err)
+	require.Equal(t, "1", string(content))
+	exists, err := afero.Exists(fs, "/etc/redpanda/tuner-state.json")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	cmd = NewTuneCommand(fs, mgr)
+	cmd.SetArgs([]string{"revert", "swappiness"})
+	require.NoError(t, cmd.Execute())
+	content, err = afero.ReadFile(fs, "/proc/sys/vm/swappiness")
+	require.NoError(t, err)
+	require.Equal(t, "60\n", string(content))
+	exists, err = afero.Exists(fs, "/etc/redpanda/tuner-state.json")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// There is nothing left to revert.
+	cmd = NewTuneCommand(fs, mgr)
+	cmd.SetArgs([]string{"revert"})
+	cmd.SilenceErrors = true
+	require.EqualError(
+		t,
+		cmd.Execute(),
+		"there are no saved tuner changes in /etc/redpanda/tuner-state.json, nothing to revert",
+	)
+}
+
+func TestTuneReport(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := config.NewManager(fs)
+	conf := config.Default()
+	conf.Rpk.TuneSwappiness = true
+	require.NoError(t, mgr.Write(conf))
+	_, err := utils.WriteBytes(fs, []byte("60\n"), "/proc/sys/vm/swappiness")
+	require.NoError(t, err)
+	_, err = utils.WriteBytes(
+		fs,
+		[]byte("Filename\tType\tSize\tUsed\tPriority\n/swapfile\tfile\t2097148\t0\t-2\n"),
+		"/proc/swaps",
+	)
+	require.NoError(t, err)
+	logrus.SetOutput(ioutil.Discard)
+
+	cmd := NewTuneCommand(fs, mgr)
+	cmd.SetArgs([]string{"swappiness,aio_events", "--report", "/tmp/tune.json"})
+	require.NoError(t, cmd.Execute())
+
+	bs, err := afero.ReadFile(fs, "/tmp/tune.json")
+	require.NoError(t, err)
+	var report tuneReport
+	require.NoError(t, json.Unmarshal(bs, &report))
+	require.False(t, report.RebootRequired)
+	require.Equal(t, []tuneReportEntry{
+		{
+			Tuner:   "aio_events",
+			Status:  tunerDisabled,
+			Changes: []executors.PlannedChange{},
+		},
+		{
+			Tuner:  "swappiness",
+			Status: tunerApplied,
+			Changes: []executors.PlannedChange{{
+				Command: "echo '1' > /proc/sys/vm/swappiness",
+				Setting: "/proc/sys/vm/swappiness",
+				Current: "60",
+				Value:   "1",
+			}},
+		},
+	}, report.Tuners)
+
+	cmd = NewTuneCommand(fs, mgr)
+	cmd.SetArgs([]string{"swappiness", "--report", "/tmp/tune.json", "--dry-run"})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	require.EqualError(t, cmd.Execute(), "--report can't be used with --dry-run or --output-script")
+}
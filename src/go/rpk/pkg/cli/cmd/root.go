@@ -28,6 +28,7 @@ import (
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/cli/cmd/common"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/out"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
@@ -129,7 +130,7 @@ func Execute() {
 		}
 	}
 	if err != nil {
-		os.Exit(1)
+		os.Exit(out.ExitCode(err))
 	}
 }
 
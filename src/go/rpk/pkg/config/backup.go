@@ -0,0 +1,144 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	fp "path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/utils"
+)
+
+// DefaultConfigBackups is the number of backups of the config file that are
+// kept if rpk.config_backups isn't set.
+const DefaultConfigBackups = 5
+
+const (
+	backupInfix = ".bak-"
+	// The backup timestamps sort in the order they were taken.
+	backupTimeFormat = "20060102T150405.000000000Z"
+)
+
+// configBackups returns the number of backups to keep per v, which is at least
+// one so that a failed write can always be recovered.
+func configBackups(v *viper.Viper) int {
+	if !v.IsSet("rpk.config_backups") {
+		return DefaultConfigBackups
+	}
+	if n := v.GetInt("rpk.config_backups"); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// findBackups returns the backups of the config file at path, oldest first.
+func findBackups(fs afero.Fs, path string) ([]string, error) {
+	dir := fp.Dir(path)
+	exists, err := afero.Exists(fs, dir)
+	if err != nil || !exists {
+		return nil, err
+	}
+	files, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fp.Base(path) + backupInfix
+	var backups []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasPrefix(f.Name(), prefix) {
+			backups = append(backups, fp.Join(dir, f.Name()))
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// backupConfig copies the config file at path to a new timestamped backup next
+// to it, removes the oldest backups so that only keep remain, and returns the
+// path of the new backup.
+func backupConfig(fs afero.Fs, path string, keep int) (string, error) {
+	backup := path + backupInfix + time.Now().UTC().Format(backupTimeFormat)
+	err := utils.CopyFile(fs, path, backup)
+	if err != nil {
+		return "", fmt.Errorf("unable to back up %s: %v", path, err)
+	}
+	log.Debugf("Backed up the current config to %s", backup)
+	backups, err := findBackups(fs, path)
+	if err != nil {
+		return "", err
+	}
+	for len(backups) > keep {
+		log.Debugf("Removing the old config backup %s", backups[0])
+		err = fs.Remove(backups[0])
+		if err != nil {
+			return "", err
+		}
+		backups = backups[1:]
+	}
+	return backup, nil
+}
+
+func (m *manager) Backups(path string) ([]string, error) {
+	return findBackups(m.fs, path)
+}
+
+func (m *manager) Backup(path string) (string, error) {
+	return backupConfig(m.fs, path, configBackups(m.v))
+}
+
+func (m *manager) Restore(path, backup string) (string, error) {
+	backups, err := findBackups(m.fs, path)
+	if err != nil {
+		return "", err
+	}
+	if len(backups) == 0 {
+		return "", fmt.Errorf("%s has no backups", path)
+	}
+	restored := backups[len(backups)-1]
+	if backup != "" {
+		restored = ""
+		for _, b := range backups {
+			if b == backup || fp.Base(b) == backup {
+				restored = b
+				break
+			}
+		}
+		if restored == "" {
+			return "", fmt.Errorf("%s is not a backup of %s", backup, path)
+		}
+	}
+	// The backup is read before backing up the current config, which may
+	// remove it if it's the oldest.
+	content, err := afero.ReadFile(m.fs, restored)
+	if err != nil {
+		return "", err
+	}
+	mode := os.FileMode(0644)
+	info, err := m.fs.Stat(path)
+	switch {
+	case err == nil:
+		mode = info.Mode()
+		_, err = m.Backup(path)
+		if err != nil {
+			return "", err
+		}
+	case !os.IsNotExist(err):
+		return "", err
+	}
+	log.Debugf("Restoring %s from %s", path, restored)
+	return restored, afero.WriteFile(m.fs, path, content, mode)
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIDs(t *testing.T, mgr Manager, conf *Config, ids ...int) {
+	for _, id := range ids {
+		conf.Redpanda.Id = id
+		require.NoError(t, mgr.Write(conf))
+	}
+}
+
+func TestWriteKeepsBackups(t *testing.T) {
+	two := 2
+	tests := []struct {
+		name     string
+		backups  *int
+		expected int
+	}{
+		{
+			name:     "it should keep the default number of backups",
+			expected: DefaultConfigBackups,
+		},
+		{
+			name:     "it should keep rpk.config_backups backups",
+			backups:  &two,
+			expected: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			mgr := NewManager(fs)
+			conf := Default()
+			conf.Rpk.ConfigBackups = tt.backups
+			writeIDs(t, mgr, conf, 0, 1, 2, 3, 4, 5, 6, 7)
+
+			backups, err := mgr.Backups(conf.ConfigFile)
+			require.NoError(t, err)
+			require.Len(t, backups, tt.expected)
+			// The newest backup is of the config before the last write.
+			latest, err := NewManager(fs).Read(backups[len(backups)-1])
+			require.NoError(t, err)
+			require.Exactly(t, 6, latest.Redpanda.Id)
+			for _, b := range backups {
+				require.Equal(t, filepath.Dir(conf.ConfigFile), filepath.Dir(b))
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name        string
+		backup      func(backups []string) string
+		expectedID  int
+		expectedErr bool
+	}{
+		{
+			name:       "it should restore the latest backup",
+			backup:     func([]string) string { return "" },
+			expectedID: 2,
+		},
+		{
+			name:       "it should restore a backup by name",
+			backup:     func(bs []string) string { return filepath.Base(bs[0]) },
+			expectedID: 0,
+		},
+		{
+			name:       "it should restore a backup by path",
+			backup:     func(bs []string) string { return bs[1] },
+			expectedID: 1,
+		},
+		{
+			name:        "it should fail if the backup doesn't exist",
+			backup:      func([]string) string { return "redpanda.yaml.bak-nope" },
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			mgr := NewManager(fs)
+			conf := Default()
+			writeIDs(t, mgr, conf, 0, 1, 2, 3)
+			backups, err := mgr.Backups(conf.ConfigFile)
+			require.NoError(t, err)
+			require.Len(t, backups, 3)
+
+			restored, err := mgr.Restore(conf.ConfigFile, tt.backup(backups))
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, backups, restored)
+
+			got, err := NewManager(fs).Read(conf.ConfigFile)
+			require.NoError(t, err)
+			require.Exactly(t, tt.expectedID, got.Redpanda.Id)
+
+			// The config that was replaced is backed up too.
+			after, err := mgr.Backups(conf.ConfigFile)
+			require.NoError(t, err)
+			require.Len(t, after, 4)
+			replaced, err := NewManager(fs).Read(after[len(after)-1])
+			require.NoError(t, err)
+			require.Exactly(t, 3, replaced.Redpanda.Id)
+		})
+	}
+}
+
+func TestRestoreWithoutBackups(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs)
+	conf := Default()
+	require.NoError(t, mgr.Write(conf))
+	_, err := mgr.Restore(conf.ConfigFile, "")
+	require.EqualError(t, err, "/etc/redpanda/redpanda.yaml has no backups")
+}
@@ -10,9 +10,12 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	fp "path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/mitchellh/mapstructure"
@@ -113,26 +116,6 @@ func defaultMap() map[string]interface{} {
 	}
 }
 
-func findBackup(fs afero.Fs, dir string) (string, error) {
-	exists, err := afero.Exists(fs, dir)
-	if err != nil {
-		return "", err
-	}
-	if !exists {
-		return "", nil
-	}
-	files, err := afero.ReadDir(fs, dir)
-	if err != nil {
-		return "", err
-	}
-	for _, f := range files {
-		if strings.HasSuffix(f.Name(), ".bk") {
-			return fmt.Sprintf("%s/%s", dir, f.Name()), nil
-		}
-	}
-	return "", nil
-}
-
 func SetMode(mode string, conf *Config) (*Config, error) {
 	m, err := NormalizeMode(mode)
 	if err != nil {
@@ -231,8 +214,20 @@ func Check(conf *Config) (bool, []error) {
 	return check(v)
 }
 
+// CheckRaw checks a yaml config with the same checks that are run before
+// writing the config, returning whether it's valid and why it isn't.
+func CheckRaw(raw []byte) (bool, []error) {
+	v := InitViper(afero.NewMemMapFs())
+	err := v.ReadConfig(bytes.NewReader(raw))
+	if err != nil {
+		return false, []error{err}
+	}
+	return check(v)
+}
+
 func check(v *viper.Viper) (bool, []error) {
-	errs := checkRedpandaConfig(v)
+	errs := checkTypes(v)
+	errs = append(errs, checkRedpandaConfig(v)...)
 	errs = append(
 		errs,
 		checkRpkConfig(v)...,
@@ -241,6 +236,80 @@ func check(v *viper.Viper) (bool, []error) {
 	return ok, errs
 }
 
+// typedKeys are the keys whose values must be of a given type, in the order
+// they are checked.
+var typedKeys = []struct {
+	key  string
+	kind reflect.Kind
+}{
+	{"redpanda.data_directory", reflect.String},
+	{"redpanda.node_id", reflect.Int},
+	{"redpanda.developer_mode", reflect.Bool},
+	{"redpanda.rack", reflect.String},
+	{"redpanda.enable_rack_awareness", reflect.Bool},
+	{"redpanda.enable_sasl", reflect.Bool},
+	{"rpk.enable_usage_stats", reflect.Bool},
+	{"rpk.overprovisioned", reflect.Bool},
+	{"rpk.tune_coredump", reflect.Bool},
+	{"rpk.coredump_dir", reflect.String},
+	{"rpk.swappiness", reflect.Int},
+	{"rpk.transparent_hugepages_enabled", reflect.String},
+	{"rpk.transparent_hugepages_defrag", reflect.String},
+}
+
+// checkTypes returns an error for each key in typedKeys whose value is not of
+// the key's type.
+func checkTypes(v *viper.Viper) []error {
+	errs := []error{}
+	for _, tk := range typedKeys {
+		val := v.Get(tk.key)
+		if val == nil || hasKind(val, tk.kind) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s must be %s, got %#v", tk.key, kindName(tk.kind), val))
+	}
+	return errs
+}
+
+// hasKind returns whether val is of the given kind. Strings that parse as the
+// kind are accepted, since viper may store values that way (i.e. through
+// BindPFlag), as are whole floats for ints, which is how JSON numbers decode.
+func hasKind(val interface{}, kind reflect.Kind) bool {
+	if str, ok := val.(string); ok {
+		var err error
+		switch kind {
+		case reflect.Int:
+			_, err = strconv.Atoi(str)
+		case reflect.Bool:
+			_, err = strconv.ParseBool(str)
+		}
+		return err == nil
+	}
+	rv := reflect.ValueOf(val)
+	if kind != reflect.Int {
+		return rv.Kind() == kind
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == float64(int64(rv.Float()))
+	}
+	return false
+}
+
+func kindName(k reflect.Kind) string {
+	switch k {
+	case reflect.Int:
+		return "an integer"
+	case reflect.Bool:
+		return "true or false"
+	default:
+		return "a string"
+	}
+}
+
 func checkRedpandaConfig(v *viper.Viper) []error {
 	errs := []error{}
 	if v.GetString("redpanda.data_directory") == "" {
@@ -310,6 +379,38 @@ func checkRedpandaConfig(v *viper.Viper) []error {
 		}
 	}
 
+	adminApiKey := "redpanda.admin"
+	if v.Get(adminApiKey) != nil {
+		var adminListeners []NamedSocketAddress
+		err := unmarshalKey(v, adminApiKey, &adminListeners)
+		if err != nil {
+			log.Error(err)
+			err = fmt.Errorf(
+				"%s doesn't have the expected structure",
+				adminApiKey,
+			)
+			return append(
+				errs,
+				err,
+			)
+		}
+		for i, addr := range adminListeners {
+			errs = append(
+				errs,
+				checkNamedSocketAddress(
+					addr,
+					fmt.Sprintf("%s.%d", adminApiKey, i),
+				)...,
+			)
+		}
+	}
+
+	if v.GetBool("redpanda.enable_rack_awareness") && v.GetString("redpanda.rack") == "" {
+		msg := "if redpanda.enable_rack_awareness is set to true, " +
+			"redpanda.rack can't be empty"
+		errs = append(errs, errors.New(msg))
+	}
+
 	var seedServersSlice []*SeedServer //map[string]interface{}
 	err := unmarshalKey(v, "redpanda.seed_servers", &seedServersSlice)
 	if err != nil {
@@ -344,6 +445,8 @@ func checkSocketAddress(s SocketAddress, configPath string) []error {
 	errs := []error{}
 	if s.Port == 0 {
 		errs = append(errs, fmt.Errorf("%s.port can't be 0", configPath))
+	} else if s.Port < 0 || s.Port > 65535 {
+		errs = append(errs, fmt.Errorf("%s.port must be between 1 and 65535, got %d", configPath, s.Port))
 	}
 	if s.Address == "" {
 		errs = append(errs, fmt.Errorf("%s.address can't be empty", configPath))
@@ -362,9 +465,38 @@ func checkRpkConfig(v *viper.Viper) []error {
 			"rpk.coredump_dir can't be empty"
 		errs = append(errs, errors.New(msg))
 	}
+	if v.IsSet("rpk.swappiness") && hasKind(v.Get("rpk.swappiness"), reflect.Int) {
+		if s := v.GetInt("rpk.swappiness"); s < 0 || s > 100 {
+			errs = append(errs, fmt.Errorf("rpk.swappiness must be between 0 and 100, got %d", s))
+		}
+	}
+	errs = append(errs, checkOneOf(v, "rpk.transparent_hugepages_enabled", thpEnabledModes)...)
+	errs = append(errs, checkOneOf(v, "rpk.transparent_hugepages_defrag", thpDefragModes)...)
 	return errs
 }
 
+// The modes that the kernel's transparent huge pages enabled and defrag
+// files accept.
+var (
+	thpEnabledModes = []string{"always", "madvise", "never"}
+	thpDefragModes  = []string{"always", "defer", "defer+madvise", "madvise", "never"}
+)
+
+// checkOneOf returns an error if the key is set to a string that isn't one
+// of the values.
+func checkOneOf(v *viper.Viper, key string, values []string) []error {
+	val, ok := v.Get(key).(string)
+	if !ok || val == "" {
+		return nil
+	}
+	for _, allowed := range values {
+		if val == allowed {
+			return nil
+		}
+	}
+	return []error{fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(values, ", "), val)}
+}
+
 func decoderConfig() mapstructure.DecoderConfig {
 	return mapstructure.DecoderConfig{
 		// Sometimes viper will save int values as strings (i.e.
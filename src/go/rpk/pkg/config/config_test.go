@@ -288,6 +288,208 @@ func TestSet(t *testing.T) {
 	}
 }
 
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		typ       string
+		expected  interface{}
+		expectErr bool
+	}{
+		{name: "it should infer ints", value: "9092", expected: 9092},
+		{name: "it should infer floats", value: "0.25", expected: 0.25},
+		{name: "it should infer bools", value: "true", expected: true},
+		{name: "it should infer null", value: "null", expected: nil},
+		{name: "it should infer null (~)", value: "~", expected: nil},
+		{
+			name:     "it should infer JSON lists",
+			value:    `["a", 2]`,
+			expected: []interface{}{"a", float64(2)},
+		},
+		{
+			name:     "it should infer JSON maps",
+			value:    `{"a": true}`,
+			typ:      "auto",
+			expected: map[string]interface{}{"a": true},
+		},
+		{
+			name:     "it should treat invalid JSON as a string",
+			value:    "[a",
+			expected: "[a",
+		},
+		{name: "it should infer strings", value: "/var/lib", expected: "/var/lib"},
+		{name: "it should force strings", value: "true", typ: "string", expected: "true"},
+		{name: "it should force bools", value: "1", typ: "bool", expected: true},
+		{name: "it should force ints", value: "1", typ: "int", expected: 1},
+		{name: "it should force floats", value: "1", typ: "float", expected: 1.0},
+		{name: "it should force null", value: "", typ: "null", expected: nil},
+		{name: "it should force JSON", value: `"1"`, typ: "json", expected: "1"},
+		{name: "it should fail on invalid bools", value: "yes", typ: "bool", expectErr: true},
+		{name: "it should fail on invalid ints", value: "1.5", typ: "int", expectErr: true},
+		{name: "it should fail on invalid floats", value: "a", typ: "float", expectErr: true},
+		{name: "it should fail on invalid null", value: "nil", typ: "null", expectErr: true},
+		{name: "it should fail on invalid JSON", value: "[a", typ: "json", expectErr: true},
+		{name: "it should fail on unknown types", value: "1", typ: "uint", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseValue(tt.value, tt.typ)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Exactly(t, tt.expected, v)
+		})
+	}
+}
+
+func TestGetValue(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs)
+	conf := Default()
+	require.NoError(t, mgr.Write(conf))
+	_, err := mgr.Read(conf.ConfigFile)
+	require.NoError(t, err)
+
+	v, err := mgr.GetValue("redpanda.kafka_api.0.port")
+	require.NoError(t, err)
+	require.Exactly(t, 9092, v)
+
+	v, err = mgr.GetValue("Redpanda.RPC_Server")
+	require.NoError(t, err)
+	require.Exactly(t, map[string]interface{}{"address": "0.0.0.0", "port": 33145}, v)
+
+	for _, key := range []string{"", "redpanda.nope", "redpanda.kafka_api.x", "redpanda.node_id.0"} {
+		_, err = mgr.GetValue(key)
+		require.Error(t, err, "key %q", key)
+	}
+}
+
+func TestSetValueKeepsSiblings(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs)
+	conf := Default()
+	require.NoError(t, mgr.Write(conf))
+	_, err := mgr.Read(conf.ConfigFile)
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.SetValue("redpanda.rpc_server.port", 33146))
+	got, err := mgr.Get()
+	require.NoError(t, err)
+	require.Exactly(t, 33146, got.Redpanda.RPCServer.Port)
+	require.Exactly(t, conf.Redpanda.RPCServer.Address, got.Redpanda.RPCServer.Address)
+
+	require.NoError(t, mgr.SetValue("redpanda.data_directory", 1234))
+	v, err := mgr.GetValue("redpanda.data_directory")
+	require.NoError(t, err)
+	require.Exactly(t, 1234, v)
+
+	require.NoError(t, mgr.SetValue("redpanda.seed_servers", nil))
+	got, err = mgr.Get()
+	require.NoError(t, err)
+	require.Empty(t, got.Redpanda.SeedServers)
+	require.Exactly(t, 33146, got.Redpanda.RPCServer.Port)
+}
+
+func TestSetValueChangesType(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs)
+	conf := Default()
+	require.NoError(t, mgr.Write(conf))
+	_, err := mgr.Read(conf.ConfigFile)
+	require.NoError(t, err)
+
+	// Merging would keep the current int, since the types differ.
+	require.NoError(t, mgr.SetValue("redpanda.node_id", "1"))
+	require.Exactly(t, "1", mgr.(*manager).v.Get("redpanda.node_id"))
+}
+
+func TestSetValueListIndex(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	mgr := NewManager(fs)
+	conf := Default()
+	require.NoError(t, mgr.Write(conf))
+	_, err := mgr.Read(conf.ConfigFile)
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.SetValue("redpanda.kafka_api.0.port", 9093))
+	got, err := mgr.Get()
+	require.NoError(t, err)
+	require.Len(t, got.Redpanda.KafkaApi, 1)
+	require.Exactly(t, 9093, got.Redpanda.KafkaApi[0].Port)
+	require.Exactly(t, conf.Redpanda.KafkaApi[0].Address, got.Redpanda.KafkaApi[0].Address)
+
+	err = mgr.SetValue("redpanda.kafka_api.1.port", 9094)
+	require.EqualError(t, err, `unable to set redpanda.kafka_api.1.port: invalid index "1" into redpanda.kafka_api, which has 1 elements`)
+	err = mgr.SetValue("redpanda.node_id.0", 1)
+	require.EqualError(t, err, `unable to set redpanda.node_id.0: invalid index "0", redpanda.node_id is not a list`)
+
+	require.NoError(t, mgr.SetValue("redpanda.kafka_api.0", nil))
+	got, err = mgr.Get()
+	require.NoError(t, err)
+	require.Empty(t, got.Redpanda.KafkaApi)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected []string
+	}{
+		{
+			name: "it should accept a valid config",
+			file: `redpanda:
+  node_id: "1"
+  developer_mode: true
+  rack: a
+`,
+		},
+		{
+			name: "it should reject values of the wrong type",
+			file: `redpanda:
+  node_id: abc
+  developer_mode: maybe
+  data_directory: 1
+rpk:
+  tune_coredump: 2.5
+`,
+			expected: []string{
+				`redpanda.data_directory must be a string, got 1`,
+				`redpanda.node_id must be an integer, got "abc"`,
+				`redpanda.developer_mode must be true or false, got "maybe"`,
+				`rpk.tune_coredump must be true or false, got 2.5`,
+			},
+		},
+		{
+			name: "it should reject ports out of range",
+			file: `redpanda:
+  rpc_server:
+    address: 0.0.0.0
+    port: -1
+`,
+			expected: []string{"redpanda.rpc_server.port must be between 1 and 65535, got -1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			path := "/etc/redpanda/redpanda.yaml"
+			require.NoError(t, afero.WriteFile(fs, path, []byte(tt.file), 0644))
+			ok, errs := NewManager(fs).Validate(path)
+			errMsgs := []string{}
+			for _, err := range errs {
+				errMsgs = append(errMsgs, err.Error())
+			}
+			require.Equal(t, len(tt.expected) == 0, ok)
+			if tt.expected == nil {
+				tt.expected = []string{}
+			}
+			require.Exactly(t, tt.expected, errMsgs)
+		})
+	}
+}
+
 func TestMerge(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1789,9 +1991,10 @@ schema_registry: {}
 			require.NoError(t, err)
 			content := string(contentBytes)
 			require.Equal(t, tt.expected, content)
-			backup, err := findBackup(fs, filepath.Dir(path))
+			backups, err := findBackups(fs, path)
 			require.NoError(t, err)
-			_, err = fs.Stat(backup)
+			require.Len(t, backups, 1)
+			_, err = fs.Stat(backups[0])
 			require.NoError(t, err)
 		})
 	}
@@ -2080,6 +2283,66 @@ func TestCheckConfig(t *testing.T) {
 			},
 			expected: []string{"redpanda.seed_servers.1.host.port can't be 0"},
 		},
+		{
+			name: "shall return an error when a port is out of range",
+			conf: func() *Config {
+				c := getValidConfig()
+				c.Redpanda.KafkaApi[0].Port = 65536
+				return c
+			},
+			expected: []string{"redpanda.kafka_api.0.port must be between 1 and 65535, got 65536"},
+		},
+		{
+			name: "shall return an error when rpk.swappiness is out of range",
+			conf: func() *Config {
+				c := getValidConfig()
+				s := 101
+				c.Rpk.Swappiness = &s
+				return c
+			},
+			expected: []string{"rpk.swappiness must be between 0 and 100, got 101"},
+		},
+		{
+			name: "shall return an error when a transparent huge pages mode is unknown",
+			conf: func() *Config {
+				c := getValidConfig()
+				c.Rpk.TransparentHugePagesEnabled = "sometimes"
+				c.Rpk.TransparentHugePagesDefrag = "defer+madvise"
+				return c
+			},
+			expected: []string{`rpk.transparent_hugepages_enabled must be one of always, madvise, never, got "sometimes"`},
+		},
+		{
+			name: "shall return an error when the admin API address is empty",
+			conf: func() *Config {
+				c := getValidConfig()
+				c.Redpanda.AdminApi = []NamedSocketAddress{{SocketAddress: SocketAddress{Port: 9644}}}
+				return c
+			},
+			expected: []string{"redpanda.admin.0.address can't be empty"},
+		},
+		{
+			name: "shall return an error when rack awareness is enabled without a rack",
+			conf: func() *Config {
+				c := getValidConfig()
+				c.Redpanda.Other = map[string]interface{}{"enable_rack_awareness": true}
+				return c
+			},
+			expected: []string{"if redpanda.enable_rack_awareness is set to true, " +
+				"redpanda.rack can't be empty"},
+		},
+		{
+			name: "shall return no errors when rack awareness is enabled with a rack",
+			conf: func() *Config {
+				c := getValidConfig()
+				c.Redpanda.Other = map[string]interface{}{
+					"enable_rack_awareness": true,
+					"rack":                  "us-east-1a",
+				}
+				return c
+			},
+			expected: []string{},
+		},
 		{
 			name: "shall return no errors when tune_coredump is set to false," +
 				"regardless of coredump_dir's value",
@@ -2331,3 +2594,20 @@ func TestGet(t *testing.T) {
 	require.NoError(t, err)
 	require.Exactly(t, Default(), conf)
 }
+
+func TestLocalHostPort(t *testing.T) {
+	for _, tt := range []struct {
+		address  string
+		expected string
+	}{
+		{"", "127.0.0.1:9644"},
+		{"0.0.0.0", "127.0.0.1:9644"},
+		{"::", "127.0.0.1:9644"},
+		{"10.0.0.1", "10.0.0.1:9644"},
+		{"::1", "[::1]:9644"},
+		{"node-1", "node-1:9644"},
+	} {
+		a := SocketAddress{Address: tt.address, Port: 9644}
+		require.Equal(t, tt.expected, a.LocalHostPort())
+	}
+}
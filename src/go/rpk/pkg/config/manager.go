@@ -45,8 +45,17 @@ type Manager interface {
 	WriteLoaded() error
 	// Get the currently-loaded config
 	Get() (*Config, error)
+	// Checks the config file at the given path with the same checks that are
+	// run before writing, returning whether it's valid and why it isn't
+	Validate(path string) (bool, []error)
+	// Gets the value of key in the currently-loaded config, where key is a
+	// dotted path that may index lists, e.g. "redpanda.kafka_api.0.port"
+	GetValue(key string) (interface{}, error)
 	// Sets key to the given value (parsing it according to the format)
 	Set(key, value, format string) error
+	// Sets key to the given, already parsed, value, or removes it if the
+	// value is nil
+	SetValue(key string, value interface{}) error
 	// If path is empty, tries to find the file in the default locations.
 	// Otherwise, it tries to read the file and load it. If the file doesn't
 	// exist, it tries to create it with the default configuration.
@@ -64,6 +73,15 @@ type Manager interface {
 	WriteNodeUUID(conf *Config) error
 	// Merges an input config to the currently-loaded map
 	Merge(conf *Config) error
+	// Lists the backups of the config file at the given path, oldest first
+	Backups(path string) ([]string, error)
+	// Backs up the config file at the given path, keeping the last
+	// rpk.config_backups backups, and returns the path of the backup
+	Backup(path string) (string, error)
+	// Restores the config file at the given path from the given backup, or
+	// from the latest one if it's empty, backing up the current file first,
+	// and returns the path of the restored backup
+	Restore(path, backup string) (string, error)
 }
 
 type manager struct {
@@ -289,6 +307,39 @@ func (m *manager) Get() (*Config, error) {
 	return unmarshal(m.v)
 }
 
+func (m *manager) Validate(path string) (bool, []error) {
+	raw, err := afero.ReadFile(m.fs, path)
+	if err != nil {
+		return false, []error{err}
+	}
+	return CheckRaw(raw)
+}
+
+func (m *manager) GetValue(key string) (interface{}, error) {
+	if key == "" {
+		return nil, errors.New("empty config field key")
+	}
+	var v interface{} = m.v.AllSettings()
+	for _, field := range strings.Split(strings.ToLower(key), ".") {
+		var ok bool
+		switch current := v.(type) {
+		case map[string]interface{}:
+			v, ok = current[field]
+		case map[interface{}]interface{}:
+			v, ok = current[field]
+		case []interface{}:
+			i, err := strconv.Atoi(field)
+			if ok = err == nil && i >= 0 && i < len(current); ok {
+				v = current[i]
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("config key %q not found", key)
+		}
+	}
+	return dyno.ConvertMapI2MapS(v), nil
+}
+
 // Checks config and writes it to the given path.
 func (m *manager) Write(conf *Config) error {
 	confMap, err := toMap(conf)
@@ -348,8 +399,7 @@ func (m *manager) setDeduceFormat(key, value string) error {
 		return replace(key, newVal)
 
 	default: // Treat the value as a "single"
-		m.v.Set(key, parse(value))
-		return nil
+		return m.SetValue(key, parse(value))
 	}
 }
 
@@ -363,8 +413,7 @@ func (m *manager) Set(key, value, format string) error {
 	var newConfValue interface{}
 	switch strings.ToLower(format) {
 	case "single":
-		m.v.Set(key, parse(value))
-		return nil
+		return m.SetValue(key, parse(value))
 	case "yaml":
 		err := yaml.Unmarshal([]byte(value), &newConfValue)
 		if err != nil {
@@ -383,6 +432,141 @@ func (m *manager) Set(key, value, format string) error {
 	return m.v.MergeConfigMap(newV.AllSettings())
 }
 
+func (m *manager) SetValue(key string, value interface{}) error {
+	if key == "" {
+		return errors.New("empty config field key")
+	}
+	// Setting the key directly would shadow its siblings in the loaded
+	// config (e.g. setting redpanda.rpc_server.port would hide
+	// redpanda.rpc_server.address), and merging it would silently keep the
+	// current value if the types differ, so the loaded config is rebuilt
+	// with the key set instead. Viper has no way to remove a key, nor does
+	// it write null values, so a nil value removes the key, which then
+	// falls back to its default value, if it has one.
+	settings := m.v.AllSettings()
+	path := strings.Split(strings.ToLower(key), ".")
+	if value == nil {
+		if !deleteKey(settings, path) {
+			return nil
+		}
+	} else if err := setKey(settings, path, value); err != nil {
+		return fmt.Errorf("unable to set %s: %w", key, err)
+	}
+	v := InitViper(m.fs)
+	v.SetConfigFile(m.v.ConfigFileUsed())
+	err := v.MergeConfigMap(settings)
+	if err != nil {
+		return err
+	}
+	m.v = v
+	return nil
+}
+
+// setKey sets the value at path in m, creating the maps along the path that
+// don't exist. Numeric path segments index lists, e.g. the 0 in
+// redpanda.kafka_api.0.port, and must be within a list that exists.
+func setKey(m map[string]interface{}, path []string, value interface{}) error {
+	_, err := setIn(m, path, 0, value)
+	return err
+}
+
+func setIn(
+	node interface{}, path []string, depth int, value interface{},
+) (interface{}, error) {
+	if depth == len(path) {
+		return value, nil
+	}
+	field := path[depth]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, err := setIn(n[field], path, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		n[field] = child
+		return n, nil
+	case map[interface{}]interface{}:
+		child, err := setIn(n[field], path, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		n[field] = child
+		return n, nil
+	case []interface{}:
+		i, err := strconv.Atoi(field)
+		if err != nil || i < 0 || i >= len(n) {
+			return nil, fmt.Errorf(
+				"invalid index %q into %s, which has %d elements",
+				field, strings.Join(path[:depth], "."), len(n),
+			)
+		}
+		child, err := setIn(n[i], path, depth+1, value)
+		if err != nil {
+			return nil, err
+		}
+		list := append([]interface{}(nil), n...)
+		list[i] = child
+		return list, nil
+	}
+	if _, err := strconv.Atoi(field); err == nil {
+		return nil, fmt.Errorf(
+			"invalid index %q, %s is not a list", field, strings.Join(path[:depth], "."),
+		)
+	}
+	return setIn(make(map[string]interface{}), path, depth, value)
+}
+
+// deleteKey removes the value at path from m, returning whether there was
+// one. As in setKey, numeric path segments index lists, and removing a list
+// element shifts the elements after it.
+func deleteKey(m map[string]interface{}, path []string) bool {
+	_, ok := deleteIn(m, path)
+	return ok
+}
+
+func deleteIn(node interface{}, path []string) (interface{}, bool) {
+	field := path[0]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[field]
+		if !ok {
+			return n, false
+		}
+		if len(path) == 1 {
+			delete(n, field)
+			return n, true
+		}
+		child, ok = deleteIn(child, path[1:])
+		n[field] = child
+		return n, ok
+	case map[interface{}]interface{}:
+		child, ok := n[field]
+		if !ok {
+			return n, false
+		}
+		if len(path) == 1 {
+			delete(n, field)
+			return n, true
+		}
+		child, ok = deleteIn(child, path[1:])
+		n[field] = child
+		return n, ok
+	case []interface{}:
+		i, err := strconv.Atoi(field)
+		if err != nil || i < 0 || i >= len(n) {
+			return n, false
+		}
+		list := append([]interface{}(nil), n...)
+		if len(path) == 1 {
+			return append(list[:i], list[i+1:]...), true
+		}
+		child, ok := deleteIn(list[i], path[1:])
+		list[i] = child
+		return list, ok
+	}
+	return node, false
+}
+
 func (m *manager) Merge(conf *Config) error {
 	confMap, err := toMap(conf)
 	if err != nil {
@@ -400,10 +584,6 @@ func checkAndWrite(fs afero.Fs, v *viper.Viper, path string) error {
 		}
 		return errors.New(strings.Join(reasons, ", "))
 	}
-	lastBackupFile, err := findBackup(fs, fp.Dir(path))
-	if err != nil {
-		return err
-	}
 	exists, err := afero.Exists(fs, path)
 	if err != nil {
 		return err
@@ -415,18 +595,10 @@ func checkAndWrite(fs afero.Fs, v *viper.Viper, path string) error {
 	// Otherwise, backup the current config file, write the new one, and
 	// try to recover if there's an error.
 	log.Debug("Backing up the current config")
-	backup, err := utils.BackupFile(fs, path)
+	backup, err := backupConfig(fs, path, configBackups(v))
 	if err != nil {
 		return err
 	}
-	log.Debugf("Backed up the current config to %s", backup)
-	if lastBackupFile != "" && lastBackupFile != backup {
-		log.Debug("Removing previous backup file")
-		err = fs.Remove(lastBackupFile)
-		if err != nil {
-			return err
-		}
-	}
 	log.Debugf("Writing the new redpanda config to '%s'", path)
 	err = write(fs, v, path)
 	if err != nil {
@@ -552,7 +724,58 @@ func checkAndPrintRestartWarning(fieldKey string) {
 	}
 }
 
+// ParseValue parses val as the given type. The supported types are "string",
+// "bool", "int", "float", "null" and "json", where "json" decodes val as a
+// JSON value, such as a list or an object. If typ is empty or "auto", the type
+// is inferred from val. Setting a key to null removes it from the config.
+func ParseValue(val, typ string) (interface{}, error) {
+	switch strings.ToLower(typ) {
+	case "", "auto":
+		return parse(val), nil
+	case "string":
+		return val, nil
+	case "bool":
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a bool", val)
+		}
+		return b, nil
+	case "int":
+		i, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an int", val)
+		}
+		return i, nil
+	case "float":
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a float", val)
+		}
+		return f, nil
+	case "null":
+		if val != "" && !isNull(val) {
+			return nil, fmt.Errorf("%q is not null", val)
+		}
+		return nil, nil
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal([]byte(val), &v); err != nil {
+			return nil, fmt.Errorf("%q is not valid JSON: %v", val, err)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+func isNull(val string) bool {
+	return val == "null" || val == "~"
+}
+
 func parse(val string) interface{} {
+	if isNull(val) {
+		return nil
+	}
 	if i, err := strconv.Atoi(val); err == nil {
 		return i
 	}
@@ -562,6 +785,13 @@ func parse(val string) interface{} {
 	if b, err := strconv.ParseBool(val); err == nil {
 		return b
 	}
+	// Lists and maps can be given as JSON.
+	if strings.HasPrefix(val, "[") || strings.HasPrefix(val, "{") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(val), &v); err == nil {
+			return v
+		}
+	}
 	return val
 }
 
@@ -0,0 +1,236 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// OffsetResetTarget is where ResetGroupOffsets moves a group's offsets to.
+type OffsetResetTarget string
+
+const (
+	// ResetEarliest resets to the earliest offset of each partition.
+	ResetEarliest OffsetResetTarget = "earliest"
+	// ResetLatest resets to the end of each partition.
+	ResetLatest OffsetResetTarget = "latest"
+	// ResetTimestamp resets to the first record of each partition at or
+	// after the spec's Timestamp, or to the end of the partition if there
+	// is no such record.
+	ResetTimestamp OffsetResetTarget = "timestamp"
+)
+
+// OffsetResetSpec describes how to reset the committed offsets of a group.
+type OffsetResetSpec struct {
+	// Topics are the topics whose offsets are reset. If empty, the
+	// offsets of every topic the group has committed to are reset.
+	Topics []string
+	// To is where to reset every partition of Topics to. If empty, only
+	// the partitions in Offsets are reset.
+	To OffsetResetTarget
+	// Timestamp is the time to reset to, if To is ResetTimestamp.
+	Timestamp time.Time
+	// Offsets are specific offsets to reset partitions to, per topic and
+	// partition, which take precedence over To.
+	Offsets GroupOffsets
+	// DryRun computes the offsets the group would be reset to without
+	// committing them.
+	DryRun bool
+}
+
+// GroupOffsets are offsets per topic and partition.
+type GroupOffsets map[string]map[int32]int64
+
+func (o GroupOffsets) set(topic string, partition int32, offset int64) {
+	if o[topic] == nil {
+		o[topic] = make(map[int32]int64)
+	}
+	o[topic][partition] = offset
+}
+
+// ResetGroupOffsets resets the committed offsets of the group as described by
+// spec and returns the offsets the group was reset to. The group must not
+// have any active members, otherwise the group coordinator rejects the new
+// offsets.
+//
+// The Redpanda admin API does not manage consumer groups, so this uses the
+// Kafka API of the cluster.
+func ResetGroupOffsets(
+	client sarama.Client,
+	admin sarama.ClusterAdmin,
+	group string,
+	spec OffsetResetSpec,
+) (GroupOffsets, error) {
+	if group == "" {
+		return nil, errors.New("invalid empty group")
+	}
+	switch spec.To {
+	case "", ResetEarliest, ResetLatest:
+	case ResetTimestamp:
+		if spec.Timestamp.IsZero() {
+			return nil, errors.New("invalid offset reset: a timestamp is required to reset to a timestamp")
+		}
+	default:
+		return nil, fmt.Errorf("invalid offset reset target %q", spec.To)
+	}
+	if spec.To == "" && len(spec.Offsets) == 0 {
+		return nil, errors.New("invalid offset reset: either a target or specific offsets are required")
+	}
+
+	partitions, err := resetPartitions(client, admin, group, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(GroupOffsets)
+	for topic, ps := range partitions {
+		for _, p := range ps {
+			offset, ok, err := resetOffset(client, spec, topic, p)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				offsets.set(topic, p, offset)
+			}
+		}
+	}
+	if spec.DryRun || len(offsets) == 0 {
+		return offsets, nil
+	}
+	return offsets, commitOffsets(client, group, offsets)
+}
+
+// resetPartitions returns the partitions of each topic that spec resets.
+func resetPartitions(
+	client sarama.Client,
+	admin sarama.ClusterAdmin,
+	group string,
+	spec OffsetResetSpec,
+) (map[string][]int32, error) {
+	partitions := make(map[string][]int32)
+	if len(spec.Topics) == 0 {
+		resp, err := admin.ListConsumerGroupOffsets(group, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the committed offsets of group %q: %w", group, err)
+		}
+		if resp.Err != sarama.ErrNoError {
+			return nil, fmt.Errorf("unable to list the committed offsets of group %q: %w", group, resp.Err)
+		}
+		for topic, blocks := range resp.Blocks {
+			for p := range blocks {
+				partitions[topic] = append(partitions[topic], p)
+			}
+		}
+	}
+	for _, topic := range spec.Topics {
+		ps, err := client.Partitions(topic)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the partitions of topic %q: %w", topic, err)
+		}
+		partitions[topic] = ps
+	}
+	// Specific offsets are reset even for partitions that the group has
+	// not committed to yet.
+	for topic, ps := range spec.Offsets {
+		for p := range ps {
+			if !containsPartition(partitions[topic], p) {
+				partitions[topic] = append(partitions[topic], p)
+			}
+		}
+	}
+	return partitions, nil
+}
+
+// resetOffset returns the offset spec resets the topic partition to, and
+// whether spec resets the partition at all.
+func resetOffset(
+	client sarama.Client, spec OffsetResetSpec, topic string, partition int32,
+) (int64, bool, error) {
+	earliest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to get the earliest offset of %s/%d: %w", topic, partition, err)
+	}
+	latest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to get the latest offset of %s/%d: %w", topic, partition, err)
+	}
+
+	if offset, ok := spec.Offsets[topic][partition]; ok {
+		if offset < earliest || offset > latest {
+			return 0, false, fmt.Errorf(
+				"offset %d of %s/%d is out of range, which is [%d, %d]",
+				offset, topic, partition, earliest, latest,
+			)
+		}
+		return offset, true, nil
+	}
+
+	switch spec.To {
+	case ResetEarliest:
+		return earliest, true, nil
+	case ResetLatest:
+		return latest, true, nil
+	case ResetTimestamp:
+		ms := spec.Timestamp.UnixNano() / int64(time.Millisecond)
+		offset, err := client.GetOffset(topic, partition, ms)
+		if err != nil {
+			return 0, false, fmt.Errorf("unable to get the offset of %s/%d at %v: %w", topic, partition, spec.Timestamp, err)
+		}
+		// There is no record at or after the timestamp.
+		if offset < 0 {
+			offset = latest
+		}
+		return offset, true, nil
+	}
+	return 0, false, nil
+}
+
+func commitOffsets(client sarama.Client, group string, offsets GroupOffsets) error {
+	coordinator, err := client.Coordinator(group)
+	if err != nil {
+		return fmt.Errorf("unable to find the coordinator of group %q: %w", group, err)
+	}
+	req := &sarama.OffsetCommitRequest{
+		Version:                 2,
+		ConsumerGroup:           group,
+		ConsumerGroupGeneration: sarama.GroupGenerationUndefined,
+		RetentionTime:           -1,
+	}
+	for topic, ps := range offsets {
+		for p, offset := range ps {
+			req.AddBlock(topic, p, offset, sarama.ReceiveTime, "")
+		}
+	}
+	resp, err := coordinator.CommitOffset(req)
+	if err != nil {
+		return fmt.Errorf("unable to commit the offsets of group %q: %w", group, err)
+	}
+	for topic, ps := range resp.Errors {
+		for p, kerr := range ps {
+			if kerr != sarama.ErrNoError {
+				return fmt.Errorf("unable to commit the offset of %s/%d for group %q: %w", topic, p, group, kerr)
+			}
+		}
+	}
+	return nil
+}
+
+func containsPartition(ps []int32, p int32) bool {
+	for _, have := range ps {
+		if have == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/kafka"
+)
+
+func TestResetGroupOffsets(t *testing.T) {
+	const group = "replay"
+	ts := time.Unix(1600000000, 0)
+	tsMs := ts.UnixNano() / int64(time.Millisecond)
+
+	tests := []struct {
+		name      string
+		spec      kafka.OffsetResetSpec
+		exp       kafka.GroupOffsets
+		expCommit bool
+		expErr    bool
+	}{
+		{
+			name:      "earliest for the committed topics",
+			spec:      kafka.OffsetResetSpec{To: kafka.ResetEarliest},
+			exp:       kafka.GroupOffsets{"foo": {0: 10, 1: 20}},
+			expCommit: true,
+		},
+		{
+			name:      "latest for the given topics",
+			spec:      kafka.OffsetResetSpec{Topics: []string{"bar"}, To: kafka.ResetLatest},
+			exp:       kafka.GroupOffsets{"bar": {0: 300}},
+			expCommit: true,
+		},
+		{
+			name: "timestamp",
+			spec: kafka.OffsetResetSpec{To: kafka.ResetTimestamp, Timestamp: ts},
+			// Partition 1 has no record after the timestamp.
+			exp:       kafka.GroupOffsets{"foo": {0: 50, 1: 200}},
+			expCommit: true,
+		},
+		{
+			name: "specific offsets override the target",
+			spec: kafka.OffsetResetSpec{
+				To:      kafka.ResetLatest,
+				Offsets: kafka.GroupOffsets{"foo": {1: 42}, "bar": {0: 7}},
+			},
+			exp:       kafka.GroupOffsets{"foo": {0: 100, 1: 42}, "bar": {0: 7}},
+			expCommit: true,
+		},
+		{
+			name: "dry run",
+			spec: kafka.OffsetResetSpec{To: kafka.ResetEarliest, DryRun: true},
+			exp:  kafka.GroupOffsets{"foo": {0: 10, 1: 20}},
+		},
+		{
+			name:   "specific offset out of range",
+			spec:   kafka.OffsetResetSpec{Offsets: kafka.GroupOffsets{"foo": {0: 101}}},
+			expErr: true,
+		},
+		{
+			name:   "no target",
+			spec:   kafka.OffsetResetSpec{},
+			expErr: true,
+		},
+		{
+			name:   "timestamp without a time",
+			spec:   kafka.OffsetResetSpec{To: kafka.ResetTimestamp},
+			expErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := sarama.NewMockBroker(t, 1)
+			defer broker.Close()
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(t).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetLeader("foo", 0, broker.BrokerID()).
+					SetLeader("foo", 1, broker.BrokerID()).
+					SetLeader("bar", 0, broker.BrokerID()).
+					SetController(broker.BrokerID()),
+				"OffsetRequest": sarama.NewMockOffsetResponse(t).SetVersion(1).
+					SetOffset("foo", 0, sarama.OffsetOldest, 10).
+					SetOffset("foo", 0, sarama.OffsetNewest, 100).
+					SetOffset("foo", 0, tsMs, 50).
+					SetOffset("foo", 1, sarama.OffsetOldest, 20).
+					SetOffset("foo", 1, sarama.OffsetNewest, 200).
+					SetOffset("foo", 1, tsMs, -1).
+					SetOffset("bar", 0, sarama.OffsetOldest, 0).
+					SetOffset("bar", 0, sarama.OffsetNewest, 300),
+				"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+					SetCoordinator(sarama.CoordinatorGroup, group, broker),
+				"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+					SetOffset(group, "foo", 0, 60, "", sarama.ErrNoError).
+					SetOffset(group, "foo", 1, 70, "", sarama.ErrNoError),
+				"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+			})
+
+			cfg := sarama.NewConfig()
+			cfg.Version = sarama.V1_0_0_0
+			client, err := sarama.NewClient([]string{broker.Addr()}, cfg)
+			require.NoError(t, err)
+			defer client.Close()
+			admin, err := sarama.NewClusterAdminFromClient(client)
+			require.NoError(t, err)
+
+			offsets, err := kafka.ResetGroupOffsets(client, admin, group, tt.spec)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, offsets)
+
+			var committed kafka.GroupOffsets
+			for _, rr := range broker.History() {
+				req, ok := rr.Request.(*sarama.OffsetCommitRequest)
+				if !ok {
+					continue
+				}
+				require.Nil(t, committed, "offsets were committed more than once")
+				committed = make(kafka.GroupOffsets)
+				for topic, ps := range tt.exp {
+					committed[topic] = make(map[int32]int64)
+					for p := range ps {
+						offset, _, err := req.Offset(topic, p)
+						require.NoError(t, err)
+						committed[topic][p] = offset
+					}
+				}
+			}
+			if !tt.expCommit {
+				require.Nil(t, committed)
+				return
+			}
+			require.Equal(t, tt.exp, committed)
+		})
+	}
+}
@@ -12,10 +12,12 @@ package os
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +28,9 @@ import (
 
 type Proc interface {
 	RunWithSystemLdPath(timeout time.Duration, command string, args ...string) ([]string, error)
+	// RunWithSystemLdPathContext is like RunWithSystemLdPath, but also
+	// kills the command if ctx is done before it completes.
+	RunWithSystemLdPathContext(ctx context.Context, timeout time.Duration, command string, args ...string) ([]string, error)
 	IsRunning(timeout time.Duration, processName string) bool
 }
 
@@ -38,7 +43,13 @@ type proc struct{}
 func (proc *proc) RunWithSystemLdPath(
 	timeout time.Duration, command string, args ...string,
 ) ([]string, error) {
-	return runWithSystemLdPath(timeout, command, args...)
+	return runWithSystemLdPath(context.Background(), timeout, command, args...)
+}
+
+func (proc *proc) RunWithSystemLdPathContext(
+	ctx context.Context, timeout time.Duration, command string, args ...string,
+) ([]string, error) {
+	return runWithSystemLdPath(ctx, timeout, command, args...)
 }
 
 func (proc *proc) IsRunning(timeout time.Duration, processName string) bool {
@@ -78,8 +89,49 @@ func IsRunningPID(fs afero.Fs, pid int) (bool, error) {
 	return true, nil
 }
 
+// clockTicks is the number of clock ticks per second in which the kernel
+// reports process times, which is 100 on all the supported architectures.
+const clockTicks = 100
+
+// ProcessUptime returns how long ago the process was started, from its start
+// time in /proc/<pid>/stat and the system uptime in /proc/uptime.
+func ProcessUptime(fs afero.Fs, pid int) (time.Duration, error) {
+	stat, err := utils.ReadEnsureSingleLine(fs, fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// The process name, in parenthesis, may have spaces, so the fields
+	// are counted from after it. The start time is the field 22.
+	end := strings.LastIndex(stat, ")")
+	if end < 0 {
+		return 0, fmt.Errorf("corrupt info for process %d", pid)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("corrupt info for process %d", pid)
+	}
+	startTicks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("corrupt start time for process %d: %v", pid, err)
+	}
+	uptime, err := utils.ReadEnsureSingleLine(fs, "/proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+	uptimeFields := strings.Fields(uptime)
+	if len(uptimeFields) == 0 {
+		return 0, errors.New("corrupt /proc/uptime")
+	}
+	systemUptime, err := strconv.ParseFloat(uptimeFields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("corrupt /proc/uptime: %v", err)
+	}
+	started := time.Duration(startTicks) * time.Second / clockTicks
+	return time.Duration(systemUptime*float64(time.Second)) - started, nil
+}
+
 func runWithSystemLdPath(
-	timeout time.Duration, command string, args ...string,
+	ctx context.Context, timeout time.Duration, command string, args ...string,
 ) ([]string, error) {
 	var env []string
 	ldLibraryPathPattern := regexp.MustCompile("^LD_LIBRARY_PATH=.*$")
@@ -88,14 +140,18 @@ func runWithSystemLdPath(
 			env = append(env, v)
 		}
 	}
-	return run(timeout, command, env, args...)
+	return run(ctx, timeout, command, env, args...)
 }
 
 func run(
-	timeout time.Duration, command string, env []string, args ...string,
+	ctx context.Context,
+	timeout time.Duration,
+	command string,
+	env []string,
+	args ...string,
 ) ([]string, error) {
 	log.Debugf("Running command '%s' with arguments '%s'", command, args)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, command, args...)
 	var out bytes.Buffer
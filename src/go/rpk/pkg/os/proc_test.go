@@ -12,6 +12,7 @@ package os_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
@@ -89,3 +90,50 @@ func TestIsRunningPID(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessUptime(t *testing.T) {
+	const stat = "4321 (redpanda main) S 1 1 1 0 -1 4194560 115854 29631806 115 956 443 1316 612807 129163 20 0 1 0 4550 175927296 3830"
+	tests := []struct {
+		name        string
+		stat        string
+		uptime      string
+		expected    time.Duration
+		expectedErr bool
+	}{
+		{
+			name:     "it should return the time since the process started",
+			stat:     stat,
+			uptime:   "100.50 350.12",
+			expected: 55 * time.Second,
+		},
+		{
+			name:        "it should fail if the stat file is corrupt",
+			stat:        "4321 (redpanda) S 1 1",
+			uptime:      "100.50 350.12",
+			expectedErr: true,
+		},
+		{
+			name:        "it should fail if /proc/uptime is corrupt",
+			stat:        stat,
+			uptime:      "lolwut",
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_, err := utils.WriteBytes(fs, []byte(tt.stat), "/proc/4321/stat")
+			require.NoError(t, err)
+			_, err = utils.WriteBytes(fs, []byte(tt.uptime), "/proc/uptime")
+			require.NoError(t, err)
+
+			uptime, err := os.ProcessUptime(fs, 4321)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, uptime)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package out
+
+import "errors"
+
+// ExitCodeError is an error that commands return to have rpk exit with a
+// specific code, instead of 1.
+type ExitCodeError struct {
+	Code int
+	Msg  string
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Msg
+}
+
+// ExitCode returns the code that rpk exits with for err: 0 if it's nil, its
+// code if it is or wraps an *ExitCodeError, and 1 otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ec *ExitCodeError
+	if errors.As(err, &ec) {
+		return ec.Code
+	}
+	return 1
+}
@@ -0,0 +1,73 @@
+package out
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The output formats that commands with structured output support. Table
+// output is specific to each command; PrintStructured handles the rest.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// CheckFormat returns an error if format is not a supported output format.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatTable, FormatJSON, FormatYAML:
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q, must be one of %s, %s, or %s", format, FormatTable, FormatJSON, FormatYAML)
+}
+
+// PrintStructured prints v to stdout as json or yaml, per format.
+func PrintStructured(format string, v interface{}) error {
+	bs, err := Structured(format, v)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(bs)
+	return err
+}
+
+// Structured encodes v as json or yaml, per format, ending with a newline.
+func Structured(format string, v interface{}) ([]byte, error) {
+	var (
+		bs  []byte
+		err error
+	)
+	switch format {
+	case FormatJSON:
+		bs, err = json.MarshalIndent(v, "", "  ")
+		bs = append(bs, '\n')
+	case FormatYAML:
+		bs, err = yaml.Marshal(v)
+	default:
+		return nil, fmt.Errorf("unable to print %q output as a structured format", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode %s output: %w", format, err)
+	}
+	return bs, nil
+}
+
+// RightAlign left-pads each of the values to the width of the widest of
+// them, so that a column of numbers lines up on the right in a table.
+func RightAlign(values ...interface{}) []string {
+	aligned := args2strings(values)
+	var width int
+	for _, v := range aligned {
+		if len(v) > width {
+			width = len(v)
+		}
+	}
+	for i, v := range aligned {
+		aligned[i] = fmt.Sprintf("%*s", width, v)
+	}
+	return aligned
+}
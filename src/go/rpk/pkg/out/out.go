@@ -4,6 +4,7 @@ package out
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
@@ -66,7 +67,12 @@ func NewTable(headers ...string) *TabWriter {
 // NewTable. This function is meant to be used when you may want some column
 // style output (i.e., headers on the left).
 func NewTabWriter() *TabWriter {
-	return &TabWriter{tabwriter.NewWriter(os.Stdout, 6, 4, 2, ' ', 0)}
+	return NewTabWriterTo(os.Stdout)
+}
+
+// NewTabWriterTo returns a TabWriter that writes to w.
+func NewTabWriterTo(w io.Writer) *TabWriter {
+	return &TabWriter{tabwriter.NewWriter(w, 6, 4, 2, ' ', 0)}
 }
 
 // Print stringifies the arguments and calls PrintStrings.
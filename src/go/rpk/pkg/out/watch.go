@@ -0,0 +1,33 @@
+package out
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// clearScreen moves the cursor to the top left corner and clears the
+// terminal.
+const clearScreen = "\033[H\033[2J"
+
+// Watch calls print every interval until it returns true. If the output is a
+// table and stdout is a terminal, the screen is cleared before each print so
+// that the table is redrawn in place; otherwise, tables are separated by an
+// empty line and structured output is printed one document after another.
+func Watch(format string, interval time.Duration, print func() (done bool)) {
+	redraw := format == FormatTable && terminal.IsTerminal(int(os.Stdout.Fd()))
+	for i := 0; ; i++ {
+		switch {
+		case redraw:
+			fmt.Print(clearScreen)
+		case i > 0 && format == FormatTable:
+			fmt.Println()
+		}
+		if print() {
+			return
+		}
+		time.Sleep(interval)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
@@ -24,6 +25,17 @@ import (
 
 type Launcher interface {
 	Start(installDir string, args *RedpandaArgs) error
+	StartInBackground(
+		installDir string, args *RedpandaArgs, logFile string,
+	) (*BackgroundProcess, error)
+}
+
+// BackgroundProcess is a redpanda process started by StartInBackground.
+type BackgroundProcess struct {
+	Pid int
+	// Exited receives the error returned from waiting for the process, or
+	// nil, when it exits.
+	Exited <-chan error
 }
 
 type launcher struct{}
@@ -32,6 +44,21 @@ type RedpandaArgs struct {
 	ConfigFilePath string
 	SeastarFlags   map[string]string
 	ExtraArgs      []string
+	// Env holds KEY=VALUE environment variables that are set for redpanda
+	// on top of the ones inherited from rpk, replacing inherited ones with
+	// the same key. If a key is repeated, the last value wins.
+	Env []string
+	// Output is where redpanda's stdout and stderr are written to.
+	Output OutputFiles
+}
+
+// OutputFiles are the files that redpanda's stdout and stderr are
+// redirected to. Streams without a file are left as they are.
+type OutputFiles struct {
+	Stdout string
+	Stderr string
+	// Append appends to the files instead of truncating them.
+	Append bool
 }
 
 func NewLauncher() Launcher {
@@ -39,13 +66,108 @@ func NewLauncher() Launcher {
 }
 
 func (l *launcher) Start(installDir string, args *RedpandaArgs) error {
-	binary, err := getBinary(installDir)
+	binary, redpandaArgs, rpEnv, err := prepareStart(installDir, args)
+	if err != nil {
+		return err
+	}
+	stdout, stderr, err := openOutput(args.Output)
 	if err != nil {
 		return err
 	}
+	log.Infof(
+		"Running:\n%s %s %s",
+		strings.Join(redactEnv(rpEnv, args.Env), " "), binary, strings.Join(redpandaArgs, " "),
+	)
+	if stdout != nil {
+		log.Infof("Redirecting redpanda's stdout to %s", stdout.Name())
+		err = unix.Dup2(int(stdout.Fd()), unix.Stdout)
+		if err != nil {
+			return fmt.Errorf("unable to redirect redpanda's stdout: %v", err)
+		}
+	}
+	if stderr != nil {
+		log.Infof("Redirecting redpanda's stderr to %s", stderr.Name())
+		err = unix.Dup2(int(stderr.Fd()), unix.Stderr)
+		if err != nil {
+			return fmt.Errorf("unable to redirect redpanda's stderr: %v", err)
+		}
+	}
+	return unix.Exec(binary, redpandaArgs, rpEnv)
+}
+
+// StartInBackground starts redpanda in a new session, so that it keeps
+// running after rpk exits, with its output appended to logFile, or written to
+// rpk's stdout and stderr if logFile is empty.
+func (l *launcher) StartInBackground(
+	installDir string, args *RedpandaArgs, logFile string,
+) (*BackgroundProcess, error) {
+	binary, redpandaArgs, rpEnv, err := prepareStart(installDir, args)
+	if err != nil {
+		return nil, err
+	}
+	cmd := &exec.Cmd{
+		Path:        binary,
+		Args:        redpandaArgs,
+		Env:         rpEnv,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+		SysProcAttr: &syscall.SysProcAttr{Setsid: true},
+	}
+	logTo := "rpk's output"
+	if logFile != "" {
+		err = os.MkdirAll(filepath.Dir(logFile), 0755)
+		if err != nil {
+			return nil, err
+		}
+		out, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the redpanda log file: %v", err)
+		}
+		defer out.Close()
+		cmd.Stdout = out
+		cmd.Stderr = out
+		logTo = logFile
+	}
+	stdout, stderr, err := openOutput(args.Output)
+	if err != nil {
+		return nil, err
+	}
+	// The streams with their own file aren't written to the log file.
+	if stdout != nil {
+		defer stdout.Close()
+		cmd.Stdout = stdout
+	}
+	if stderr != nil {
+		if stderr != stdout {
+			defer stderr.Close()
+		}
+		cmd.Stderr = stderr
+	}
+	log.Infof(
+		"Running in the background, logging to %s:\n%s %s %s",
+		logTo, strings.Join(redactEnv(rpEnv, args.Env), " "), binary, strings.Join(redpandaArgs, " "),
+	)
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	return &BackgroundProcess{Pid: cmd.Process.Pid, Exited: exited}, nil
+}
+
+// prepareStart returns the redpanda binary, and the arguments and
+// environment to run it with.
+func prepareStart(
+	installDir string, args *RedpandaArgs,
+) (string, []string, []string, error) {
+	binary, err := getBinary(installDir)
+	if err != nil {
+		return "", nil, nil, err
+	}
 
 	if args.ConfigFilePath == "" {
-		return errors.New("Redpanda config file is required")
+		return "", nil, nil, errors.New("Redpanda config file is required")
 	}
 	redpandaArgs := collectRedpandaArgs(args)
 	log.Debugf("Starting '%s' with arguments '%v'", binary, redpandaArgs)
@@ -57,8 +179,94 @@ func (l *launcher) Start(installDir string, args *RedpandaArgs) error {
 			rpEnv = append(rpEnv, ev)
 		}
 	}
-	log.Infof("Running:\n%s %s %s", strings.Join(rpEnv, " "), binary, strings.Join(redpandaArgs, " "))
-	return unix.Exec(binary, redpandaArgs, rpEnv)
+	return binary, redpandaArgs, mergeEnv(rpEnv, args.Env), nil
+}
+
+// openOutput opens the files that redpanda's stdout and stderr are
+// redirected to, creating them and their directories if they don't exist.
+// If both streams go to the same file, it's opened once, so that they don't
+// overwrite each other. The returned files are nil for streams that aren't
+// redirected.
+func openOutput(o OutputFiles) (stdout, stderr *os.File, err error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if o.Append {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	open := func(stream, path string) (*os.File, error) {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the directory for redpanda's %s file %s: %v", stream, path, err)
+		}
+		f, err := os.OpenFile(path, flags, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open redpanda's %s file: %v", stream, err)
+		}
+		return f, nil
+	}
+	if o.Stdout != "" {
+		stdout, err = open("stdout", o.Stdout)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if o.Stderr == "" {
+		return stdout, nil, nil
+	}
+	if stdout != nil && filepath.Clean(o.Stderr) == filepath.Clean(o.Stdout) {
+		return stdout, stdout, nil
+	}
+	stderr, err = open("stderr", o.Stderr)
+	if err != nil {
+		if stdout != nil {
+			stdout.Close()
+		}
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// mergeEnv sets the KEY=VALUE variables in overrides on env, replacing the
+// ones with the same key in place and appending the rest.
+func mergeEnv(env, overrides []string) []string {
+	merged := append([]string{}, env...)
+	index := map[string]int{}
+	for i, ev := range merged {
+		index[envKey(ev)] = i
+	}
+	for _, ev := range overrides {
+		k := envKey(ev)
+		if i, ok := index[k]; ok {
+			merged[i] = ev
+			continue
+		}
+		index[k] = len(merged)
+		merged = append(merged, ev)
+	}
+	return merged
+}
+
+// redactEnv hides the values of the variables in env that were set
+// explicitly, since they may hold credentials, before env is logged.
+func redactEnv(env, explicit []string) []string {
+	if len(explicit) == 0 {
+		return env
+	}
+	keys := map[string]bool{}
+	for _, ev := range explicit {
+		keys[envKey(ev)] = true
+	}
+	redacted := make([]string, 0, len(env))
+	for _, ev := range env {
+		if k := envKey(ev); keys[k] {
+			ev = k + "=<redacted>"
+		}
+		redacted = append(redacted, ev)
+	}
+	return redacted
+}
+
+func envKey(ev string) string {
+	return strings.SplitN(ev, "=", 2)[0]
 }
 
 func getBinary(installDir string) (string, error) {
@@ -11,6 +11,9 @@ package redpanda
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -102,3 +105,100 @@ func Test_collectRedpandaArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       []string
+		overrides []string
+		want      []string
+	}{
+		{
+			name: "it should keep the env if there are no overrides",
+			env:  []string{"HOME=/root", "PATH=/bin"},
+			want: []string{"HOME=/root", "PATH=/bin"},
+		},
+		{
+			name:      "it should replace vars in place and append new ones",
+			env:       []string{"HOME=/root", "PATH=/bin"},
+			overrides: []string{"PATH=/usr/bin", "MALLOC_CONF=abort:true"},
+			want:      []string{"HOME=/root", "PATH=/usr/bin", "MALLOC_CONF=abort:true"},
+		},
+		{
+			name:      "it should keep the last value of a repeated override",
+			env:       []string{"HOME=/root"},
+			overrides: []string{"A=1", "A=2", "HOME=/tmp", "HOME="},
+			want:      []string{"HOME=", "A=2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := append([]string{}, tt.env...)
+			got := mergeEnv(env, tt.overrides)
+			require.Exactly(t, tt.want, got)
+			require.Exactly(t, tt.env, env)
+		})
+	}
+}
+
+func TestRedactEnv(t *testing.T) {
+	env := []string{"HOME=/root", "AWS_SECRET_ACCESS_KEY=s3cr3t", "A=1=2"}
+	got := redactEnv(env, []string{"AWS_SECRET_ACCESS_KEY=s3cr3t", "A=1=2"})
+	require.Exactly(
+		t,
+		[]string{"HOME=/root", "AWS_SECRET_ACCESS_KEY=<redacted>", "A=<redacted>"},
+		got,
+	)
+	require.Exactly(t, env, redactEnv(env, nil))
+}
+
+func TestOpenOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpk-launcher-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(o OutputFiles, s string) {
+		stdout, stderr, err := openOutput(o)
+		require.NoError(t, err)
+		for _, f := range []*os.File{stdout, stderr} {
+			if f != nil {
+				_, err = f.WriteString(s)
+				require.NoError(t, err)
+			}
+		}
+		if stdout != nil {
+			require.NoError(t, stdout.Close())
+		}
+		if stderr != nil && stderr != stdout {
+			require.NoError(t, stderr.Close())
+		}
+	}
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	stdout := filepath.Join(dir, "logs", "out.log")
+	stderr := filepath.Join(dir, "logs", "err.log")
+	write(OutputFiles{Stdout: stdout, Stderr: stderr}, "a")
+	write(OutputFiles{Stdout: stdout, Stderr: stderr, Append: true}, "b")
+	require.Equal(t, "ab", read("logs/out.log"))
+	require.Equal(t, "ab", read("logs/err.log"))
+
+	write(OutputFiles{Stdout: stdout}, "c")
+	require.Equal(t, "c", read("logs/out.log"))
+	require.Equal(t, "ab", read("logs/err.log"))
+
+	both := filepath.Join(dir, "both.log")
+	write(OutputFiles{Stdout: both, Stderr: both}, "d")
+	require.Equal(t, "dd", read("both.log"))
+
+	stdoutF, stderrF, err := openOutput(OutputFiles{})
+	require.NoError(t, err)
+	require.Nil(t, stdoutF)
+	require.Nil(t, stderrF)
+
+	_, _, err = openOutput(OutputFiles{Stderr: filepath.Join(dir, "logs")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to open redpanda's stderr file")
+}
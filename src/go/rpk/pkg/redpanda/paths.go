@@ -30,6 +30,18 @@ func GetIOConfigPath(configFileDirectory string) string {
 	return filepath.Join(configFileDirectory, "io-config.yaml")
 }
 
+// GetTunerStatePath returns the path of the file where the tuners save what
+// they changed, so that it can be reverted.
+func GetTunerStatePath(configFileDirectory string) string {
+	return filepath.Join(configFileDirectory, "tuner-state.json")
+}
+
+// GetLogPath returns the path of the file that redpanda logs to when rpk
+// starts it in the background.
+func GetLogPath(dataDirectory string) string {
+	return filepath.Join(dataDirectory, "redpanda.log")
+}
+
 func FindInstallDir(fs afero.Fs) (string, error) {
 	log.Debugf("Looking for redpanda install directory")
 	execPath, err := os.Executable()
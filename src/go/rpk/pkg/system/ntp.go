@@ -10,6 +10,7 @@
 package system
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -26,7 +27,7 @@ import (
 const reachMask int64 = 1
 
 type NtpQuery interface {
-	IsNtpSynced() (bool, error)
+	IsNtpSynced(ctx context.Context) (bool, error)
 }
 
 func NewNtpQuery(timeout time.Duration, fs afero.Fs) NtpQuery {
@@ -43,12 +44,12 @@ type ntpQuery struct {
 	proc    os.Proc
 }
 
-func (q *ntpQuery) IsNtpSynced() (bool, error) {
+func (q *ntpQuery) IsNtpSynced(ctx context.Context) (bool, error) {
 	_, err := exec.LookPath("timedatectl")
 	if err != nil {
 		log.Debug(err)
 	}
-	synced, err := q.checkWithTimedateCtl()
+	synced, err := q.checkWithTimedateCtl(ctx)
 	if err != nil {
 		log.Debug(err)
 	} else {
@@ -58,7 +59,7 @@ func (q *ntpQuery) IsNtpSynced() (bool, error) {
 	if err != nil {
 		log.Debug(err)
 	}
-	synced, err = q.checkWithNtpstat()
+	synced, err = q.checkWithNtpstat(ctx)
 	if err != nil {
 		log.Debug(err)
 	} else {
@@ -68,8 +69,8 @@ func (q *ntpQuery) IsNtpSynced() (bool, error) {
 	return false, errors.New("couldn't check NTP with timedatectl or ntpstat")
 }
 
-func (q *ntpQuery) checkWithTimedateCtl() (bool, error) {
-	output, err := q.proc.RunWithSystemLdPath(q.timeout, "timedatectl", "status")
+func (q *ntpQuery) checkWithTimedateCtl(ctx context.Context) (bool, error) {
+	output, err := q.proc.RunWithSystemLdPathContext(ctx, q.timeout, "timedatectl", "status")
 	if err != nil {
 		return false, err
 	}
@@ -84,9 +85,9 @@ func (q *ntpQuery) checkWithTimedateCtl() (bool, error) {
 	return false, errors.New("NTP sync information not found in timedatectl output")
 }
 
-func (q *ntpQuery) checkWithNtpstat() (bool, error) {
+func (q *ntpQuery) checkWithNtpstat(ctx context.Context) (bool, error) {
 	log.Debugf("Checking NTP sync with ntpstat")
-	_, err := q.proc.RunWithSystemLdPath(q.timeout, "ntpstat")
+	_, err := q.proc.RunWithSystemLdPathContext(ctx, q.timeout, "ntpstat")
 	// ntpstat exits with status other than 0 when NTP is not synced
 	if err != nil {
 		log.Debugf("ntpstat returned an error '%s'", err.Error())
@@ -95,9 +96,9 @@ func (q *ntpQuery) checkWithNtpstat() (bool, error) {
 	return true, nil
 }
 
-func (q *ntpQuery) checkWithNtpq() (bool, error) {
+func (q *ntpQuery) checkWithNtpq(ctx context.Context) (bool, error) {
 	log.Debugf("Checking NTP sync with ntpq")
-	output, err := q.proc.RunWithSystemLdPath(q.timeout, "ntpq", "-p")
+	output, err := q.proc.RunWithSystemLdPathContext(ctx, q.timeout, "ntpq", "-p")
 	if err != nil {
 		log.Debugf("ntpq returned an error: '%s'", err.Error())
 		return false, err
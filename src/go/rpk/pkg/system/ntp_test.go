@@ -10,6 +10,7 @@
 package system
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -26,6 +27,12 @@ func (m *procMock) RunWithSystemLdPath(
 	return m.runFunction(command, args...)
 }
 
+func (m *procMock) RunWithSystemLdPathContext(
+	_ context.Context, _ time.Duration, command string, args ...string,
+) ([]string, error) {
+	return m.runFunction(command, args...)
+}
+
 func (*procMock) IsRunning(_ time.Duration, _ string) bool {
 	return true
 }
@@ -86,7 +93,7 @@ func Test_ntpQuery_checkWithTimedateCtl(t *testing.T) {
 				},
 			}
 			q := &ntpQuery{proc: proc}
-			got, err := q.checkWithTimedateCtl()
+			got, err := q.checkWithTimedateCtl(context.Background())
 			if tt.wantErr {
 				require.Error(t, err)
 				return
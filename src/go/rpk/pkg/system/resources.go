@@ -0,0 +1,82 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	onlineCPUsFile = "/sys/devices/system/cpu/online"
+	memInfoFile    = "/proc/meminfo"
+)
+
+// AvailableCPUs returns the number of online CPUs, or of the CPUs in this
+// process' cgroup cpuset, if there are fewer.
+func AvailableCPUs(fs afero.Fs) (uint64, error) {
+	online, err := afero.ReadFile(fs, onlineCPUsFile)
+	if err != nil {
+		return 0, err
+	}
+	cpus, err := calculateEffectiveCpus(strings.TrimSpace(string(online)))
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %s: %v", onlineCPUsFile, err)
+	}
+	cgroupCpus, err := ReadCgroupEffectiveCpusNo(fs)
+	if err != nil {
+		log.Debugf("Unable to read the cgroup's cpuset: %v", err)
+		return cpus, nil
+	}
+	return min(cpus, cgroupCpus), nil
+}
+
+// AvailableMemBytes returns the total memory of the host, or this process'
+// cgroup memory limit, if it's lower.
+func AvailableMemBytes(fs afero.Fs) (uint64, error) {
+	info, err := afero.ReadFile(fs, memInfoFile)
+	if err != nil {
+		return 0, err
+	}
+	total, err := parseMemTotal(info)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %s: %v", memInfoFile, err)
+	}
+	limit, err := ReadCgroupMemLimitBytes(fs)
+	if err != nil || limit == 0 {
+		log.Debugf("Unable to read the cgroup's memory limit: %v", err)
+		return total, nil
+	}
+	return min(total, limit), nil
+}
+
+// parseMemTotal returns the MemTotal in a /proc/meminfo, in bytes.
+func parseMemTotal(info []byte) (uint64, error) {
+	s := bufio.NewScanner(bytes.NewReader(info))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MemTotal '%s'", fields[1])
+		}
+		return kb * 1024, nil
+	}
+	return 0, errors.New("MemTotal not found")
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package system_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/system"
+)
+
+const memInfo = `MemTotal:       16318460 kB
+MemFree:         1459096 kB
+MemAvailable:    9876540 kB
+`
+
+func TestAvailableCPUs(t *testing.T) {
+	tests := []struct {
+		name        string
+		online      string
+		cpuset      string
+		expected    uint64
+		expectedErr bool
+	}{
+		{
+			name:     "it should count the online CPUs",
+			online:   "0-7,10\n",
+			expected: 9,
+		},
+		{
+			name:     "it should count the CPUs in the cgroup's cpuset if there are fewer",
+			online:   "0-7",
+			cpuset:   "2-3",
+			expected: 2,
+		},
+		{
+			name:        "it should fail if the online CPUs can't be parsed",
+			online:      "0-a",
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, "/sys/devices/system/cpu/online", []byte(tt.online), 0644)
+			require.NoError(t, err)
+			if tt.cpuset != "" {
+				err = setUpCgroup(fs, "/cpuset/cpuset.effective_cpus", tt.cpuset, false)
+				require.NoError(t, err)
+			}
+			cpus, err := system.AvailableCPUs(fs)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cpus)
+		})
+	}
+}
+
+func TestAvailableMemBytes(t *testing.T) {
+	tests := []struct {
+		name        string
+		memInfo     string
+		limit       string
+		expected    uint64
+		expectedErr bool
+	}{
+		{
+			name:     "it should return MemTotal",
+			memInfo:  memInfo,
+			expected: 16318460 * 1024,
+		},
+		{
+			name:     "it should return the cgroup's memory limit if it's lower",
+			memInfo:  memInfo,
+			limit:    "2147483648",
+			expected: 2147483648,
+		},
+		{
+			name:     "it should return MemTotal if the cgroup's limit is higher",
+			memInfo:  memInfo,
+			limit:    "max",
+			expected: 16318460 * 1024,
+		},
+		{
+			name:        "it should fail if there's no MemTotal",
+			memInfo:     "MemFree: 1459096 kB\n",
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, "/proc/meminfo", []byte(tt.memInfo), 0644)
+			require.NoError(t, err)
+			if tt.limit != "" {
+				err = setUpCgroup(fs, "/memory.max", tt.limit, true)
+				require.NoError(t, err)
+			}
+			mem, err := system.AvailableMemBytes(fs)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, mem)
+		})
+	}
+}
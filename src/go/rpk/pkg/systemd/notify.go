@@ -0,0 +1,112 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package systemd implements the sd_notify(3) protocol, with which services
+// started by systemd with Type=notify report their state to it.
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// notifyTimeout is how long sending a notification may block, if systemd
+// isn't reading them.
+const notifyTimeout = time.Second
+
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// Notifier sends state notifications to systemd's notification socket.
+type Notifier struct {
+	socket string
+}
+
+// NewNotifier returns a notifier for the given socket. Sockets in the
+// abstract namespace start with '@'.
+func NewNotifier(socket string) *Notifier {
+	return &Notifier{socket: socket}
+}
+
+// NotifierFromEnv returns a notifier for the socket in $NOTIFY_SOCKET, which
+// systemd sets for Type=notify services.
+func NotifierFromEnv() (*Notifier, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil, errors.New(
+			"$NOTIFY_SOCKET isn't set, the service must be started by" +
+				" systemd with Type=notify",
+		)
+	}
+	return NewNotifier(socket), nil
+}
+
+// Notify sends the states, such as READY=1 or STATUS=<text>, in a single
+// notification.
+func (n *Notifier) Notify(states ...string) error {
+	name := n.socket
+	if strings.HasPrefix(name, "@") {
+		name = "\x00" + name[1:]
+	}
+	conn, err := net.DialUnix(
+		"unixgram",
+		nil,
+		&net.UnixAddr{Name: name, Net: "unixgram"},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %v", n.socket, err)
+	}
+	defer conn.Close()
+	err = conn.SetWriteDeadline(time.Now().Add(notifyTimeout))
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write([]byte(strings.Join(states, "\n")))
+	return err
+}
+
+// WatchdogInterval returns the interval within which systemd expects
+// WATCHDOG=1 notifications from this process, or 0 if the watchdog isn't
+// enabled for it.
+func WatchdogInterval() (time.Duration, error) {
+	return watchdogInterval(
+		os.Getenv("WATCHDOG_USEC"),
+		os.Getenv("WATCHDOG_PID"),
+		os.Getpid(),
+	)
+}
+
+func watchdogInterval(usec, pid string, self int) (time.Duration, error) {
+	if usec == "" {
+		return 0, nil
+	}
+	if pid != "" {
+		p, err := strconv.Atoi(pid)
+		if err != nil {
+			return 0, fmt.Errorf("invalid $WATCHDOG_PID '%s': %v", pid, err)
+		}
+		// The watchdog is meant for another process.
+		if p != self {
+			return 0, nil
+		}
+	}
+	us, err := strconv.ParseUint(usec, 10, 64)
+	if err != nil || us == 0 {
+		return 0, fmt.Errorf("invalid $WATCHDOG_USEC '%s'", usec)
+	}
+	return time.Duration(us) * time.Microsecond, nil
+}
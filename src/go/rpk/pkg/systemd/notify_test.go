@@ -0,0 +1,100 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package systemd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpk-systemd-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram(
+		"unixgram",
+		&net.UnixAddr{Name: socket, Net: "unixgram"},
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	err = NewNotifier(socket).Notify(Ready, "STATUS=Redpanda is ready")
+	require.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, "READY=1\nSTATUS=Redpanda is ready", string(buf[:n]))
+}
+
+func TestNotifyWithoutListener(t *testing.T) {
+	err := NewNotifier("/nonexistent/notify.sock").Notify(Ready)
+	require.Error(t, err)
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		usec        string
+		pid         string
+		expected    time.Duration
+		expectedErr bool
+	}{
+		{
+			name: "it should be disabled if WATCHDOG_USEC isn't set",
+		},
+		{
+			name:     "it should return WATCHDOG_USEC",
+			usec:     "30000000",
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "it should return WATCHDOG_USEC if WATCHDOG_PID is this process",
+			usec:     "500000",
+			pid:      "42",
+			expected: 500 * time.Millisecond,
+		},
+		{
+			name: "it should be disabled if WATCHDOG_PID is another process",
+			usec: "500000",
+			pid:  "1",
+		},
+		{
+			name:        "it should fail if WATCHDOG_USEC is invalid",
+			usec:        "soon",
+			expectedErr: true,
+		},
+		{
+			name:        "it should fail if WATCHDOG_USEC is 0",
+			usec:        "0",
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval, err := watchdogInterval(tt.usec, tt.pid, 42)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, interval)
+		})
+	}
+}
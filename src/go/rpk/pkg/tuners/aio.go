@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -33,7 +34,7 @@ func NewMaxAIOEventsChecker(fs afero.Fs) Checker {
 		func() string {
 			return fmt.Sprintf(">= %d", maxAIOEvents)
 		},
-		func() (int, error) {
+		func(context.Context) (int, error) {
 			return utils.ReadIntFromFile(fs, maxAIOEventsFile)
 		},
 	)
@@ -10,6 +10,9 @@
 package tuners
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"sort"
 	"time"
@@ -22,6 +25,20 @@ import (
 
 func Check(
 	fs afero.Fs, conf *config.Config, timeout time.Duration,
+) ([]CheckResult, error) {
+	return CheckFiltered(fs, conf, timeout, func(CheckerID) bool { return true }, nil)
+}
+
+// CheckFiltered runs the checkers for which include returns true. If
+// checkTimeout is not nil, each checker is aborted if it doesn't complete
+// within the duration it returns for the checker's ID, unless that's zero,
+// and reported as failed with a *CheckTimeoutError.
+func CheckFiltered(
+	fs afero.Fs,
+	conf *config.Config,
+	timeout time.Duration,
+	include func(CheckerID) bool,
+	checkTimeout func(CheckerID) time.Duration,
 ) ([]CheckResult, error) {
 	var results []CheckResult
 	ioConfigFile := redpanda.GetIOConfigPath(filepath.Dir(conf.ConfigFile))
@@ -30,10 +47,20 @@ func Check(
 		return results, err
 	}
 
-	for _, checkers := range checkersMap {
+	for id, checkers := range checkersMap {
+		if !include(id) {
+			continue
+		}
+		var limit time.Duration
+		if checkTimeout != nil {
+			limit = checkTimeout(id)
+		}
 		for _, c := range checkers {
-			result := c.Check()
-			if result.Err != nil {
+			result := runChecker(c, limit)
+			var timeoutErr *CheckTimeoutError
+			if errors.As(result.Err, &timeoutErr) {
+				log.Warnf("System check '%s' %v", c.GetDesc(), timeoutErr)
+			} else if result.Err != nil {
 				if c.GetSeverity() == Fatal {
 					return results, result.Err
 				}
@@ -46,3 +73,40 @@ func Check(
 	sort.Slice(results, func(i, j int) bool { return results[i].Desc < results[j].Desc })
 	return results, nil
 }
+
+// CheckTimeoutError is the error of a check that was aborted because it
+// didn't complete in time.
+type CheckTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *CheckTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %v", e.Timeout)
+}
+
+// runChecker runs the checker, giving up on it after timeout if it's not
+// zero. The timeout cancels the context the checker runs with, which kills
+// the commands that its probe runs with that context. Probes that can't be
+// interrupted keep running in the background until they return, and their
+// result is discarded.
+func runChecker(c Checker, timeout time.Duration) *CheckResult {
+	if timeout <= 0 {
+		return c.Check(context.Background())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	done := make(chan *CheckResult, 1)
+	go func() { done <- c.Check(ctx) }()
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return &CheckResult{
+			CheckerId: c.Id(),
+			Desc:      c.GetDesc(),
+			Severity:  c.GetSeverity(),
+			Required:  c.GetRequiredAsString(),
+			Err:       &CheckTimeoutError{Timeout: timeout},
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tuners
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunChecker(t *testing.T) {
+	current := func(d time.Duration) func(context.Context) (interface{}, error) {
+		return func(context.Context) (interface{}, error) {
+			time.Sleep(d)
+			return true, nil
+		}
+	}
+	fast := NewEqualityChecker(NtpChecker, "NTP Synced", Fatal, true, current(0))
+	result := runChecker(fast, time.Second)
+	require.NoError(t, result.Err)
+	require.True(t, result.IsOk)
+
+	slow := NewEqualityChecker(NtpChecker, "NTP Synced", Fatal, true, current(time.Second))
+	result = runChecker(slow, 10*time.Millisecond)
+	require.False(t, result.IsOk)
+	require.EqualValues(t, NtpChecker, result.CheckerId)
+	require.Equal(t, "NTP Synced", result.Desc)
+	require.Equal(t, "true", result.Required)
+	var timeoutErr *CheckTimeoutError
+	require.True(t, errors.As(result.Err, &timeoutErr))
+	require.EqualError(t, result.Err, "timed out after 10ms")
+
+	result = runChecker(slow, 0)
+	require.NoError(t, result.Err)
+	require.True(t, result.IsOk)
+}
+
+func TestRunCheckerCancelsTheCheck(t *testing.T) {
+	canceled := make(chan struct{})
+	blocked := NewEqualityChecker(
+		NtpChecker,
+		"NTP Synced",
+		Fatal,
+		true,
+		func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			close(canceled)
+			return false, ctx.Err()
+		},
+	)
+	result := runChecker(blocked, 10*time.Millisecond)
+	require.EqualError(t, result.Err, "timed out after 10ms")
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the check wasn't canceled")
+	}
+}
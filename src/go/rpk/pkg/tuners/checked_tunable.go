@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -43,7 +44,7 @@ func (t *checkedTunable) CheckIfSupported() (supported bool, reason string) {
 
 func (t *checkedTunable) Tune() TuneResult {
 	log.Debugf("Checking '%s'", t.checker.GetDesc())
-	result := t.checker.Check()
+	result := t.checker.Check(context.Background())
 	if result.Err != nil {
 		return NewTuneError(result.Err)
 	}
@@ -58,7 +59,7 @@ func (t *checkedTunable) Tune() TuneResult {
 		return NewTuneError(tuneResult.Error())
 	}
 	if !t.disablePostTuneCheck {
-		postTuneResult := t.checker.Check()
+		postTuneResult := t.checker.Check(context.Background())
 		if !postTuneResult.IsOk {
 			severity := t.checker.GetSeverity()
 			msg := fmt.Sprintf(
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -33,7 +34,7 @@ func (c *checkedTunerMock) GetDesc() string {
 	return "mocked check"
 }
 
-func (c *checkedTunerMock) Check() *CheckResult {
+func (c *checkedTunerMock) Check(_ context.Context) *CheckResult {
 	c.checkCalled = true
 	return c.check()
 }
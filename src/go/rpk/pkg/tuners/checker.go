@@ -9,6 +9,8 @@
 
 package tuners
 
+import "context"
+
 type Severity byte
 
 const (
@@ -39,7 +41,7 @@ type CheckResult struct {
 type Checker interface {
 	Id() CheckerID
 	GetDesc() string
-	Check() *CheckResult
+	Check(ctx context.Context) *CheckResult
 	GetRequiredAsString() string
 	GetSeverity() Severity
 }
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -26,7 +27,7 @@ func NewClockSourceChecker(fs afero.Fs) Checker {
 		"Clock Source",
 		Warning,
 		prefferedClkSource,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			content, err := afero.ReadFile(fs,
 				"/sys/devices/system/clocksource/clocksource0/current_clocksource")
 			if err != nil {
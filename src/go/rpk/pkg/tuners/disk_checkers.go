@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/afero"
@@ -42,7 +43,7 @@ func NewDeviceNomergesChecker(
 		fmt.Sprintf("Disk '%s' nomerges tuned", device),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return checkDeviceNomerges(deviceFeatures, device)
 		},
 	)
@@ -59,7 +60,7 @@ func NewDirectoryNomergesChecker(
 		fmt.Sprintf("Dir '%s' nomerges tuned", dir),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			devices, err := blockDevices.GetDirectoryDevices(dir)
 			if err != nil {
 				return false, err
@@ -95,7 +96,7 @@ func NewDeviceSchedulerChecker(
 		fmt.Sprintf("Disk '%s' scheduler tuned", device),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return checkScheduler(deviceFeatures, device)
 		},
 	)
@@ -112,7 +113,7 @@ func NewDirectorySchedulerChecker(
 		fmt.Sprintf("Dir '%s' scheduler tuned", dir),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			devices, err := blockDevices.GetDirectoryDevices(dir)
 			if err != nil {
 				return nil, err
@@ -148,7 +149,7 @@ func NewDeviceWriteCacheChecker(
 		fmt.Sprintf("Disk '%s' write cache tuned", device),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return checkDeviceWriteCache(deviceFeatures, device)
 		},
 	)
@@ -165,7 +166,7 @@ func NewDirectoryWriteCacheChecker(
 		fmt.Sprintf("Dir '%s' write cache tuned", dir),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			devices, err := blockDevices.GetDirectoryDevices(dir)
 			if err != nil {
 				return nil, err
@@ -204,7 +205,7 @@ func NewDisksIRQAffinityStaticChecker(
 		"Disks IRQs affinity static",
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return checkDisksIRQsAffinity(blockDevices, balanceService, devices)
 		},
 	)
@@ -221,7 +222,7 @@ func NewDirectoryIRQsAffinityStaticChecker(
 		fmt.Sprintf("Dir '%s' IRQs affinity static", dir),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			devices, err := blockDevices.GetDirectoryDevices(dir)
 			if err != nil {
 				return nil, err
@@ -264,7 +265,7 @@ func NewDisksIRQAffinityChecker(
 		"Disks IRQs affinity set",
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return areDevicesIRQsDistributed(
 				devices,
 				cpuMask,
@@ -289,7 +290,7 @@ func NewDirectoryIRQAffinityChecker(
 		fmt.Sprintf("Dir '%s' IRQs affinity set", dir),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			devices, err := blockDevices.GetDirectoryDevices(dir)
 			if err != nil {
 				return false, err
@@ -337,3 +338,93 @@ func areDevicesIRQsDistributed(
 	}
 	return true, nil
 }
+
+// NewDirectoryIRQsSpreadChecker checks that the IRQs of the devices backing
+// dir aren't all handled by a single CPU, which caps the disks' throughput at
+// what that CPU can process. It passes if there's a single IRQ or CPU, so
+// there's nothing to spread, and if the IRQs are distributed as the disk_irq
+// tuner would for cpuMask and mode, since in sq mode it assigns them all to
+// CPU 0 on purpose.
+func NewDirectoryIRQsSpreadChecker(
+	fs afero.Fs,
+	dir string,
+	cpuMask string,
+	mode irq.Mode,
+	blockDevices disk.BlockDevices,
+	cpuMasks irq.CpuMasks,
+) Checker {
+	return NewEqualityChecker(
+		DiskIRQsSpreadChecker,
+		fmt.Sprintf("Dir '%s' IRQs spread across CPUs", dir),
+		Warning,
+		true,
+		func(context.Context) (interface{}, error) {
+			devices, err := blockDevices.GetDirectoryDevices(dir)
+			if err != nil {
+				return false, err
+			}
+			return areDevicesIRQsSpread(
+				devices,
+				cpuMask,
+				mode,
+				blockDevices,
+				cpuMasks,
+			)
+		},
+	)
+}
+
+func areDevicesIRQsSpread(
+	devices []string,
+	cpuMask string,
+	mode irq.Mode,
+	blockDevices disk.BlockDevices,
+	cpuMasks irq.CpuMasks,
+) (bool, error) {
+	diskInfoByType, err := blockDevices.GetDiskInfoByType(devices)
+	if err != nil {
+		return false, err
+	}
+	var IRQs []int
+	for _, diskInfo := range diskInfoByType {
+		IRQs = append(IRQs, diskInfo.Irqs...)
+	}
+	if len(IRQs) < 2 {
+		return true, nil
+	}
+	cpus := map[uint]bool{}
+	for _, IRQ := range IRQs {
+		mask, err := cpuMasks.ReadIRQMask(IRQ)
+		if err != nil {
+			return false, err
+		}
+		IRQCpus, err := irq.MaskCPUs(mask)
+		if err != nil {
+			return false, err
+		}
+		for _, cpu := range IRQCpus {
+			cpus[cpu] = true
+		}
+	}
+	if len(cpus) > 1 {
+		return true, nil
+	}
+	allCpus, err := cpuMasks.GetAllCpusMask()
+	if err != nil {
+		return false, err
+	}
+	hostCpus, err := irq.MaskCPUs(allCpus)
+	if err != nil {
+		return false, err
+	}
+	if len(hostCpus) < 2 {
+		return true, nil
+	}
+	return areDevicesIRQsDistributed(
+		devices,
+		cpuMask,
+		mode,
+		blockDevices,
+		cpuMasks,
+	)
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tuners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/disk"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/irq"
+)
+
+func TestAreDevicesIRQsSpread(t *testing.T) {
+	tests := []struct {
+		name     string
+		irqs     []int
+		masks    map[int]string
+		allCpus  string
+		expected bool
+	}{
+		{
+			name:     "it should pass if the IRQs are spread across CPUs",
+			irqs:     []int{10, 11},
+			masks:    map[int]string{10: "0x00000001", 11: "0x00000002"},
+			allCpus:  "0x0000000f",
+			expected: true,
+		},
+		{
+			name:     "it should pass if there's a single IRQ",
+			irqs:     []int{10},
+			masks:    map[int]string{10: "0x00000002"},
+			allCpus:  "0x0000000f",
+			expected: true,
+		},
+		{
+			name:     "it should pass if there's a single CPU",
+			irqs:     []int{10, 11},
+			masks:    map[int]string{10: "0x00000001", 11: "0x00000001"},
+			allCpus:  "0x00000001",
+			expected: true,
+		},
+		{
+			name:     "it should pass if the IRQs are distributed as the tuner would",
+			irqs:     []int{10, 11},
+			masks:    map[int]string{10: "0x00000001", 11: "0x00000001"},
+			allCpus:  "0x0000000f",
+			expected: true,
+		},
+		{
+			name:     "it should fail if the IRQs are all handled by the same CPU",
+			irqs:     []int{10, 11},
+			masks:    map[int]string{10: "0x00000004", 11: "0x00000004"},
+			allCpus:  "0x0000000f",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockDevices := &blockDevicesMock{
+				getDiskInfoByType: func([]string) (map[disk.DiskType]disk.DevicesIRQs, error) {
+					return map[disk.DiskType]disk.DevicesIRQs{
+						disk.NonNvme: {Devices: []string{"sda"}, Irqs: tt.irqs},
+					}, nil
+				},
+			}
+			cpuMasks := &cpuMasksMock{
+				baseCpuMask: func(string) (string, error) {
+					return tt.allCpus, nil
+				},
+				cpuMaskForIRQs: func(irq.Mode, string) (string, error) {
+					return "0x00000001", nil
+				},
+				// In sq mode, the tuner assigns the IRQs of the disks that
+				// aren't NVMe to CPU 0.
+				getIRQsDistributionMasks: func(IRQs []int, cpuMask string) (map[int]string, error) {
+					dist := map[int]string{}
+					for _, IRQ := range IRQs {
+						dist[IRQ] = cpuMask
+					}
+					return dist, nil
+				},
+				readIRQMask: func(IRQ int) (string, error) {
+					return tt.masks[IRQ], nil
+				},
+				getAllCpusMask: func() (string, error) {
+					return tt.allCpus, nil
+				},
+			}
+			spread, err := areDevicesIRQsSpread(
+				[]string{"sda"},
+				"all",
+				irq.Sq,
+				blockDevices,
+				cpuMasks,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, spread)
+		})
+	}
+}
@@ -22,6 +22,8 @@ type cpuMasksMock struct {
 	baseCpuMask              func(string) (string, error)
 	cpuMaskForIRQs           func(irq.Mode, string) (string, error)
 	getIRQsDistributionMasks func([]int, string) (map[int]string, error)
+	readIRQMask              func(int) (string, error)
+	getAllCpusMask           func() (string, error)
 }
 
 type blockDevicesMock struct {
@@ -48,6 +50,14 @@ func (m *cpuMasksMock) GetIRQsDistributionMasks(
 	return m.getIRQsDistributionMasks(IRQs, cpuMask)
 }
 
+func (m *cpuMasksMock) ReadIRQMask(IRQ int) (string, error) {
+	return m.readIRQMask(IRQ)
+}
+
+func (m *cpuMasksMock) GetAllCpusMask() (string, error) {
+	return m.getAllCpusMask()
+}
+
 func (m *blockDevicesMock) GetDirectoriesDevices(
 	directories []string,
 ) (map[string][]string, error) {
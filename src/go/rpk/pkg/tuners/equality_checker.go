@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
@@ -22,7 +23,7 @@ func NewEqualityChecker(
 	desc string,
 	severity Severity,
 	required interface{},
-	getCurrent func() (interface{}, error),
+	getCurrent func(context.Context) (interface{}, error),
 ) Checker {
 	return &equalityChecker{
 		id:         id,
@@ -38,7 +39,7 @@ type equalityChecker struct {
 	desc       string
 	severity   Severity
 	required   interface{}
-	getCurrent func() (interface{}, error)
+	getCurrent func(context.Context) (interface{}, error)
 }
 
 func (c *equalityChecker) Id() CheckerID {
@@ -57,14 +58,14 @@ func (c *equalityChecker) GetRequiredAsString() string {
 	return fmt.Sprint(c.required)
 }
 
-func (c *equalityChecker) Check() *CheckResult {
+func (c *equalityChecker) Check(ctx context.Context) *CheckResult {
 	res := &CheckResult{
 		CheckerId: c.Id(),
 		Desc:      c.GetDesc(),
 		Severity:  c.GetSeverity(),
 		Required:  c.GetRequiredAsString(),
 	}
-	current, err := c.getCurrent()
+	current, err := c.getCurrent(ctx)
 	if err != nil {
 		res.Err = err
 		return res
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -18,14 +19,14 @@ import (
 
 func Test_equalityChecker_Check(t *testing.T) {
 	type fields struct {
-		getCurrent func() (interface{}, error)
+		getCurrent func(context.Context) (interface{}, error)
 		desc       string
 		severity   Severity
 		required   interface{}
 	}
 	tests := []struct {
 		name       string
-		getCurrent func() (interface{}, error)
+		getCurrent func(context.Context) (interface{}, error)
 		desc       string
 		severity   Severity
 		required   interface{}
@@ -34,7 +35,7 @@ func Test_equalityChecker_Check(t *testing.T) {
 		{
 			name:       "Shall return valid result when required == current",
 			desc:       "Some desc",
-			getCurrent: func() (interface{}, error) { return "STR_1", nil },
+			getCurrent: func(context.Context) (interface{}, error) { return "STR_1", nil },
 			required:   "STR_1",
 			severity:   Warning,
 			want: &CheckResult{
@@ -48,7 +49,7 @@ func Test_equalityChecker_Check(t *testing.T) {
 		{
 			name:       "Shall return valid result when required == current for bool",
 			desc:       "Some desc",
-			getCurrent: func() (interface{}, error) { return true, nil },
+			getCurrent: func(context.Context) (interface{}, error) { return true, nil },
 			required:   true,
 			severity:   Warning,
 			want: &CheckResult{
@@ -62,7 +63,7 @@ func Test_equalityChecker_Check(t *testing.T) {
 		{
 			name:       "Shall return not valid result when required != current",
 			desc:       "Some desc",
-			getCurrent: func() (interface{}, error) { return "STR_1", nil },
+			getCurrent: func(context.Context) (interface{}, error) { return "STR_1", nil },
 			required:   "STR_2",
 			severity:   Warning,
 			want: &CheckResult{
@@ -75,7 +76,7 @@ func Test_equalityChecker_Check(t *testing.T) {
 		},
 		{
 			name:       "Shall return result with an error when getCurrent returns an error",
-			getCurrent: func() (interface{}, error) { return "", errors.New("e") },
+			getCurrent: func(context.Context) (interface{}, error) { return "", errors.New("e") },
 			required:   "STR_2",
 			severity:   Warning,
 			want: &CheckResult{
@@ -95,7 +96,7 @@ func Test_equalityChecker_Check(t *testing.T) {
 				tt.required,
 				tt.getCurrent,
 			)
-			got := v.Check()
+			got := v.Check(context.Background())
 			require.Exactly(t, tt.want, got)
 		})
 	}
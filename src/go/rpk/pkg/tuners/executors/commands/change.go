@@ -0,0 +1,24 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands
+
+// Change is what a command changes: a setting, such as a file or a sysctl
+// key, its current value and the value that the command sets.
+type Change struct {
+	Setting string
+	Current string
+	Value   string
+}
+
+// Describer is implemented by the commands that can tell what they would
+// change without executing.
+type Describer interface {
+	Describe() Change
+}
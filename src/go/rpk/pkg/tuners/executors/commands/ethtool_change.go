@@ -12,6 +12,8 @@ package commands
 import (
 	"bufio"
 	"fmt"
+	"sort"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/ethtool"
@@ -51,3 +53,50 @@ func (c *ethtoolChangeCommand) RenderScript(w *bufio.Writer) error {
 	fmt.Fprintln(w)
 	return w.Flush()
 }
+
+func (c *ethtoolChangeCommand) Describe() Change {
+	// The interface's features that can't be read are reported with no
+	// current value.
+	features, _ := c.ethtool.Features(c.intf)
+	var (
+		names   []string
+		current []string
+		value   []string
+	)
+	for feature := range c.config {
+		names = append(names, feature)
+	}
+	sort.Strings(names)
+	for _, feature := range names {
+		if state, ok := features[feature]; ok {
+			current = append(current, feature+" "+onOff(state))
+		}
+		value = append(value, feature+" "+onOff(c.config[feature]))
+	}
+	return Change{
+		Setting: fmt.Sprintf("ethtool features of %s", c.intf),
+		Current: strings.Join(current, ", "),
+		Value:   strings.Join(value, ", "),
+	}
+}
+
+func onOff(state bool) string {
+	if state {
+		return "on"
+	}
+	return "off"
+}
+
+func (c *ethtoolChangeCommand) Previous() (State, error) {
+	features, err := c.ethtool.Features(c.intf)
+	if err != nil {
+		return State{}, err
+	}
+	previous := map[string]bool{}
+	for feature := range c.config {
+		if state, ok := features[feature]; ok {
+			previous[feature] = state
+		}
+	}
+	return State{Kind: EthtoolState, Setting: c.intf, Features: previous}, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+type removeFileCommand struct {
+	fs   afero.Fs
+	path string
+}
+
+func NewRemoveFileCmd(fs afero.Fs, path string) Command {
+	return &removeFileCommand{fs: fs, path: path}
+}
+
+func (c *removeFileCommand) Execute() error {
+	log.Debugf("Removing file '%s'", c.path)
+	err := c.fs.Remove(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *removeFileCommand) RenderScript(w *bufio.Writer) error {
+	fmt.Fprintf(w, "rm -f %s\n", c.path)
+	return w.Flush()
+}
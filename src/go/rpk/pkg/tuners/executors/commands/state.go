@@ -0,0 +1,97 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/ethtool"
+)
+
+const (
+	FileState    = "file"
+	SysctlState  = "sysctl"
+	EthtoolState = "ethtool"
+)
+
+// State is the state of a setting before a command changed it, which can be
+// restored with NewRestoreCmd.
+type State struct {
+	Kind    string `json:"kind"`
+	Setting string `json:"setting"`
+	// Value is the content of a file or the value of a sysctl key.
+	Value string `json:"value,omitempty"`
+	// Missing is set if the file didn't exist.
+	Missing bool `json:"missing,omitempty"`
+	// Features are the interface's ethtool features.
+	Features map[string]bool `json:"features,omitempty"`
+}
+
+// Reversible is implemented by the commands whose changes can be reverted.
+type Reversible interface {
+	// Previous returns the state that executing the command changes.
+	Previous() (State, error)
+}
+
+// NewRestoreCmd returns a command that restores the state.
+func NewRestoreCmd(fs afero.Fs, state State) (Command, error) {
+	switch state.Kind {
+	case FileState:
+		if state.Missing {
+			return NewRemoveFileCmd(fs, state.Setting), nil
+		}
+		return NewWriteFileCmd(fs, state.Setting, state.Value), nil
+	case SysctlState:
+		return NewSysctlSetCmd(state.Setting, state.Value), nil
+	case EthtoolState:
+		wrapper, err := ethtool.NewEthtoolWrapper()
+		if err != nil {
+			return nil, err
+		}
+		return NewEthtoolChangeCmd(wrapper, state.Setting, state.Features), nil
+	}
+	return nil, fmt.Errorf("unknown state kind %q", state.Kind)
+}
+
+// Sysfs files such as /sys/block/<dev>/queue/scheduler list the values they
+// accept on a single line, with the selected one in brackets, but only accept
+// that value when written.
+var selectedValue = regexp.MustCompile(`\[([^\]\s]+)\]`)
+
+// fileState returns the state of the file at path.
+func fileState(fs afero.Fs, path string) (State, error) {
+	content, err := afero.ReadFile(fs, path)
+	if os.IsNotExist(err) {
+		return State{Kind: FileState, Setting: path, Missing: true}, nil
+	}
+	if err != nil {
+		return State{}, err
+	}
+	value := string(content)
+	if isSysfsOption(path, value) {
+		if m := selectedValue.FindStringSubmatch(value); m != nil {
+			value = m[1]
+		}
+	}
+	return State{Kind: FileState, Setting: path, Value: value}, nil
+}
+
+// isSysfsOption returns whether the file may be a sysfs file that lists the
+// values it accepts. Any other file, such as /etc/default/grub, is saved
+// verbatim, even if it has values in brackets.
+func isSysfsOption(path, content string) bool {
+	return strings.HasPrefix(filepath.Clean(path), "/sys/") &&
+		!strings.Contains(strings.TrimSuffix(content, "\n"), "\n")
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+)
+
+func TestWriteFileCmdPreviousAndRestore(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  *string
+		expected commands.State
+		restored *string
+	}{
+		{
+			name:     "it should save the content of the file",
+			content:  strPtr("60\n"),
+			expected: commands.State{Kind: commands.FileState, Setting: "/f", Value: "60\n"},
+			restored: strPtr("60\n"),
+		},
+		{
+			name:     "it should save the selected value of a sysfs file that lists the values it accepts",
+			path:     "/sys/kernel/mm/transparent_hugepage/enabled",
+			content:  strPtr("always [madvise] never\n"),
+			expected: commands.State{Kind: commands.FileState, Setting: "/sys/kernel/mm/transparent_hugepage/enabled", Value: "madvise"},
+			restored: strPtr("madvise"),
+		},
+		{
+			name:     "it should save a file outside of sysfs verbatim",
+			content:  strPtr("always [madvise] never\n"),
+			expected: commands.State{Kind: commands.FileState, Setting: "/f", Value: "always [madvise] never\n"},
+			restored: strPtr("always [madvise] never\n"),
+		},
+		{
+			name:     "it should save a multi-line sysfs file verbatim",
+			path:     "/sys/f",
+			content:  strPtr("a [b]\nc\n"),
+			expected: commands.State{Kind: commands.FileState, Setting: "/sys/f", Value: "a [b]\nc\n"},
+			restored: strPtr("a [b]\nc\n"),
+		},
+		{
+			name:     "it should save that the file didn't exist",
+			expected: commands.State{Kind: commands.FileState, Setting: "/f", Missing: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.path == "" {
+				tt.path = "/f"
+			}
+			fs := afero.NewMemMapFs()
+			if tt.content != nil {
+				require.NoError(t, afero.WriteFile(fs, tt.path, []byte(*tt.content), 0644))
+			}
+			cmd := commands.NewWriteFileCmd(fs, tt.path, "changed")
+			state, err := cmd.(commands.Reversible).Previous()
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, state)
+			require.NoError(t, cmd.Execute())
+
+			restore, err := commands.NewRestoreCmd(fs, state)
+			require.NoError(t, err)
+			require.NoError(t, restore.Execute())
+			content, err := afero.ReadFile(fs, tt.path)
+			if tt.restored == nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, *tt.restored, string(content))
+		})
+	}
+}
+
+func TestWriteFileLinesCmdRestoresMultiLineFile(t *testing.T) {
+	const grub = `GRUB_DEFAULT=0
+# Options such as [quiet] are kept as they are.
+GRUB_CMDLINE_LINUX_DEFAULT="quiet splash"
+`
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/etc/default/grub", []byte(grub), 0644))
+	cmd := commands.NewWriteFileLinesCmd(fs, "/etc/default/grub", []string{"GRUB_DEFAULT=1"})
+	state, err := cmd.(commands.Reversible).Previous()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Execute())
+
+	restore, err := commands.NewRestoreCmd(fs, state)
+	require.NoError(t, err)
+	require.NoError(t, restore.Execute())
+	content, err := afero.ReadFile(fs, "/etc/default/grub")
+	require.NoError(t, err)
+	require.Equal(t, grub, string(content))
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -39,3 +39,21 @@ func (c *sysctlSetCommand) RenderScript(w *bufio.Writer) error {
 	fmt.Fprintf(w, "sysctl -w %s=%s\n", c.key, c.value)
 	return w.Flush()
 }
+
+func (c *sysctlSetCommand) Describe() Change {
+	// A key that can't be read is reported with no current value.
+	current, _ := sysctl.Get(c.key)
+	return Change{
+		Setting: c.key,
+		Current: current,
+		Value:   c.value,
+	}
+}
+
+func (c *sysctlSetCommand) Previous() (State, error) {
+	current, err := sysctl.Get(c.key)
+	if err != nil {
+		return State{}, err
+	}
+	return State{Kind: SysctlState, Setting: c.key, Value: current}, nil
+}
@@ -53,3 +53,12 @@ EOF
 	_, err = fmt.Fprint(w, "sudo systemctl daemon-reload\n")
 	return err
 }
+
+func (cmd *installSystemdUnitCommand) Describe() Change {
+	path := systemd.UnitPath(cmd.name)
+	return Change{
+		Setting: path,
+		Current: readCurrent(cmd.fs, path),
+		Value:   cmd.body,
+	}
+}
@@ -38,3 +38,10 @@ func (cmd *startSystemdUnitCommand) RenderScript(w *bufio.Writer) error {
 	_, err := fmt.Fprintf(w, "sudo systemctl start %s\n", cmd.name)
 	return err
 }
+
+func (cmd *startSystemdUnitCommand) Describe() Change {
+	return Change{
+		Setting: fmt.Sprintf("systemd unit %s", cmd.name),
+		Value:   "started",
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -75,3 +76,25 @@ func (c *writeFileCommand) RenderScript(w *bufio.Writer) error {
 	}
 	return w.Flush()
 }
+
+func (c *writeFileCommand) Describe() Change {
+	return Change{
+		Setting: c.path,
+		Current: readCurrent(c.fs, c.path),
+		Value:   c.content,
+	}
+}
+
+// readCurrent returns the trimmed content of the file at path, or an empty
+// string if it can't be read.
+func readCurrent(fs afero.Fs, path string) string {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func (c *writeFileCommand) Previous() (State, error) {
+	return fileState(c.fs, c.path)
+}
@@ -12,6 +12,7 @@ package commands
 import (
 	"bufio"
 	"fmt"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -47,3 +48,15 @@ func (c *writeFileLinesCommand) RenderScript(w *bufio.Writer) error {
 	fmt.Fprintln(w, "EOF")
 	return w.Flush()
 }
+
+func (c *writeFileLinesCommand) Describe() Change {
+	return Change{
+		Setting: c.path,
+		Current: readCurrent(c.fs, c.path),
+		Value:   strings.Join(c.lines, "\n"),
+	}
+}
+
+func (c *writeFileLinesCommand) Previous() (State, error) {
+	return fileState(c.fs, c.path)
+}
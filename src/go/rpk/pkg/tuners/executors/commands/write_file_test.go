@@ -143,3 +143,24 @@ func TestWriteFileCmdRenderExistingFile(t *testing.T) {
 		t.Errorf("expected:\n\"%s\"\ngot:\n\"%s\"\n", expected, buf.String())
 	}
 }
+
+func TestWriteFileCmdDescribe(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	path := "/proc/sys/vm/swappiness"
+	cmd := commands.NewWriteFileCmd(fs, path, "1")
+	expected := commands.Change{Setting: path, Value: "1"}
+	got := cmd.(commands.Describer).Describe()
+	if got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	err := afero.WriteFile(fs, path, []byte("60\n"), 0644)
+	if err != nil {
+		t.Errorf("got an error writing the file: %v", err)
+	}
+	expected.Current = "60"
+	got = cmd.(commands.Describer).Describe()
+	if got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
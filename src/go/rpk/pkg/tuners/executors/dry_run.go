@@ -0,0 +1,81 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package executors
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+)
+
+// PlannedChange is a change that a command run by the DryRunExecutor would
+// have made. Setting, Current and Value are only set for the commands that
+// can describe their change.
+type PlannedChange struct {
+	Command string `json:"command" yaml:"command"`
+	Setting string `json:"setting,omitempty" yaml:"setting,omitempty"`
+	Current string `json:"current,omitempty" yaml:"current,omitempty"`
+	Value   string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// DryRunExecutor records the changes of the commands it's given, instead of
+// executing them.
+type DryRunExecutor struct {
+	changes []PlannedChange
+}
+
+func NewDryRunExecutor() *DryRunExecutor {
+	return &DryRunExecutor{}
+}
+
+func (e *DryRunExecutor) Execute(cmd commands.Command) error {
+	change, err := describeChange(cmd)
+	if err != nil {
+		return err
+	}
+	e.changes = append(e.changes, change)
+	return nil
+}
+
+func (e *DryRunExecutor) IsLazy() bool {
+	return true
+}
+
+// Changes returns the changes recorded since it was last called.
+func (e *DryRunExecutor) Changes() []PlannedChange {
+	changes := e.changes
+	e.changes = nil
+	return changes
+}
+
+// describeChange returns the change that executing cmd would make, which
+// must be called before cmd is executed for Current to be its current value.
+func describeChange(cmd commands.Command) (PlannedChange, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	err := cmd.RenderScript(w)
+	if err != nil {
+		return PlannedChange{}, err
+	}
+	err = w.Flush()
+	if err != nil {
+		return PlannedChange{}, err
+	}
+	change := PlannedChange{Command: strings.TrimSpace(buf.String())}
+	if d, ok := cmd.(commands.Describer); ok {
+		c := d.Describe()
+		change.Setting = c.Setting
+		change.Current = c.Current
+		change.Value = c.Value
+	}
+	return change, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package executors
+
+import (
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+)
+
+// RecordingExecutor executes the commands it's given, recording the state of
+// what the reversible ones change before they run, and the changes that the
+// commands made.
+type RecordingExecutor struct {
+	states  []commands.State
+	changes []PlannedChange
+}
+
+func NewRecordingExecutor() *RecordingExecutor {
+	return &RecordingExecutor{}
+}
+
+func (e *RecordingExecutor) Execute(cmd commands.Command) error {
+	if r, ok := cmd.(commands.Reversible); ok {
+		state, err := r.Previous()
+		if err != nil {
+			return err
+		}
+		e.states = append(e.states, state)
+	}
+	change, err := describeChange(cmd)
+	if err != nil {
+		return err
+	}
+	err = cmd.Execute()
+	if err != nil {
+		return err
+	}
+	e.changes = append(e.changes, change)
+	return nil
+}
+
+func (e *RecordingExecutor) IsLazy() bool {
+	return false
+}
+
+// States returns the states recorded since it was last called, in the order
+// that the commands ran.
+func (e *RecordingExecutor) States() []commands.State {
+	states := e.states
+	e.states = nil
+	return states
+}
+
+// Changes returns the changes made by the commands that ran successfully
+// since it was last called, in the order that they ran.
+func (e *RecordingExecutor) Changes() []PlannedChange {
+	changes := e.changes
+	e.changes = nil
+	return changes
+}
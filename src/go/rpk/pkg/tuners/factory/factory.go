@@ -47,6 +47,27 @@ var (
 	}
 )
 
+// tunerDescriptions are the one line descriptions of the tuners in allTuners.
+var tunerDescriptions = map[string]string{
+	"disk_irq":              "Distributes the disks' IRQs across the CPUs",
+	"disk_scheduler":        "Sets the disks' I/O scheduler to none or noop",
+	"disk_nomerges":         "Disables merging adjacent I/O requests on the disks",
+	"disk_write_cache":      "Disables the write cache of GCP local SSDs",
+	"fstrim":                "Runs fstrim weekly to discard unused SSD blocks",
+	"net":                   "Distributes the NICs' IRQs and queues across the CPUs",
+	"cpu":                   "Sets the CPU governor to performance and disables power saving states",
+	"aio_events":            "Raises the maximum number of concurrent AIO requests",
+	"clocksource":           "Sets the clock source to TSC",
+	"swappiness":            "Lowers vm.swappiness, if there is swap, to keep data in memory",
+	"transparent_hugepages": "Sets the transparent huge pages enabled and defrag modes",
+	"coredump":              "Enables coredumps and sets where they are saved",
+}
+
+// TunerDescription returns a one line description of the tuner.
+func TunerDescription(tuner string) string {
+	return tunerDescriptions[tuner]
+}
+
 type TunerParams struct {
 	Mode          string
 	CpuMask       string
@@ -93,6 +114,34 @@ func NewScriptRenderingTunersFactory(
 	return newTunersFactory(fs, conf, irqProcFile, proc, irqDeviceInfo, executor, timeout)
 }
 
+// NewRecordingTunersFactory returns a factory whose tuners record what they
+// change in executor, so that it can be reverted.
+func NewRecordingTunersFactory(
+	fs afero.Fs,
+	conf config.Config,
+	executor *executors.RecordingExecutor,
+	timeout time.Duration,
+) TunersFactory {
+	irqProcFile := irq.NewProcFile(fs)
+	proc := os.NewProc()
+	irqDeviceInfo := irq.NewDeviceInfo(fs, irqProcFile)
+	return newTunersFactory(fs, conf, irqProcFile, proc, irqDeviceInfo, executor, timeout)
+}
+
+// NewDryRunTunersFactory returns a factory whose tuners record their changes
+// in executor instead of making them.
+func NewDryRunTunersFactory(
+	fs afero.Fs,
+	conf config.Config,
+	executor *executors.DryRunExecutor,
+	timeout time.Duration,
+) TunersFactory {
+	irqProcFile := irq.NewProcFile(fs)
+	proc := os.NewProc()
+	irqDeviceInfo := irq.NewDeviceInfo(fs, irqProcFile)
+	return newTunersFactory(fs, conf, irqProcFile, proc, irqDeviceInfo, executor, timeout)
+}
+
 func newTunersFactory(
 	fs afero.Fs,
 	conf config.Config,
@@ -271,11 +320,16 @@ func (factory *tunersFactory) newClockSourceTuner(
 func (factory *tunersFactory) newSwappinessTuner(
 	params *TunerParams,
 ) tuners.Tunable {
-	return tuners.NewSwappinessTuner(factory.fs, factory.executor)
+	return tuners.NewSwappinessTuner(
+		factory.fs,
+		tuners.SwappinessTarget(factory.conf.Rpk),
+		factory.executor,
+	)
 }
 
 func (factory *tunersFactory) newTHPTuner(_ *TunerParams) tuners.Tunable {
-	return tuners.NewEnableTHPTuner(factory.fs, factory.executor)
+	enabled, defrag := tuners.THPSettings(factory.conf.Rpk)
+	return tuners.NewTHPTuner(factory.fs, enabled, defrag, factory.executor)
 }
 
 func (factory *tunersFactory) newCoredumpTuner(
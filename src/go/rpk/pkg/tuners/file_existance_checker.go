@@ -9,7 +9,11 @@
 
 package tuners
 
-import "github.com/spf13/afero"
+import (
+	"context"
+
+	"github.com/spf13/afero"
+)
 
 func NewFileExistanceChecker(
 	fs afero.Fs, id CheckerID, desc string, severity Severity, filePath string,
@@ -19,7 +23,7 @@ func NewFileExistanceChecker(
 		desc,
 		severity,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return afero.Exists(fs, filePath)
 		})
 }
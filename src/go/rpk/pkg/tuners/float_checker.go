@@ -9,7 +9,10 @@
 
 package tuners
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 func NewFloatChecker(
 	id CheckerID,
@@ -17,7 +20,7 @@ func NewFloatChecker(
 	severity Severity,
 	check func(float64) bool,
 	renderRequired func() string,
-	getCurrent func() (float64, error),
+	getCurrent func(context.Context) (float64, error),
 ) Checker {
 	return &floatChecker{
 		id:             id,
@@ -34,7 +37,7 @@ type floatChecker struct {
 	desc           string
 	check          func(float64) bool
 	renderRequired func() string
-	getCurrent     func() (float64, error)
+	getCurrent     func(context.Context) (float64, error)
 	severity       Severity
 }
 
@@ -54,14 +57,14 @@ func (c *floatChecker) GetRequiredAsString() string {
 	return c.renderRequired()
 }
 
-func (c *floatChecker) Check() *CheckResult {
+func (c *floatChecker) Check(ctx context.Context) *CheckResult {
 	res := &CheckResult{
 		CheckerId: c.Id(),
 		Desc:      c.GetDesc(),
 		Severity:  c.GetSeverity(),
 		Required:  c.GetRequiredAsString(),
 	}
-	current, err := c.getCurrent()
+	current, err := c.getCurrent(ctx)
 	if err != nil {
 		res.Err = err
 		return res
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -23,7 +24,7 @@ func Test_floatChecker_Check(t *testing.T) {
 		name           string
 		check          func(c float64) bool
 		renderRequired func() string
-		getCurrent     func() (float64, error)
+		getCurrent     func(context.Context) (float64, error)
 		desc           string
 		severity       Severity
 		want           *CheckResult
@@ -33,7 +34,7 @@ func Test_floatChecker_Check(t *testing.T) {
 			check:          func(c float64) bool { return c >= 0.0 },
 			renderRequired: func() string { return ">= 0.0" },
 			desc:           "Some desc",
-			getCurrent:     func() (float64, error) { return 0.0, nil },
+			getCurrent:     func(context.Context) (float64, error) { return 0.0, nil },
 			severity:       Warning,
 			want: &CheckResult{
 				IsOk:     true,
@@ -48,7 +49,7 @@ func Test_floatChecker_Check(t *testing.T) {
 			check:          func(c float64) bool { return c == 0.1 },
 			renderRequired: func() string { return "0.1" },
 			desc:           "Some desc",
-			getCurrent:     func() (float64, error) { return 1.1, nil },
+			getCurrent:     func(context.Context) (float64, error) { return 1.1, nil },
 			severity:       Warning,
 			want: &CheckResult{
 				IsOk:     false,
@@ -63,7 +64,7 @@ func Test_floatChecker_Check(t *testing.T) {
 			name:           "Shall return result with an error when getCurretn returns an error",
 			check:          func(c float64) bool { return c < 10.0 },
 			renderRequired: func() string { return "< 10" },
-			getCurrent:     func() (float64, error) { return 0.0, errors.New("err") },
+			getCurrent:     func(context.Context) (float64, error) { return 0.0, errors.New("err") },
 			severity:       Warning,
 			want: &CheckResult{
 				IsOk:     false,
@@ -82,7 +83,7 @@ func Test_floatChecker_Check(t *testing.T) {
 				desc:           tt.desc,
 				severity:       tt.severity,
 			}
-			got := v.Check()
+			got := v.Check(context.Background())
 			require.Exactly(t, tt.want, got)
 		})
 	}
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"time"
 
 	"github.com/pkg/errors"
@@ -131,7 +132,7 @@ func NewFstrimChecker() Checker {
 		"Fstrim systemd service and timer active",
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			c, err := systemd.NewDbusClient()
 			if err != nil {
 				return false, err
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -37,6 +38,13 @@ func (p *mockProc) RunWithSystemLdPath(
 	}
 	return []string{fstrimBinPath}, nil
 }
+
+func (p *mockProc) RunWithSystemLdPathContext(
+	_ context.Context, timeout time.Duration, cmd string, args ...string,
+) ([]string, error) {
+	return p.RunWithSystemLdPath(timeout, cmd, args...)
+}
+
 func (*mockProc) IsRunning(_ time.Duration, _ string) bool {
 	return true
 }
@@ -9,7 +9,10 @@
 
 package tuners
 
-import "strconv"
+import (
+	"context"
+	"strconv"
+)
 
 func NewIntChecker(
 	id CheckerID,
@@ -17,7 +20,7 @@ func NewIntChecker(
 	severity Severity,
 	check func(int) bool,
 	renderRequired func() string,
-	getCurrent func() (int, error),
+	getCurrent func(context.Context) (int, error),
 ) Checker {
 	return &intChecker{
 		id:             id,
@@ -34,7 +37,7 @@ type intChecker struct {
 	desc           string
 	check          func(int) bool
 	renderRequired func() string
-	getCurrent     func() (int, error)
+	getCurrent     func(context.Context) (int, error)
 	severity       Severity
 }
 
@@ -54,14 +57,14 @@ func (c *intChecker) GetRequiredAsString() string {
 	return c.renderRequired()
 }
 
-func (c *intChecker) Check() *CheckResult {
+func (c *intChecker) Check(ctx context.Context) *CheckResult {
 	res := &CheckResult{
 		CheckerId: c.Id(),
 		Desc:      c.GetDesc(),
 		Severity:  c.GetSeverity(),
 		Required:  c.GetRequiredAsString(),
 	}
-	current, err := c.getCurrent()
+	current, err := c.getCurrent(ctx)
 	if err != nil {
 		res.Err = err
 		return res
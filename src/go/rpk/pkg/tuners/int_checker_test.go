@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -21,7 +22,7 @@ func Test_intChecker_Check(t *testing.T) {
 		name           string
 		check          func(c int) bool
 		renderRequired func() string
-		getCurrent     func() (int, error)
+		getCurrent     func(context.Context) (int, error)
 		desc           string
 		severity       Severity
 		want           *CheckResult
@@ -30,7 +31,7 @@ func Test_intChecker_Check(t *testing.T) {
 			name:           "Shall return valid result when condition is met",
 			check:          func(c int) bool { return c == 0 },
 			renderRequired: func() string { return "0" },
-			getCurrent:     func() (int, error) { return 0, nil },
+			getCurrent:     func(context.Context) (int, error) { return 0, nil },
 			want: &CheckResult{
 				IsOk:     true,
 				Current:  "0",
@@ -43,7 +44,7 @@ func Test_intChecker_Check(t *testing.T) {
 			name:           "Shall return not valid result when condition is not met",
 			check:          func(c int) bool { return c == 0 },
 			renderRequired: func() string { return "0" },
-			getCurrent:     func() (int, error) { return 1, nil },
+			getCurrent:     func(context.Context) (int, error) { return 1, nil },
 			want: &CheckResult{
 				IsOk:     false,
 				Current:  "1",
@@ -56,7 +57,7 @@ func Test_intChecker_Check(t *testing.T) {
 			name:           "Shall return result with an error when getCurrent returns an error",
 			check:          func(c int) bool { return c == 0 },
 			renderRequired: func() string { return "0" },
-			getCurrent:     func() (int, error) { return 0, errors.New("err") },
+			getCurrent:     func(context.Context) (int, error) { return 0, errors.New("err") },
 			want: &CheckResult{
 				IsOk:     false,
 				Desc:     "An int check",
@@ -76,7 +77,7 @@ func Test_intChecker_Check(t *testing.T) {
 				tt.renderRequired,
 				tt.getCurrent,
 			)
-			got := v.Check()
+			got := v.Check(context.Background())
 			require.Exactly(t, tt.want, got)
 		})
 	}
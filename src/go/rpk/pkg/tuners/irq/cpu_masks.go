@@ -246,6 +246,27 @@ func MasksEqual(a, b string) (bool, error) {
 	return true, nil
 }
 
+// MaskCPUs returns the IDs of the CPUs set in mask, in ascending order. The
+// mask is made of comma separated 32 bit words, the most significant first,
+// as in /proc/irq/<IRQ>/smp_affinity.
+func MaskCPUs(mask string) ([]uint, error) {
+	parts := strings.Split(strings.TrimSpace(mask), ",")
+	var cpus []uint
+	for i := len(parts) - 1; i >= 0; i-- {
+		word, err := parseMask(parts[i])
+		if err != nil {
+			return nil, err
+		}
+		base := uint(len(parts)-1-i) * 32
+		for bit := uint(0); bit < 32; bit++ {
+			if word&(1<<bit) != 0 {
+				cpus = append(cpus, base+bit)
+			}
+		}
+	}
+	return cpus, nil
+}
+
 func parseMask(mask string) (uint, error) {
 	if mask == "" {
 		return 0, nil
@@ -71,3 +71,34 @@ func Test_masksEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestMaskCPUs(t *testing.T) {
+	tests := []struct {
+		name string
+		mask string
+		want []uint
+	}{
+		{
+			name: "it should return the CPUs in a single word mask",
+			mask: "0x00000013",
+			want: []uint{0, 1, 4},
+		},
+		{
+			name: "it should return the CPUs in a multi word mask",
+			mask: "0x1,,0x80000000",
+			want: []uint{31, 64},
+		},
+		{
+			name: "it should return no CPUs for an empty mask",
+			mask: "00000000",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MaskCPUs(tt.mask)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -9,7 +9,10 @@
 
 package tuners
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 const (
 	ExpectedKernelVersion string = "4.19"
@@ -41,7 +44,7 @@ func (c kernelVersionChecker) GetRequiredAsString() string {
 	return "4.19"
 }
 
-func (c kernelVersionChecker) Check() *CheckResult {
+func (c kernelVersionChecker) Check(_ context.Context) *CheckResult {
 	res := &CheckResult{
 		CheckerId: c.Id(),
 		Desc:      c.GetDesc(),
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -131,7 +132,7 @@ func Test_kernelVersionChecker_Check(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v := NewKernelVersionChecker(tt.getCurrent)
-			got := v.Check()
+			got := v.Check(context.Background())
 			require.Exactly(t, tt.want, got)
 		})
 	}
@@ -10,6 +10,7 @@
 package tuners
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -74,7 +75,7 @@ func (f *netCheckersFactory) NewNicIRQAffinityStaticChecker(
 		"NIC IRQs affinity static",
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			var IRQs []int
 			for _, ifaceName := range interfaces {
 				nic := network.NewNic(f.fs, f.irqProcFile, f.irqDeviceInfo, f.ethtool, ifaceName)
@@ -97,7 +98,7 @@ func (f *netCheckersFactory) NewNicIRQAffinityChecker(
 		fmt.Sprintf("NIC %s IRQ affinity set", nic.Name()),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return isSet(nic, func(currentNic network.Nic) (bool, error) {
 				dist, err := network.GetHwInterfaceIRQsDistribution(
 					currentNic, mode, cpuMask, f.cpuMasks)
@@ -149,7 +150,7 @@ func (f *netCheckersFactory) NewNicRpsSetChecker(
 		fmt.Sprintf("NIC %s RPS set", nic.Name()),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return isSet(nic, func(currentNic network.Nic) (bool, error) {
 				rpsCPUs, err := currentNic.GetRpsCPUFiles()
 				if err != nil {
@@ -188,7 +189,7 @@ func (f *netCheckersFactory) NewNicRfsChecker(nic network.Nic) Checker {
 		fmt.Sprintf("NIC %s RFS set", nic.Name()),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return isSet(nic, func(currentNic network.Nic) (bool, error) {
 				limits, err := currentNic.GetRpsLimitFiles()
 				queueLimit := network.OneRPSQueueLimit(limits)
@@ -222,7 +223,7 @@ func (f *netCheckersFactory) NewNicNTupleChecker(nic network.Nic) Checker {
 		fmt.Sprintf("NIC %s NTuple set", nic.Name()),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return isSet(nic, func(currentNic network.Nic) (bool, error) {
 				nTupleStatus, err := currentNic.GetNTupleStatus()
 				if err != nil {
@@ -247,7 +248,7 @@ func (f *netCheckersFactory) NewNicXpsChecker(nic network.Nic) Checker {
 		fmt.Sprintf("NIC %s XPS set", nic.Name()),
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return isSet(nic, func(currentNic network.Nic) (bool, error) {
 				xpsCPUFiles, err := currentNic.GetXpsCPUFiles()
 				if err != nil {
@@ -290,7 +291,7 @@ func (f *netCheckersFactory) NewRfsTableSizeChecker() Checker {
 		func() string {
 			return fmt.Sprintf(">= %d", network.RfsTableSize)
 		},
-		func() (int, error) {
+		func(context.Context) (int, error) {
 			value, err := sysctl.Get(network.RfsTableSizeProperty)
 			if err != nil {
 				return 0, err
@@ -311,7 +312,7 @@ func (f *netCheckersFactory) NewListenBacklogChecker() Checker {
 		func() string {
 			return fmt.Sprintf(">= %d", network.ListenBacklogSize)
 		},
-		func() (int, error) {
+		func(context.Context) (int, error) {
 			return utils.ReadIntFromFile(f.fs, network.ListenBacklogFile)
 		},
 	)
@@ -328,7 +329,7 @@ func (f *netCheckersFactory) NewSynBacklogChecker() Checker {
 		func() string {
 			return fmt.Sprintf(">= %d", network.SynBacklogSize)
 		},
-		func() (int, error) {
+		func(context.Context) (int, error) {
 			return utils.ReadIntFromFile(f.fs, network.SynBacklogFile)
 		},
 	)
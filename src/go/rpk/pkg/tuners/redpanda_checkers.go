@@ -10,7 +10,10 @@
 package tuners
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/spf13/afero"
@@ -59,15 +62,113 @@ const (
 	Swappiness
 	KernelVersion
 	WriteCachePolicyChecker
+	DiskIRQsSpreadChecker
+	TransparentHugePagesDefragChecker
 )
 
+var checkerNames = map[CheckerID]string{
+	ConfigFileChecker:                 "config_file",
+	DataDirAccessChecker:              "data_dir_access",
+	DiskSpaceChecker:                  "disk_space",
+	FreeMemChecker:                    "free_memory",
+	SwapChecker:                       "swap",
+	FsTypeChecker:                     "filesystem_type",
+	IoConfigFileChecker:               "io_config_file",
+	TransparentHugePagesChecker:       "transparent_hugepages",
+	NtpChecker:                        "ntp",
+	SchedulerChecker:                  "disk_scheduler",
+	NomergesChecker:                   "disk_nomerges",
+	DiskIRQsAffinityStaticChecker:     "disk_irqs_affinity_static",
+	DiskIRQsAffinityChecker:           "disk_irqs_affinity",
+	FstrimChecker:                     "fstrim",
+	NicIRQsAffinitChecker:             "nic_irqs_affinity",
+	NicIRQsAffinitStaticChecker:       "nic_irqs_affinity_static",
+	NicRfsChecker:                     "nic_rfs",
+	NicXpsChecker:                     "nic_xps",
+	NicRpsChecker:                     "nic_rps",
+	NicNTupleChecker:                  "nic_ntuple",
+	RfsTableEntriesChecker:            "rfs_table_entries",
+	ListenBacklogChecker:              "listen_backlog",
+	SynBacklogChecker:                 "syn_backlog",
+	MaxAIOEvents:                      "max_aio_events",
+	ClockSource:                       "clocksource",
+	Swappiness:                        "swappiness",
+	KernelVersion:                     "kernel_version",
+	WriteCachePolicyChecker:           "write_cache_policy",
+	DiskIRQsSpreadChecker:             "disk_irqs_spread",
+	TransparentHugePagesDefragChecker: "transparent_hugepages_defrag",
+}
+
+// String returns the checker's name, which is stable, so that it can be
+// used to refer to the checker in scripts.
+func (id CheckerID) String() string {
+	if name, ok := checkerNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("checker_%d", int(id))
+}
+
+// checkerTuners are the tuners that change what the checkers check, so that
+// running them fixes the checks that failed.
+var checkerTuners = map[CheckerID]string{
+	TransparentHugePagesChecker:       "transparent_hugepages",
+	TransparentHugePagesDefragChecker: "transparent_hugepages",
+	SchedulerChecker:                  "disk_scheduler",
+	NomergesChecker:                   "disk_nomerges",
+	DiskIRQsAffinityStaticChecker:     "disk_irq",
+	DiskIRQsAffinityChecker:           "disk_irq",
+	DiskIRQsSpreadChecker:             "disk_irq",
+	FstrimChecker:                     "fstrim",
+	NicIRQsAffinitChecker:             "net",
+	NicIRQsAffinitStaticChecker:       "net",
+	NicRfsChecker:                     "net",
+	NicXpsChecker:                     "net",
+	NicRpsChecker:                     "net",
+	NicNTupleChecker:                  "net",
+	RfsTableEntriesChecker:            "net",
+	ListenBacklogChecker:              "net",
+	SynBacklogChecker:                 "net",
+	MaxAIOEvents:                      "aio_events",
+	ClockSource:                       "clocksource",
+	Swappiness:                        "swappiness",
+	WriteCachePolicyChecker:           "disk_write_cache",
+}
+
+// Tuner returns the name of the tuner that fixes the checker's check when it
+// fails, if there's one.
+func (id CheckerID) Tuner() (string, bool) {
+	tuner, ok := checkerTuners[id]
+	return tuner, ok
+}
+
+// CheckerIDByName returns the ID of the checker with the given name, as it's
+// returned by CheckerID.String.
+func CheckerIDByName(name string) (CheckerID, bool) {
+	for id, n := range checkerNames {
+		if n == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// CheckerNames returns the names of all the checkers, sorted.
+func CheckerNames() []string {
+	names := make([]string, 0, len(checkerNames))
+	for _, name := range checkerNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func NewConfigChecker(conf *config.Config) Checker {
 	return NewEqualityChecker(
 		ConfigFileChecker,
 		"Config file valid",
 		Fatal,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			ok, _ := config.Check(conf)
 			return ok, nil
 		})
@@ -79,7 +180,7 @@ func NewDataDirWritableChecker(fs afero.Fs, path string) Checker {
 		"Data directory is writable",
 		Fatal,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return filesystem.DirectoryIsWriteable(fs, path)
 		})
 }
@@ -95,7 +196,7 @@ func NewFreeDiskSpaceChecker(path string) Checker {
 		func() string {
 			return ">= 10"
 		},
-		func() (float64, error) {
+		func(context.Context) (float64, error) {
 			return filesystem.GetFreeDiskSpaceGB(path)
 		})
 }
@@ -111,7 +212,7 @@ func NewMemoryChecker(fs afero.Fs) Checker {
 		func() string {
 			return "2048 per CPU"
 		},
-		func() (int, error) {
+		func(context.Context) (int, error) {
 			effCpus, err := system.ReadCgroupEffectiveCpusNo(fs)
 			if err != nil {
 				return 0, err
@@ -132,7 +233,7 @@ func NewSwapChecker(fs afero.Fs) Checker {
 		"Swap enabled",
 		Warning,
 		true,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return system.IsSwapEnabled(fs)
 		},
 	)
@@ -144,7 +245,7 @@ func NewFilesystemTypeChecker(path string) Checker {
 		"Data directory filesystem type",
 		Warning,
 		filesystem.Xfs,
-		func() (interface{}, error) {
+		func(context.Context) (interface{}, error) {
 			return filesystem.GetFilesystemType(path)
 		})
 }
@@ -164,8 +265,8 @@ func NewNTPSyncChecker(timeout time.Duration, fs afero.Fs) Checker {
 		"NTP Synced",
 		Warning,
 		true,
-		func() (interface{}, error) {
-			return system.NewNtpQuery(timeout, fs).IsNtpSynced()
+		func(ctx context.Context) (interface{}, error) {
+			return system.NewNtpQuery(timeout, fs).IsNtpSynced(ctx)
 		},
 	)
 }
@@ -202,6 +303,8 @@ func RedpandaCheckers(
 	cpuMasks := irq.NewCpuMasks(fs, hwloc.NewHwLocCmd(proc, timeout), executor)
 	dirIRQAffinityChecker := NewDirectoryIRQAffinityChecker(
 		fs, config.Redpanda.Directory, "all", irq.Default, blockDevices, cpuMasks)
+	dirIRQsSpreadChecker := NewDirectoryIRQsSpreadChecker(
+		fs, config.Redpanda.Directory, "all", irq.Default, blockDevices, cpuMasks)
 	dirIRQAffinityStaticChecker := NewDirectoryIRQsAffinityStaticChecker(
 		fs,
 		config.Redpanda.Directory,
@@ -220,33 +323,36 @@ func RedpandaCheckers(
 	}
 	netCheckersFactory := NewNetCheckersFactory(
 		fs, irqProcFile, irqDeviceInfo, ethtool, balanceService, cpuMasks)
+	thpEnabled, thpDefrag := THPSettings(config.Rpk)
 	checkers := map[CheckerID][]Checker{
-		ConfigFileChecker:             {NewConfigChecker(config)},
-		IoConfigFileChecker:           {NewIOConfigFileExistanceChecker(fs, ioConfigFile)},
-		FreeMemChecker:                {NewMemoryChecker(fs)},
-		SwapChecker:                   {NewSwapChecker(fs)},
-		DataDirAccessChecker:          {NewDataDirWritableChecker(fs, config.Redpanda.Directory)},
-		DiskSpaceChecker:              {NewFreeDiskSpaceChecker(config.Redpanda.Directory)},
-		FsTypeChecker:                 {NewFilesystemTypeChecker(config.Redpanda.Directory)},
-		TransparentHugePagesChecker:   {NewTransparentHugePagesChecker(fs)},
-		NtpChecker:                    {NewNTPSyncChecker(timeout, fs)},
-		SchedulerChecker:              {schedulerChecker},
-		NomergesChecker:               {nomergesChecker},
-		DiskIRQsAffinityChecker:       {dirIRQAffinityChecker},
-		DiskIRQsAffinityStaticChecker: {dirIRQAffinityStaticChecker},
-		FstrimChecker:                 {NewFstrimChecker()},
-		SynBacklogChecker:             {netCheckersFactory.NewSynBacklogChecker()},
-		ListenBacklogChecker:          {netCheckersFactory.NewListenBacklogChecker()},
-		RfsTableEntriesChecker:        {netCheckersFactory.NewRfsTableSizeChecker()},
-		NicIRQsAffinitStaticChecker:   {netCheckersFactory.NewNicIRQAffinityStaticChecker(interfaces)},
-		NicIRQsAffinitChecker:         netCheckersFactory.NewNicIRQAffinityCheckers(interfaces, irq.Default, "all"),
-		NicRpsChecker:                 netCheckersFactory.NewNicRpsSetCheckers(interfaces, irq.Default, "all"),
-		NicRfsChecker:                 netCheckersFactory.NewNicRfsCheckers(interfaces),
-		NicXpsChecker:                 netCheckersFactory.NewNicXpsCheckers(interfaces),
-		MaxAIOEvents:                  {NewMaxAIOEventsChecker(fs)},
-		ClockSource:                   {NewClockSourceChecker(fs)},
-		Swappiness:                    {NewSwappinessChecker(fs)},
-		KernelVersion:                 {NewKernelVersionChecker(GetKernelVersion)},
+		ConfigFileChecker:                 {NewConfigChecker(config)},
+		IoConfigFileChecker:               {NewIOConfigFileExistanceChecker(fs, ioConfigFile)},
+		FreeMemChecker:                    {NewMemoryChecker(fs)},
+		SwapChecker:                       {NewSwapChecker(fs)},
+		DataDirAccessChecker:              {NewDataDirWritableChecker(fs, config.Redpanda.Directory)},
+		DiskSpaceChecker:                  {NewFreeDiskSpaceChecker(config.Redpanda.Directory)},
+		FsTypeChecker:                     {NewFilesystemTypeChecker(config.Redpanda.Directory)},
+		TransparentHugePagesChecker:       {NewTransparentHugePagesChecker(fs, thpEnabled)},
+		TransparentHugePagesDefragChecker: {NewTransparentHugePagesDefragChecker(fs, thpDefrag)},
+		NtpChecker:                        {NewNTPSyncChecker(timeout, fs)},
+		SchedulerChecker:                  {schedulerChecker},
+		NomergesChecker:                   {nomergesChecker},
+		DiskIRQsAffinityChecker:           {dirIRQAffinityChecker},
+		DiskIRQsAffinityStaticChecker:     {dirIRQAffinityStaticChecker},
+		DiskIRQsSpreadChecker:             {dirIRQsSpreadChecker},
+		FstrimChecker:                     {NewFstrimChecker()},
+		SynBacklogChecker:                 {netCheckersFactory.NewSynBacklogChecker()},
+		ListenBacklogChecker:              {netCheckersFactory.NewListenBacklogChecker()},
+		RfsTableEntriesChecker:            {netCheckersFactory.NewRfsTableSizeChecker()},
+		NicIRQsAffinitStaticChecker:       {netCheckersFactory.NewNicIRQAffinityStaticChecker(interfaces)},
+		NicIRQsAffinitChecker:             netCheckersFactory.NewNicIRQAffinityCheckers(interfaces, irq.Default, "all"),
+		NicRpsChecker:                     netCheckersFactory.NewNicRpsSetCheckers(interfaces, irq.Default, "all"),
+		NicRfsChecker:                     netCheckersFactory.NewNicRfsCheckers(interfaces),
+		NicXpsChecker:                     netCheckersFactory.NewNicXpsCheckers(interfaces),
+		MaxAIOEvents:                      {NewMaxAIOEventsChecker(fs)},
+		ClockSource:                       {NewClockSourceChecker(fs)},
+		Swappiness:                        {NewSwappinessChecker(fs, SwappinessTarget(config.Rpk))},
+		KernelVersion:                     {NewKernelVersionChecker(GetKernelVersion)},
 	}
 
 	v, err := cloud.AvailableVendor()
@@ -12,6 +12,7 @@
 package tuners_test
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 	"time"
@@ -103,7 +104,7 @@ func setUpCgroupsV2(fs afero.Fs, file, val string) error {
 func TestNtpCheckTimeout(t *testing.T) {
 	timeout := time.Duration(0)
 	check := tuners.NewNTPSyncChecker(timeout, afero.NewMemMapFs())
-	res := check.Check()
+	res := check.Check(context.Background())
 	require.False(t, res.IsOk, "the NTP check shouldn't have succeeded")
 	require.Error(t, res.Err, "the NTP check should have failed with an error")
 }
@@ -203,7 +204,7 @@ func TestFreeMemoryChecker(t *testing.T) {
 				require.NoError(t, err)
 			}
 			c := tuners.NewMemoryChecker(fs)
-			res := c.Check()
+			res := c.Check(context.Background())
 			if tt.expectedErr != "" {
 				require.EqualError(t, res.Err, tt.expectedErr)
 				return
@@ -213,3 +214,25 @@ func TestFreeMemoryChecker(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckerIDString(t *testing.T) {
+	seen := map[string]bool{}
+	for id := tuners.CheckerID(tuners.ConfigFileChecker); id <= tuners.TransparentHugePagesDefragChecker; id++ {
+		name := id.String()
+		require.NotContains(t, name, "checker_", "checker %d has no name", int(id))
+		require.False(t, seen[name], "%s is the name of several checkers", name)
+		seen[name] = true
+		byName, ok := tuners.CheckerIDByName(name)
+		require.True(t, ok)
+		require.Equal(t, id, byName)
+	}
+	require.Equal(t, "checker_1000", tuners.CheckerID(1000).String())
+	tuner, ok := tuners.CheckerID(tuners.DiskIRQsSpreadChecker).Tuner()
+	require.True(t, ok)
+	require.Equal(t, "disk_irq", tuner)
+	_, ok = tuners.CheckerID(tuners.ConfigFileChecker).Tuner()
+	require.False(t, ok)
+	require.Len(t, tuners.CheckerNames(), len(seen))
+	_, ok = tuners.CheckerIDByName("checker_1000")
+	require.False(t, ok)
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tuners
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+)
+
+// TunerState has, for each tuner, the state of the settings it changed from
+// before it first changed them, in the order that it changed them.
+type TunerState map[string][]commands.State
+
+// Record adds the states of the settings that the tuner changed. The states
+// of the settings that it already changed before are kept, so that the
+// original state is restored even if the tuner ran several times.
+func (s TunerState) Record(tuner string, states []commands.State) {
+	for _, state := range states {
+		recorded := false
+		for _, r := range s[tuner] {
+			if r.Kind == state.Kind && r.Setting == state.Setting {
+				recorded = true
+				break
+			}
+		}
+		if !recorded {
+			s[tuner] = append(s[tuner], state)
+		}
+	}
+}
+
+// ReadTunerState reads the tuner state saved at path, which is empty if the
+// file doesn't exist.
+func ReadTunerState(fs afero.Fs, path string) (TunerState, error) {
+	content, err := afero.ReadFile(fs, path)
+	if os.IsNotExist(err) {
+		return TunerState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := TunerState{}
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the tuner state in %s: %v", path, err)
+	}
+	return state, nil
+}
+
+// WriteTunerState saves the tuner state at path, removing the file if the
+// state is empty.
+func WriteTunerState(fs afero.Fs, path string, state TunerState) error {
+	if len(state) == 0 {
+		err := fs.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = fs.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, path, append(content, '\n'), 0644)
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 Vectorized, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package tuners_test
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+)
+
+func TestTunerStateRecordsTheOriginalState(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, tuners.File, []byte("60\n"), 0644))
+	executor := executors.NewRecordingExecutor()
+	state := tuners.TunerState{}
+
+	// Running the tuner twice keeps the value from before the first run.
+	for _, value := range []string{"30", "1"} {
+		err := executor.Execute(commands.NewWriteFileCmd(fs, tuners.File, value))
+		require.NoError(t, err)
+		state.Record("swappiness", executor.States())
+	}
+	require.Equal(t, tuners.TunerState{
+		"swappiness": {{
+			Kind:    commands.FileState,
+			Setting: tuners.File,
+			Value:   "60\n",
+		}},
+	}, state)
+	content, err := afero.ReadFile(fs, tuners.File)
+	require.NoError(t, err)
+	require.Equal(t, "1", string(content))
+}
+
+func TestTunerStateReadWrite(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	path := "/etc/redpanda/tuner-state.json"
+
+	state, err := tuners.ReadTunerState(fs, path)
+	require.NoError(t, err)
+	require.Empty(t, state)
+
+	state.Record("transparent_hugepages", []commands.State{{
+		Kind:    commands.FileState,
+		Setting: "/sys/kernel/mm/transparent_hugepage/enabled",
+		Value:   "madvise",
+	}, {
+		Kind:    commands.FileState,
+		Setting: "/etc/thp",
+		Missing: true,
+	}})
+	require.NoError(t, tuners.WriteTunerState(fs, path, state))
+	read, err := tuners.ReadTunerState(fs, path)
+	require.NoError(t, err)
+	require.Equal(t, state, read)
+
+	// Writing an empty state removes the file.
+	require.NoError(t, tuners.WriteTunerState(fs, path, tuners.TunerState{}))
+	exists, err := afero.Exists(fs, path)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
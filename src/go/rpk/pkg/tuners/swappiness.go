@@ -10,52 +10,94 @@
 package tuners
 
 import (
+	"context"
 	"fmt"
-	"strconv"
-	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/utils"
 )
 
 const (
-	File               string = "/proc/sys/vm/swappiness"
-	ExpectedSwappiness int    = 1
+	File string = "/proc/sys/vm/swappiness"
+	// DefaultSwappiness is the value that vm.swappiness is set to unless
+	// rpk.swappiness says otherwise.
+	DefaultSwappiness int = 1
+
+	swapsFile = "/proc/swaps"
 )
 
-func NewSwappinessChecker(fs afero.Fs) Checker {
-	return NewEqualityChecker(
+// SwappinessTarget returns the value that vm.swappiness should be set to per
+// the rpk config.
+func SwappinessTarget(conf config.RpkConfig) int {
+	if conf.Swappiness != nil {
+		return *conf.Swappiness
+	}
+	return DefaultSwappiness
+}
+
+// SwapEnabled returns whether there is any swap space in use, per
+// /proc/swaps, whose first line is a header.
+func SwapEnabled(fs afero.Fs) (bool, error) {
+	lines, err := utils.ReadFileLines(fs, swapsFile)
+	if err != nil {
+		return false, err
+	}
+	return len(lines) > 1, nil
+}
+
+// NewSwappinessChecker warns if vm.swappiness is higher than the target,
+// unless there's no swap, in which case its value doesn't matter.
+func NewSwappinessChecker(fs afero.Fs, target int) Checker {
+	return NewIntChecker(
 		Swappiness,
 		"Swappiness",
 		Warning,
-		ExpectedSwappiness,
-		func() (interface{}, error) {
-			content, err := afero.ReadFile(fs, File)
-			if err != nil {
-				return -1, err
+		func(current int) bool {
+			if current <= target {
+				return true
 			}
-			return strconv.Atoi(strings.TrimSpace(string(content)))
+			enabled, err := SwapEnabled(fs)
+			return err == nil && !enabled
+		},
+		func() string {
+			return fmt.Sprintf("<= %d", target)
+		},
+		func(context.Context) (int, error) {
+			return utils.ReadIntFromFile(fs, File)
 		},
 	)
 }
 
-func NewSwappinessTuner(fs afero.Fs, executor executors.Executor) Tunable {
+// NewSwappinessTuner sets vm.swappiness to target. It isn't supported on hosts
+// with no swap, where there is nothing to tune.
+func NewSwappinessTuner(
+	fs afero.Fs, target int, executor executors.Executor,
+) Tunable {
 	return NewCheckedTunable(
-		NewSwappinessChecker(fs),
+		NewSwappinessChecker(fs, target),
 		func() TuneResult {
-			log.Debugf("Setting swappiness to %d", ExpectedSwappiness)
+			log.Debugf("Setting swappiness to %d", target)
 			err := executor.Execute(
 				commands.NewWriteFileCmd(
-					fs, File, fmt.Sprint(ExpectedSwappiness)))
+					fs, File, fmt.Sprint(target)))
 			if err != nil {
-				log.Errorf("got an error while writing %d to %s: %v", ExpectedSwappiness, File, err)
+				log.Errorf("got an error while writing %d to %s: %v", target, File, err)
 				return NewTuneError(err)
 			}
 			return NewTuneResult(false)
 		},
 		func() (bool, string) {
+			enabled, err := SwapEnabled(fs)
+			if err != nil {
+				return false, fmt.Sprintf("unable to check if swap is enabled: %v", err)
+			}
+			if !enabled {
+				return false, "No swap is enabled, so there's no swappiness to tune"
+			}
 			return true, ""
 		},
 		executor.IsLazy(),
@@ -10,49 +10,69 @@
 package tuners_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/utils"
 )
 
+const (
+	swapsHeader = "Filename\tType\tSize\tUsed\tPriority\n"
+	swaps       = swapsHeader + "/swapfile\tfile\t2097148\t0\t-2\n"
+)
+
+func writeSwappiness(swappiness, swapsContent string) func(afero.Fs) error {
+	return func(fs afero.Fs) error {
+		_, err := utils.WriteBytes(fs, []byte(swappiness), tuners.File)
+		if err != nil {
+			return err
+		}
+		_, err = utils.WriteBytes(fs, []byte(swapsContent), "/proc/swaps")
+		return err
+	}
+}
+
 func TestChecker(t *testing.T) {
 	tests := []struct {
 		name      string
 		before    func(fs afero.Fs) error
+		target    int
 		expectOk  bool
 		expectErr bool
 	}{
 		{
-			name: "It should return true if the value is correct",
-			before: func(fs afero.Fs) error {
-				_, err := utils.WriteBytes(
-					fs,
-					[]byte(fmt.Sprint(tuners.ExpectedSwappiness)),
-					tuners.File,
-				)
-				return err
-			},
+			name:     "It should return true if the value is correct",
+			before:   writeSwappiness(fmt.Sprint(tuners.DefaultSwappiness), swaps),
+			target:   tuners.DefaultSwappiness,
 			expectOk: true,
 		},
 		{
-			name: "It should return false if the file exists but the value iswrong",
-			before: func(fs afero.Fs) error {
-				_, err := utils.WriteBytes(
-					fs,
-					[]byte("120"),
-					tuners.File,
-				)
-				return err
-			},
+			name:     "It should return true if the value is lower than the target",
+			before:   writeSwappiness("0", swaps),
+			target:   10,
+			expectOk: true,
+		},
+		{
+			name:     "It should return false if the file exists but the value is too high",
+			before:   writeSwappiness("120", swaps),
+			target:   tuners.DefaultSwappiness,
 			expectOk: false,
 		},
+		{
+			name:     "It should return true if the value is high but there is no swap",
+			before:   writeSwappiness("60", swapsHeader),
+			target:   tuners.DefaultSwappiness,
+			expectOk: true,
+		},
 		{
 			name:      "It should fail if the file doesn't exist",
+			target:    tuners.DefaultSwappiness,
 			expectOk:  false,
 			expectErr: true,
 		},
@@ -64,8 +84,8 @@ func TestChecker(t *testing.T) {
 				err := tt.before(fs)
 				require.NoError(t, err)
 			}
-			checker := tuners.NewSwappinessChecker(fs)
-			res := checker.Check()
+			checker := tuners.NewSwappinessChecker(fs, tt.target)
+			res := checker.Check(context.Background())
 			if tt.expectErr {
 				require.Error(t, res.Err)
 				return
@@ -80,32 +100,31 @@ func TestTuner(t *testing.T) {
 	tests := []struct {
 		name      string
 		before    func(fs afero.Fs) error
+		target    int
+		expected  string
 		expectErr bool
 	}{
 		{
-			name: "It should leave the same value if it was correct",
-			before: func(fs afero.Fs) error {
-				_, err := utils.WriteBytes(
-					fs,
-					[]byte(fmt.Sprint(tuners.ExpectedSwappiness)),
-					tuners.File,
-				)
-				return err
-			},
+			name:     "It should leave the same value if it was correct",
+			before:   writeSwappiness(fmt.Sprint(tuners.DefaultSwappiness), swaps),
+			target:   tuners.DefaultSwappiness,
+			expected: fmt.Sprint(tuners.DefaultSwappiness),
 		},
 		{
-			name: "It should change the value if it was different",
-			before: func(fs afero.Fs) error {
-				_, err := utils.WriteBytes(
-					fs,
-					[]byte("120"),
-					tuners.File,
-				)
-				return err
-			},
+			name:     "It should change the value if it was different",
+			before:   writeSwappiness("120", swaps),
+			target:   tuners.DefaultSwappiness,
+			expected: fmt.Sprint(tuners.DefaultSwappiness),
+		},
+		{
+			name:     "It should set the configured target",
+			before:   writeSwappiness("60", swaps),
+			target:   10,
+			expected: "10",
 		},
 		{
 			name:      "It should fail if the file doesn't exist",
+			target:    tuners.DefaultSwappiness,
 			expectErr: true,
 		},
 	}
@@ -116,7 +135,7 @@ func TestTuner(t *testing.T) {
 				err := tt.before(fs)
 				require.NoError(t, err)
 			}
-			tuner := tuners.NewSwappinessTuner(fs, executors.NewDirectExecutor())
+			tuner := tuners.NewSwappinessTuner(fs, tt.target, executors.NewDirectExecutor())
 			res := tuner.Tune()
 			if tt.expectErr {
 				require.Error(t, res.Error())
@@ -126,7 +145,28 @@ func TestTuner(t *testing.T) {
 			lines, err := utils.ReadFileLines(fs, tuners.File)
 			require.NoError(t, err)
 			require.Len(t, lines, 1)
-			require.Equal(t, fmt.Sprint(tuners.ExpectedSwappiness), lines[0])
+			require.Equal(t, tt.expected, lines[0])
 		})
 	}
 }
+
+func TestTunerIsNotSupportedWithoutSwap(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, writeSwappiness("60", swapsHeader)(fs))
+	tuner := tuners.NewSwappinessTuner(fs, tuners.DefaultSwappiness, executors.NewDirectExecutor())
+	supported, reason := tuner.CheckIfSupported()
+	require.False(t, supported)
+	require.Equal(t, "No swap is enabled, so there's no swappiness to tune", reason)
+
+	require.NoError(t, writeSwappiness("60", swaps)(fs))
+	supported, _ = tuner.CheckIfSupported()
+	require.True(t, supported)
+}
+
+func TestSwappinessTarget(t *testing.T) {
+	conf := config.Default()
+	require.Equal(t, tuners.DefaultSwappiness, tuners.SwappinessTarget(conf.Rpk))
+	ten := 10
+	conf.Rpk.Swappiness = &ten
+	require.Equal(t, 10, tuners.SwappinessTarget(conf.Rpk))
+}
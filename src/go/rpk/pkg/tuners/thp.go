@@ -10,21 +10,50 @@
 package tuners
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/system"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors/commands"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/utils"
 )
 
-const enabledFile = "enabled"
+const (
+	enabledFile = "enabled"
+	defragFile  = "defrag"
+
+	// DefaultTHPEnabled and DefaultTHPDefrag are the values that the THP
+	// enabled and defrag files are set to unless the rpk config says
+	// otherwise.
+	DefaultTHPEnabled = "madvise"
+	DefaultTHPDefrag  = "never"
+)
+
+// THPSettings returns the values that the THP enabled and defrag files should
+// be set to per the rpk config.
+func THPSettings(conf config.RpkConfig) (enabled, defrag string) {
+	enabled, defrag = DefaultTHPEnabled, DefaultTHPDefrag
+	if conf.TransparentHugePagesEnabled != "" {
+		enabled = conf.TransparentHugePagesEnabled
+	}
+	if conf.TransparentHugePagesDefrag != "" {
+		defrag = conf.TransparentHugePagesDefrag
+	}
+	return enabled, defrag
+}
 
 type thpTuner struct {
 	fs       afero.Fs
+	enabled  string
+	defrag   string
 	executor executors.Executor
 }
 
@@ -54,10 +83,18 @@ func getTHPDir(fs afero.Fs) (string, error) {
 }
 
 /*
-/ Create a new tuner to enable Transparent Huge Pages
+/ Create a new tuner that sets the Transparent Huge Pages enabled and defrag
+/ modes.
 */
-func NewEnableTHPTuner(fs afero.Fs, executor executors.Executor) Tunable {
-	return &thpTuner{fs: fs, executor: executor}
+func NewTHPTuner(
+	fs afero.Fs, enabled, defrag string, executor executors.Executor,
+) Tunable {
+	return &thpTuner{
+		fs:       fs,
+		enabled:  enabled,
+		defrag:   defrag,
+		executor: executor,
+	}
 }
 
 func (t *thpTuner) CheckIfSupported() (bool, string) {
@@ -73,30 +110,106 @@ func (t *thpTuner) Tune() TuneResult {
 	if err != nil {
 		return NewTuneError(err)
 	}
-	// Write 'always' to the 'enabled' file in the existing THP dir so that
-	// they're always enabled.
 	// https://www.kernel.org/doc/Documentation/vm/transhuge.txt
-
-	cmd := commands.NewWriteFileCmd(
-		t.fs,
-		filepath.Join(dir, enabledFile),
-		"always",
-	)
-	err = t.executor.Execute(cmd)
-	if err != nil {
-		return NewTuneError(err)
+	for _, s := range []struct{ file, value string }{
+		{enabledFile, t.enabled},
+		{defragFile, t.defrag},
+	} {
+		path := filepath.Join(dir, s.file)
+		exists, err := afero.Exists(t.fs, path)
+		if err != nil {
+			return NewTuneError(err)
+		}
+		// Older kernels don't have all the files.
+		if !exists {
+			log.Infof("'%s' doesn't exist, skipping it", path)
+			continue
+		}
+		opts, err := system.ReadRuntineOptions(t.fs, path)
+		if err != nil {
+			return NewTuneError(err)
+		}
+		if opts.GetActive() == s.value {
+			log.Debugf("'%s' is already set to '%s'", path, s.value)
+			continue
+		}
+		available := opts.GetAvailable()
+		sort.Strings(available)
+		if !utils.StringInSlice(s.value, available) {
+			return NewTuneError(fmt.Errorf(
+				"'%s' can't be set to '%s', it only accepts %s",
+				path,
+				s.value,
+				strings.Join(available, ", "),
+			))
+		}
+		err = t.executor.Execute(commands.NewWriteFileCmd(t.fs, path, s.value))
+		if err != nil {
+			return NewTuneError(err)
+		}
 	}
 	return NewTuneResult(false)
 }
 
-func NewTransparentHugePagesChecker(fs afero.Fs) Checker {
-	return NewEqualityChecker(
-		TransparentHugePagesChecker,
-		"Transparent huge pages active",
-		Warning,
-		true,
-		func() (interface{}, error) {
-			return system.GetTransparentHugePagesActive(fs)
-		},
-	)
+// NewTransparentHugePagesChecker checks that the THP enabled mode is the
+// given one.
+func NewTransparentHugePagesChecker(fs afero.Fs, enabled string) Checker {
+	return newTHPChecker(fs, TransparentHugePagesChecker, "Transparent huge pages enabled", enabledFile, enabled)
+}
+
+// NewTransparentHugePagesDefragChecker checks that the THP defrag mode is the
+// given one.
+func NewTransparentHugePagesDefragChecker(fs afero.Fs, defrag string) Checker {
+	return newTHPChecker(fs, TransparentHugePagesDefragChecker, "Transparent huge pages defrag", defragFile, defrag)
+}
+
+func newTHPChecker(fs afero.Fs, id CheckerID, desc, file, required string) Checker {
+	return &thpChecker{
+		Checker: NewEqualityChecker(
+			id,
+			desc,
+			Warning,
+			required,
+			func(context.Context) (interface{}, error) {
+				dir, err := getTHPDir(fs)
+				if err != nil {
+					return "", err
+				}
+				opts, err := system.ReadRuntineOptions(fs, filepath.Join(dir, file))
+				if err != nil {
+					return "", err
+				}
+				return opts.GetActive(), nil
+			},
+		),
+		fs:   fs,
+		file: file,
+	}
+}
+
+// thpChecker passes if the kernel has THP but not the checked file, which
+// older kernels don't have for defrag, since the tuner skips it too.
+type thpChecker struct {
+	Checker
+	fs   afero.Fs
+	file string
+}
+
+func (c *thpChecker) Check(ctx context.Context) *CheckResult {
+	dir, err := getTHPDir(c.fs)
+	if err != nil {
+		return c.Checker.Check(ctx)
+	}
+	exists, err := afero.Exists(c.fs, filepath.Join(dir, c.file))
+	if err != nil || exists {
+		return c.Checker.Check(ctx)
+	}
+	return &CheckResult{
+		CheckerId: c.Id(),
+		IsOk:      true,
+		Current:   "unsupported by the kernel",
+		Desc:      c.GetDesc(),
+		Severity:  c.GetSeverity(),
+		Required:  c.GetRequiredAsString(),
+	}
 }
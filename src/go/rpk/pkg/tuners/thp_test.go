@@ -10,11 +10,13 @@
 package tuners_test
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
+	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/config"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners"
 	"github.com/vectorizedio/redpanda/src/go/rpk/pkg/tuners/executors"
 )
@@ -52,7 +54,7 @@ func TestTHPTunerSupported(t *testing.T) {
 				require.NoError(st, err)
 			}
 			exec := executors.NewDirectExecutor()
-			tuner := tuners.NewEnableTHPTuner(fs, exec)
+			tuner := tuners.NewTHPTuner(fs, "madvise", "never", exec)
 			supported, reason := tuner.CheckIfSupported()
 			require.Equal(st, tt.expected, supported)
 			require.Equal(st, tt.expectedReason, reason)
@@ -60,6 +62,20 @@ func TestTHPTunerSupported(t *testing.T) {
 	}
 }
 
+const thpDir = "/sys/kernel/mm/transparent_hugepage"
+
+// writeTHPFiles writes the enabled file, and the defrag file if its contents
+// aren't empty, as the kernel shows them.
+func writeTHPFiles(t *testing.T, fs afero.Fs, enabled, defrag string) {
+	require.NoError(t, fs.MkdirAll(thpDir, 0755))
+	err := afero.WriteFile(fs, filepath.Join(thpDir, "enabled"), []byte(enabled), 0644)
+	require.NoError(t, err)
+	if defrag != "" {
+		err = afero.WriteFile(fs, filepath.Join(thpDir, "defrag"), []byte(defrag), 0644)
+		require.NoError(t, err)
+	}
+}
+
 func TestTHPTunerScriptExecutor(t *testing.T) {
 	expected := `#!/bin/bash
 
@@ -67,19 +83,15 @@ func TestTHPTunerScriptExecutor(t *testing.T) {
 # ----------------------------------
 # This file was autogenerated by RPK
 
-echo 'always' > /sys/kernel/mm/transparent_hugepage/enabled
+echo 'madvise' > /sys/kernel/mm/transparent_hugepage/enabled
+echo 'never' > /sys/kernel/mm/transparent_hugepage/defrag
 `
 	fs := afero.NewMemMapFs()
 	scriptFileName := "script.sh"
 	exec := executors.NewScriptRenderingExecutor(fs, scriptFileName)
-	dir := "/sys/kernel/mm/transparent_hugepage"
-	err := fs.MkdirAll(dir, 0755)
-	require.NoError(t, err)
-
-	_, err = fs.Create(filepath.Join(dir, "enabled"))
-	require.NoError(t, err)
+	writeTHPFiles(t, fs, "[always] madvise never\n", "[always] defer defer+madvise madvise never\n")
 
-	tuner := tuners.NewEnableTHPTuner(fs, exec)
+	tuner := tuners.NewTHPTuner(fs, "madvise", "never", exec)
 
 	res := tuner.Tune()
 	require.False(t, res.IsFailed())
@@ -91,79 +103,146 @@ echo 'always' > /sys/kernel/mm/transparent_hugepage/enabled
 }
 
 func TestTHPTunerDirectExecutor(t *testing.T) {
-	// The file is on sysfs, and when printed, its contents are printed
-	// differently, showing the valid options and the chosen option wrapped
-	// in brackets, so in reality it would look like this:
-	//
-	// [always] madvise never
-	//
-	// This expected value is meant only for this test, which uses an
-	// fs.MemMapFs
-	expected := "always"
-	fs := afero.NewMemMapFs()
-	exec := executors.NewDirectExecutor()
-	dir := "/sys/kernel/mm/transparent_hugepage"
-	filePath := filepath.Join(dir, "enabled")
-	err := fs.MkdirAll(dir, 0755)
-	require.NoError(t, err)
-
-	_, err = fs.Create(filePath)
-	require.NoError(t, err)
-
-	tuner := tuners.NewEnableTHPTuner(fs, exec)
-
-	res := tuner.Tune()
-	require.False(t, res.IsFailed())
-	require.False(t, res.IsRebootRequired())
-
-	bs, err := afero.ReadFile(fs, filePath)
-	require.NoError(t, err)
+	// The files are on sysfs, and when printed, their contents show the
+	// valid options and the chosen option wrapped in brackets. In an
+	// fs.MemMapFs they contain whatever was written last.
+	tests := []struct {
+		name           string
+		enabled        string
+		defrag         string
+		expEnabled     string
+		expDefrag      string
+		expectedErrMsg string
+	}{
+		{
+			name:       "it should set both modes",
+			enabled:    "[always] madvise never",
+			defrag:     "always defer defer+madvise [madvise] never",
+			expEnabled: "madvise",
+			expDefrag:  "never",
+		},
+		{
+			name:       "it should leave the modes that are already set",
+			enabled:    "always [madvise] never",
+			defrag:     "always defer defer+madvise [madvise] never",
+			expEnabled: "always [madvise] never",
+			expDefrag:  "never",
+		},
+		{
+			name:       "it should only set the enabled mode if there's no defrag file",
+			enabled:    "[always] madvise never",
+			expEnabled: "madvise",
+		},
+		{
+			name:           "it should fail if the kernel doesn't accept the mode",
+			enabled:        "[always] madvise never",
+			defrag:         "[always] madvise never",
+			expEnabled:     "madvise",
+			expectedErrMsg: "'/sys/kernel/mm/transparent_hugepage/defrag' can't be set to 'defer+madvise', it only accepts always, madvise, never",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			writeTHPFiles(t, fs, tt.enabled, tt.defrag)
+			defrag := "never"
+			if tt.expectedErrMsg != "" {
+				defrag = "defer+madvise"
+			}
+			tuner := tuners.NewTHPTuner(fs, "madvise", defrag, executors.NewDirectExecutor())
+
+			res := tuner.Tune()
+			if tt.expectedErrMsg != "" {
+				require.EqualError(t, res.Error(), tt.expectedErrMsg)
+			} else {
+				require.False(t, res.IsFailed())
+				require.False(t, res.IsRebootRequired())
+			}
 
-	require.Equal(t, expected, string(bs))
+			bs, err := afero.ReadFile(fs, filepath.Join(thpDir, "enabled"))
+			require.NoError(t, err)
+			require.Equal(t, tt.expEnabled, string(bs))
+			if tt.expDefrag != "" {
+				bs, err = afero.ReadFile(fs, filepath.Join(thpDir, "defrag"))
+				require.NoError(t, err)
+				require.Equal(t, tt.expDefrag, string(bs))
+			}
+		})
+	}
 }
 
 func TestTHPCheckID(t *testing.T) {
-	c := tuners.NewTransparentHugePagesChecker(afero.NewMemMapFs())
+	c := tuners.NewTransparentHugePagesChecker(afero.NewMemMapFs(), "madvise")
 	require.Equal(t, tuners.CheckerID(tuners.TransparentHugePagesChecker), c.Id())
+	c = tuners.NewTransparentHugePagesDefragChecker(afero.NewMemMapFs(), "never")
+	require.Equal(t, tuners.CheckerID(tuners.TransparentHugePagesDefragChecker), c.Id())
 }
 
 func TestTHPCheck(t *testing.T) {
 	tests := []struct {
-		name     string
-		contents string
-		expected bool
+		name      string
+		enabled   string
+		defrag    string
+		expEnable bool
+		expDefrag bool
 	}{
 		{
-			name:     "should return true if the active value is 'always'",
-			contents: "[always] madvise never",
-			expected: true,
+			name:      "should return true if the active modes are the required ones",
+			enabled:   "always [madvise] never",
+			defrag:    "always defer defer+madvise madvise [never]",
+			expEnable: true,
+			expDefrag: true,
 		},
 		{
-			name:     "should return true if the active value is 'madvise'",
-			contents: "always [madvise] never",
-			expected: true,
+			name:      "should return false if the active enabled mode is 'always'",
+			enabled:   "[always] madvise never",
+			defrag:    "always defer defer+madvise madvise [never]",
+			expDefrag: true,
 		},
 		{
-			name:     "should return false if the active value is 'never'",
-			contents: "always madvise [never]",
-			expected: false,
+			name:      "should return false if the active defrag mode is 'always'",
+			enabled:   "always [madvise] never",
+			defrag:    "[always] defer defer+madvise madvise never",
+			expEnable: true,
 		},
 	}
 	for _, tt := range tests {
-		t.Run(tt.name, func(st *testing.T) {
+		t.Run(tt.name, func(t *testing.T) {
 			fs := afero.NewMemMapFs()
-			dir := "/sys/kernel/mm/transparent_hugepage"
-			err := fs.MkdirAll(dir, 0755)
-			require.NoError(t, err)
-
-			f, err := fs.Create(filepath.Join(dir, "enabled"))
-			require.NoError(t, err)
-			defer f.Close()
-			_, err = f.Write([]byte(tt.contents))
-			require.NoError(t, err)
-			c := tuners.NewTransparentHugePagesChecker(fs)
-			res := c.Check()
-			require.Equal(t, tt.expected, res.IsOk)
+			writeTHPFiles(t, fs, tt.enabled, tt.defrag)
+			res := tuners.NewTransparentHugePagesChecker(fs, "madvise").Check(context.Background())
+			require.NoError(t, res.Err)
+			require.Equal(t, tt.expEnable, res.IsOk)
+			res = tuners.NewTransparentHugePagesDefragChecker(fs, "never").Check(context.Background())
+			require.NoError(t, res.Err)
+			require.Equal(t, tt.expDefrag, res.IsOk)
 		})
 	}
 }
+
+func TestTHPCheckWithoutDefrag(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeTHPFiles(t, fs, "always [madvise] never", "")
+	res := tuners.NewTransparentHugePagesDefragChecker(fs, "never").Check(context.Background())
+	require.NoError(t, res.Err)
+	require.True(t, res.IsOk, "a kernel without THP defrag should pass, as the tuner skips it")
+}
+
+func TestTHPCheckWithoutTHP(t *testing.T) {
+	res := tuners.NewTransparentHugePagesChecker(afero.NewMemMapFs(), "madvise").Check(context.Background())
+	require.Error(t, res.Err)
+	require.False(t, res.IsOk)
+}
+
+func TestTHPSettings(t *testing.T) {
+	conf := config.Default()
+	enabled, defrag := tuners.THPSettings(conf.Rpk)
+	require.Equal(t, tuners.DefaultTHPEnabled, enabled)
+	require.Equal(t, tuners.DefaultTHPDefrag, defrag)
+
+	conf.Rpk.TransparentHugePagesEnabled = "never"
+	conf.Rpk.TransparentHugePagesDefrag = "defer"
+	enabled, defrag = tuners.THPSettings(conf.Rpk)
+	require.Equal(t, "never", enabled)
+	require.Equal(t, "defer", defrag)
+}